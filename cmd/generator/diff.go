@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/diff"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <before> <after>",
+	Short: "Output the packages added, removed and changed between two generated documents",
+	Long:  "Output the packages added, removed and changed between two generated documents, JSON or tag value formatted",
+	Args:  cobra.ExactArgs(2),
+	Run:   compareDocuments,
+}
+
+func init() {
+	diffCmd.Flags().StringP("format", "f", "text", "diff output format, text or json (default: text)")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func compareDocuments(cmd *cobra.Command, args []string) {
+	before, err := diff.LoadDocument(args[0])
+	if err != nil {
+		log.Fatalf("Failed to read document %s: %v", args[0], err)
+	}
+	after, err := diff.LoadDocument(args[1])
+	if err != nil {
+		log.Fatalf("Failed to read document %s: %v", args[1], err)
+	}
+
+	report := diff.Documents(before, after)
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	switch strings.ToLower(format) {
+	case "json":
+		output, err := report.JSON()
+		if err != nil {
+			log.Fatalf("Failed to render diff: %v", err)
+		}
+		fmt.Println(string(output))
+	case "text":
+		fmt.Print(report.Text())
+	default:
+		log.Fatalf("Unsupported diff format %s", format)
+	}
+}
@@ -3,14 +3,19 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"os"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/blocklist"
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
 	"github.com/spdx/spdx-sbom-generator/pkg/handler"
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
@@ -43,9 +48,34 @@ func main() {
 func init() {
 	rootCmd.Flags().StringP("path", "p", ".", "the path to package file or the path to a directory which will be recursively analyzed for the package files (default '.')")
 	rootCmd.Flags().BoolP("include-license-text", "i", false, " Include full license text (default: false)")
-	rootCmd.Flags().StringP("schema", "s", "2.2", "<version> Target schema version (default: '2.2')")
-	rootCmd.Flags().StringP("output-dir", "o", ".", "<output> directory to Write SPDX to file (default: current directory)")
-	rootCmd.Flags().StringP("format", "f", "spdx", "output file format (default: spdx)")
+	rootCmd.Flags().StringP("schema", "s", "2.3", "<version> Target schema version, 2.2 or 2.3 (default: '2.3')")
+	rootCmd.Flags().StringP("output-dir", "o", ".", "<output> directory to Write SPDX to file, - to write the documents to stdout (default: current directory)")
+	rootCmd.Flags().Bool("format-header", false, "precede each document written to stdout with its Content-Type, e.g. application/spdx+json, for consumers detecting the format (default: false)")
+	rootCmd.Flags().StringP("format", "f", "spdx", "output file format, spdx, json or inventory (default: spdx)")
+	rootCmd.Flags().Bool("trim-noassertion", false, "omit the optional package fields valued NOASSERTION from the json documents, the ones the schema requires being kept (default: false)")
+	rootCmd.Flags().Bool("verification-code", false, "analyze the files of the project directory, writing the root package with their package verification code, the documents left out (default: false)")
+	rootCmd.Flags().Bool("vex", false, "also output bom-<package manager>.vex.json listing the packages by SPDXID, purl and CPE with placeholder VEX statuses, for VEX annotation (default: false)")
+	rootCmd.Flags().Bool("gzip", false, "write the output files gzip compressed, with a .gz suffix (default: false)")
+	rootCmd.Flags().String("source", "", "VCS url or purl of the sources the root package is generated from (default: none)")
+	rootCmd.Flags().Bool("scoped-relationships", false, "relate dependencies according to their scope, e.g. RUNTIME_DEPENDENCY_OF for runtime-only ones or TEST_DEPENDENCY_OF for test ones, instead of DEPENDS_ON (default: false)")
+	rootCmd.Flags().String("relationship-direction", "depends-on", "relate dependencies with DEPENDS_ON from the dependent, DEPENDENCY_OF from the dependency, or both: depends-on, dependency-of or both (default: depends-on)")
+	rootCmd.Flags().String("root-spdxid", "", "SPDXID of the root package the document describes, e.g. SPDXRef-RootPackage, or purl to derive it from the root package url (default: derived from the root module name)")
+	rootCmd.Flags().Int("max-packages", 0, "cap the number of packages of each document, omitting the deepest dependencies first (default: no cap)")
+	rootCmd.Flags().Bool("diagnostic-annotations", false, "embed the generation diagnostics, e.g. unresolved versions or a partial dependency tree, as JSON annotations of the document (default: false)")
+	rootCmd.Flags().String("ntia", "", "ensure the NTIA minimum elements are present and report the missing ones, report or strict to fail when a supplier or version is missing (default: not checked)")
+	rootCmd.Flags().String("author", "", "person or organization creating the documents, e.g. 'Organization: Example Inc' (default: none, the root package supplier with --ntia)")
+	rootCmd.Flags().String("external-documents", "", "JSON file mapping dependency purls or <name>@<version> to the namespace and checksum of their SBOM, referenced as external documents (default: none)")
+	rootCmd.Flags().Bool("build-environment", false, "record the package manager, runtime and OS versions used for the build in the document (default: false)")
+	rootCmd.Flags().StringToString("license-text", nil, "file holding the text of a non-standard license, as <LicenseRef id>=<file>, used when no text is found for it (default: none)")
+	rootCmd.Flags().StringSlice("allow-license", nil, "license identifiers the concluded licenses must comply with, others are reported as violations (default: all)")
+	rootCmd.Flags().StringSlice("deny-license", nil, "license identifiers reported as violations when concluded (default: none)")
+	rootCmd.Flags().Bool("fail-on-license-violation", false, "do not output the document of a package manager whose modules violate the license policy (default: false)")
+	rootCmd.Flags().String("blocklist", "", "file listing known-bad coordinates or typosquat patterns, one '<pattern> [<reason>]' per line, whose matching modules are reported as errors (default: none)")
+	rootCmd.Flags().Bool("fail-on-blocklist-match", false, "do not output the document of a package manager with modules matching the blocklist (default: false)")
+	rootCmd.Flags().Bool("declared-view", false, "also output the dependencies as declared in the manifest, to compare with the resolved ones (default: false)")
+	rootCmd.Flags().Int("parallelism", 4, "number of package managers run at the same time when several are detected, 0 to run all of them at once (default: 4)")
+	rootCmd.Flags().Bool("merge", false, "output a single bom-merged document covering the packages of all the detected package managers instead of one document per package manager (default: false)")
+	rootCmd.Flags().Bool("dry-run", false, "preview the packages declared in the manifests and the steps a generation would run, without running the package managers or writing output (default: false)")
 
 	//rootCmd.MarkFlagRequired("path")
 	cobra.OnInitialize(setupLogger)
@@ -57,6 +87,8 @@ func parseOutputFormat(formatOption string) models.OutputFormat {
 		return models.OutputFormatSpdx
 	case "json":
 		return models.OutputFormatJson
+	case "inventory":
+		return models.OutputFormatInventory
 	default:
 		return models.OutputFormatSpdx
 	}
@@ -97,19 +129,149 @@ func generate(cmd *cobra.Command, args []string) {
 	path := checkOpt("path")
 	outputDir := checkOpt("output-dir")
 	schema := checkOpt("schema")
-	format := parseOutputFormat(checkOpt("format"))
+	outputFormat := parseOutputFormat(checkOpt("format"))
+	source := checkOpt("source")
 	license, err := cmd.Flags().GetBool("include-license-text")
 	if err != nil {
 		log.Fatalf("Failed to read command option: %v", err)
 	}
+	formatHeader, err := cmd.Flags().GetBool("format-header")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	trimNoAssertion, err := cmd.Flags().GetBool("trim-noassertion")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	verificationCode, err := cmd.Flags().GetBool("verification-code")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	vex, err := cmd.Flags().GetBool("vex")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	compress, err := cmd.Flags().GetBool("gzip")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	declaredView, err := cmd.Flags().GetBool("declared-view")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	scopedRelationships, err := cmd.Flags().GetBool("scoped-relationships")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	relationshipDirection := checkOpt("relationship-direction")
+	rootSPDXID := checkOpt("root-spdxid")
+	maxPackages, err := cmd.Flags().GetInt("max-packages")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	diagnosticAnnotations, err := cmd.Flags().GetBool("diagnostic-annotations")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	ntia := checkOpt("ntia")
+	author := checkOpt("author")
+	externalDocumentsFile := checkOpt("external-documents")
+	buildEnvironment, err := cmd.Flags().GetBool("build-environment")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	licenseTextFiles, err := cmd.Flags().GetStringToString("license-text")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	allowedLicenses, err := cmd.Flags().GetStringSlice("allow-license")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	deniedLicenses, err := cmd.Flags().GetStringSlice("deny-license")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	failOnLicenseViolation, err := cmd.Flags().GetBool("fail-on-license-violation")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	blocklistFile := checkOpt("blocklist")
+	failOnBlocklistMatch, err := cmd.Flags().GetBool("fail-on-blocklist-match")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	parallelism, err := cmd.Flags().GetInt("parallelism")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	mergeDocuments, err := cmd.Flags().GetBool("merge")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		log.Fatalf("Failed to read command option: %v", err)
+	}
+	licenseTexts := map[string]string{}
+	for licenseID, file := range licenseTextFiles {
+		text, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatalf("Failed to read license text of %s: %v", licenseID, err)
+		}
+		licenseTexts[licenseID] = string(text)
+	}
+	var blocked blocklist.List
+	if blocklistFile != "" {
+		if blocked, err = blocklist.Load(blocklistFile); err != nil {
+			log.Fatalf("Failed to read blocklist: %v", err)
+		}
+	}
+	var externalDocuments map[string]format.ExternalDocument
+	if externalDocumentsFile != "" {
+		data, err := ioutil.ReadFile(externalDocumentsFile)
+		if err != nil {
+			log.Fatalf("Failed to read external documents: %v", err)
+		}
+		if err := json.Unmarshal(data, &externalDocuments); err != nil {
+			log.Fatalf("Failed to parse external documents %s: %v", externalDocumentsFile, err)
+		}
+	}
 
 	handler, err := handler.NewSPDX(handler.SPDXSettings{
-		Version:   version,
-		Path:      path,
-		License:   license,
-		OutputDir: outputDir,
-		Schema:    schema,
-		Format:    format,
+		Version:               version,
+		Path:                  path,
+		License:               license,
+		OutputDir:             outputDir,
+		Schema:                schema,
+		Format:                outputFormat,
+		Source:                source,
+		Compress:              compress,
+		FormatHeader:          formatHeader,
+		TrimNoAssertion:       trimNoAssertion,
+		VEX:                   vex,
+		VerificationCode:      verificationCode,
+		DeclaredView:          declaredView,
+		ScopedRelationships:   scopedRelationships,
+		RelationshipDirection: relationshipDirection,
+		MaxPackages:           maxPackages,
+		RootSPDXID:            rootSPDXID,
+		DiagnosticAnnotations: diagnosticAnnotations,
+		NTIA:                  ntia,
+		Author:                author,
+		ExternalDocuments:     externalDocuments,
+		BuildEnvironment:      buildEnvironment,
+		LicenseTexts:          licenseTexts,
+		LicensePolicy: licenses.Policy{
+			Allowed: allowedLicenses,
+			Denied:  deniedLicenses,
+		},
+		FailOnLicenseViolation: failOnLicenseViolation,
+		Blocklist:              blocked,
+		FailOnBlocklistMatch:   failOnBlocklistMatch,
+		Parallelism:            parallelism,
+		MergeDocuments:         mergeDocuments,
+		DryRun:                 dryRun,
 	})
 	if err != nil {
 		log.Fatalf("Failed to initialize command: %v", err)
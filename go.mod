@@ -11,4 +11,5 @@ require (
 	github.com/stretchr/testify v1.6.1
 	github.com/vifraa/gopom v0.1.0
 	golang.org/x/mod v0.4.2
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
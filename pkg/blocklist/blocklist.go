@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocklist
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// DiagnosticKnownBad is the code of the diagnostics reporting a module matching the blocklist
+const DiagnosticKnownBad = "known-bad-module"
+
+var errInvalidPattern = errors.New("invalid blocklist pattern")
+
+// Entry is a known-bad coordinate or typosquat pattern, along with why it is listed
+type Entry struct {
+	// Pattern is matched against the package url, the name, `<name>@<version>`, `<group>:<name>` and
+	// `<group>:<name>:<version>` of the modules. It may hold `*`, `?` and `[...]` wildcards, e.g. `lodahs*`
+	Pattern string
+	Reason  string
+}
+
+// List is the known-bad coordinates and typosquat patterns flagged by the lightweight supply-chain guard
+type List struct {
+	Entries []Entry
+}
+
+// Load reads a blocklist file, one `<pattern> [<reason>]` entry per line. Empty lines and `#` comments are skipped
+func Load(file string) (List, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return List{}, err
+	}
+	defer f.Close()
+
+	var list List
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		entry := Entry{Pattern: fields[0], Reason: strings.Join(fields[1:], " ")}
+		if _, err := path.Match(entry.Pattern, ""); err != nil {
+			return List{}, fmt.Errorf("%w %q at %s:%d", errInvalidPattern, entry.Pattern, file, line)
+		}
+		list.Entries = append(list.Entries, entry)
+	}
+	return list, scanner.Err()
+}
+
+// IsEmpty tells whether the list has no entries, in which case no module is flagged
+func (l List) IsEmpty() bool {
+	return len(l.Entries) == 0
+}
+
+// Check flags the modules matching an entry of the list as error diagnostics, the first matching entry being
+// reported for each module
+func (l List) Check(modules []models.Module) []models.Diagnostic {
+	var diagnostics []models.Diagnostic
+	for _, module := range modules {
+		if module.Root {
+			continue
+		}
+		for _, entry := range l.Entries {
+			if matched, ok := entry.match(module); ok {
+				message := fmt.Sprintf("%s matches the blocklist entry `%s`", matched, entry.Pattern)
+				if entry.Reason != "" {
+					message += ": " + entry.Reason
+				}
+				diagnostics = append(diagnostics, models.Diagnostic{
+					Severity: models.DiagnosticError,
+					Code:     DiagnosticKnownBad,
+					Module:   module.Name,
+					Message:  message,
+				})
+				break
+			}
+		}
+	}
+	return diagnostics
+}
+
+// match returns the coordinates of the module the entry matches, case insensitively
+func (e Entry) match(module models.Module) (string, bool) {
+	pattern := strings.ToLower(e.Pattern)
+	for _, coordinates := range moduleCoordinates(module) {
+		if matched, _ := path.Match(pattern, strings.ToLower(coordinates)); matched {
+			return coordinates, true
+		}
+	}
+	return "", false
+}
+
+// moduleCoordinates lists the ways a module can be referred to by a blocklist entry
+func moduleCoordinates(module models.Module) []string {
+	var coordinates []string
+	if strings.HasPrefix(module.PackageURL, "pkg:") {
+		coordinates = append(coordinates, module.PackageURL)
+	}
+	coordinates = append(coordinates, module.Name)
+	if module.Version != "" {
+		coordinates = append(coordinates, module.Name+"@"+module.Version)
+	}
+	if module.Group != "" {
+		coordinates = append(coordinates, module.Group+":"+module.Name)
+		if module.Version != "" {
+			coordinates = append(coordinates, module.Group+":"+module.Name+":"+module.Version)
+		}
+	}
+	return coordinates
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package blocklist
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestCheckFlagsMatchingModules(t *testing.T) {
+	list, err := Load(filepath.Join("testdata", "blocklist.txt"))
+	assert.NoError(t, err)
+	assert.Len(t, list.Entries, 4)
+
+	modules := []models.Module{
+		{Name: "app", Version: "1.0.0", Root: true},
+		{Name: "event-stream", Version: "3.3.6"},
+		{Name: "event-stream", Version: "4.0.1"},
+		{Name: "lodash", Version: "4.17.21"},
+		{Name: "Lodahs", Version: "4.17.21"},
+		{Name: "crossenv", Version: "6.1.1", PackageURL: "pkg:npm/crossenv@6.1.1"},
+		{Name: "payload", Group: "org.example.evil", Version: "1.0.0"},
+	}
+	assert.Equal(t, []models.Diagnostic{
+		{
+			Severity: models.DiagnosticError,
+			Code:     DiagnosticKnownBad,
+			Module:   "event-stream",
+			Message:  "event-stream@3.3.6 matches the blocklist entry `event-stream@3.3.6`: compromised release shipping the flatmap-stream payload",
+		},
+		{
+			Severity: models.DiagnosticError,
+			Code:     DiagnosticKnownBad,
+			Module:   "Lodahs",
+			Message:  "Lodahs matches the blocklist entry `lodahs*`: typosquat of lodash",
+		},
+		{
+			Severity: models.DiagnosticError,
+			Code:     DiagnosticKnownBad,
+			Module:   "crossenv",
+			Message:  "pkg:npm/crossenv@6.1.1 matches the blocklist entry `pkg:npm/crossenv@*`: typosquat of cross-env",
+		},
+		{
+			Severity: models.DiagnosticError,
+			Code:     DiagnosticKnownBad,
+			Module:   "payload",
+			Message:  "org.example.evil:payload matches the blocklist entry `org.example.evil:*`",
+		},
+	}, list.Check(modules))
+}
+
+func TestLoadInvalidPattern(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "blocklist.txt")
+	assert.NoError(t, ioutil.WriteFile(file, []byte("left-pad\nlodahs[\n"), 0644))
+	_, err := Load(file)
+	assert.True(t, errors.Is(err, errInvalidPattern))
+}
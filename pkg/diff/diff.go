@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Package is a package entry of a document, as compared by the diff
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	License string `json:"license,omitempty"`
+}
+
+// String returns the package name followed by its version, if any
+func (p Package) String() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return fmt.Sprintf("%s %s", p.Name, p.Version)
+}
+
+// Change is a value of a package that differs between the two documents
+type Change struct {
+	Name   string `json:"name"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Report lists the packages added, removed and changed from a document to another, sorted by name
+type Report struct {
+	Added          []Package `json:"added"`
+	Removed        []Package `json:"removed"`
+	VersionChanged []Change  `json:"versionChanged"`
+	LicenseChanged []Change  `json:"licenseChanged"`
+}
+
+// IsEmpty tells whether both documents hold the same packages
+func (r Report) IsEmpty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.VersionChanged) == 0 && len(r.LicenseChanged) == 0
+}
+
+// Documents compares the packages of two documents, packages are matched by name
+func Documents(before, after models.Document) Report {
+	return compare(documentPackages(before), documentPackages(after))
+}
+
+// Modules compares two module graphs, modules are matched by name
+func Modules(before, after []models.Module) Report {
+	return compare(modulePackages(before), modulePackages(after))
+}
+
+func documentPackages(document models.Document) map[string]Package {
+	packages := map[string]Package{}
+	for _, pkg := range document.Packages {
+		packages[pkg.PackageName] = Package{
+			Name:    pkg.PackageName,
+			Version: pkg.PackageVersion,
+			License: packageLicense(pkg.PackageLicenseConcluded, pkg.PackageLicenseDeclared),
+		}
+	}
+	return packages
+}
+
+func modulePackages(modules []models.Module) map[string]Package {
+	packages := map[string]Package{}
+	var walk func(module models.Module)
+	walk = func(module models.Module) {
+		if _, ok := packages[module.Name]; ok {
+			return
+		}
+		packages[module.Name] = Package{
+			Name:    module.Name,
+			Version: module.Version,
+			License: packageLicense(module.LicenseConcluded, module.LicenseDeclared),
+		}
+		for _, dep := range module.Modules {
+			if dep != nil {
+				walk(*dep)
+			}
+		}
+	}
+	for _, module := range modules {
+		walk(module)
+	}
+	return packages
+}
+
+// packageLicense prefers the concluded license, falling back to the declared one when it is not asserted
+func packageLicense(concluded, declared string) string {
+	if concluded != "" && concluded != "NOASSERTION" {
+		return concluded
+	}
+	return declared
+}
+
+func compare(before, after map[string]Package) Report {
+	report := Report{
+		Added:          []Package{},
+		Removed:        []Package{},
+		VersionChanged: []Change{},
+		LicenseChanged: []Change{},
+	}
+
+	for name, pkg := range after {
+		previous, ok := before[name]
+		if !ok {
+			report.Added = append(report.Added, pkg)
+			continue
+		}
+		if previous.Version != pkg.Version {
+			report.VersionChanged = append(report.VersionChanged, Change{Name: name, Before: previous.Version, After: pkg.Version})
+		}
+		if previous.License != pkg.License {
+			report.LicenseChanged = append(report.LicenseChanged, Change{Name: name, Before: previous.License, After: pkg.License})
+		}
+	}
+	for name, pkg := range before {
+		if _, ok := after[name]; !ok {
+			report.Removed = append(report.Removed, pkg)
+		}
+	}
+
+	sort.Slice(report.Added, func(i, j int) bool { return report.Added[i].Name < report.Added[j].Name })
+	sort.Slice(report.Removed, func(i, j int) bool { return report.Removed[i].Name < report.Removed[j].Name })
+	sort.Slice(report.VersionChanged, func(i, j int) bool { return report.VersionChanged[i].Name < report.VersionChanged[j].Name })
+	sort.Slice(report.LicenseChanged, func(i, j int) bool { return report.LicenseChanged[i].Name < report.LicenseChanged[j].Name })
+	return report
+}
+
+// JSON renders the report as indented JSON
+func (r Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "\t")
+}
+
+// Text renders the report one change per line, `+` for added packages, `-` for removed and `~` for changed ones
+func (r Report) Text() string {
+	var b strings.Builder
+	for _, pkg := range r.Added {
+		fmt.Fprintf(&b, "+ %s\n", pkg)
+	}
+	for _, pkg := range r.Removed {
+		fmt.Fprintf(&b, "- %s\n", pkg)
+	}
+	for _, change := range r.VersionChanged {
+		fmt.Fprintf(&b, "~ %s version %s -> %s\n", change.Name, change.Before, change.After)
+	}
+	for _, change := range r.LicenseChanged {
+		fmt.Fprintf(&b, "~ %s license %s -> %s\n", change.Name, change.Before, change.After)
+	}
+	return b.String()
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestDocuments(t *testing.T) {
+	before, err := LoadDocument("testdata/before.json")
+	assert.NoError(t, err)
+	after, err := LoadDocument("testdata/after.spdx")
+	assert.NoError(t, err)
+	assert.Equal(t, "example-1.1.0", after.DocumentName)
+
+	report := Documents(before, after)
+	assert.Equal(t, Report{
+		Added:   []Package{{Name: "commons-lang3", Version: "3.12.0", License: "Apache-2.0"}},
+		Removed: []Package{{Name: "commons-lang", Version: "2.6", License: "Apache-2.0"}},
+		VersionChanged: []Change{
+			{Name: "example", Before: "1.0.0", After: "1.1.0"},
+			{Name: "junit", Before: "4.12", After: "4.13.2"},
+		},
+		LicenseChanged: []Change{{Name: "junit", Before: "EPL-1.0", After: "EPL-2.0"}},
+	}, report)
+
+	assert.Equal(t, `+ commons-lang3 3.12.0
+- commons-lang 2.6
+~ example version 1.0.0 -> 1.1.0
+~ junit version 4.12 -> 4.13.2
+~ junit license EPL-1.0 -> EPL-2.0
+`, report.Text())
+
+	output, err := report.JSON()
+	assert.NoError(t, err)
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(output, &decoded))
+	assert.Equal(t, report, decoded)
+}
+
+func TestModules(t *testing.T) {
+	hamcrest := models.Module{Name: "hamcrest-core", Version: "1.3"}
+	before := []models.Module{{
+		Name:    "example",
+		Version: "1.0.0",
+		Root:    true,
+		Modules: map[string]*models.Module{
+			"junit": {Name: "junit", Version: "4.12", Modules: map[string]*models.Module{"hamcrest-core": &hamcrest}},
+		},
+	}}
+	after := []models.Module{{
+		Name:    "example",
+		Version: "1.0.0",
+		Root:    true,
+		Modules: map[string]*models.Module{
+			"junit":     {Name: "junit", Version: "4.13.2", Modules: map[string]*models.Module{"hamcrest-core": &hamcrest}},
+			"slf4j-api": {Name: "slf4j-api", Version: "1.7.30", LicenseDeclared: "MIT"},
+		},
+	}}
+
+	report := Modules(before, after)
+	assert.Equal(t, []Package{{Name: "slf4j-api", Version: "1.7.30", License: "MIT"}}, report.Added)
+	assert.Empty(t, report.Removed)
+	assert.Equal(t, []Change{{Name: "junit", Before: "4.12", After: "4.13.2"}}, report.VersionChanged)
+	assert.Empty(t, report.LicenseChanged)
+	assert.True(t, Modules(before, before).IsEmpty())
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// LoadDocument reads a previously generated document, either JSON or tag value formatted
+func LoadDocument(filename string) (models.Document, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return models.Document{}, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		var document models.Document
+		err := json.Unmarshal(content, &document)
+		return document, err
+	}
+	return parseTagValue(content)
+}
+
+// parseTagValue reads the document and package tags the diff relies on out of a tag value document
+func parseTagValue(content []byte) (models.Document, error) {
+	var document models.Document
+	var pkg *models.Package
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		tag, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+		switch tag {
+		case "SPDXVersion":
+			document.SPDXVersion = value
+		case "DocumentName":
+			document.DocumentName = value
+		case "PackageName":
+			document.Packages = append(document.Packages, models.Package{PackageName: value})
+			pkg = &document.Packages[len(document.Packages)-1]
+		}
+		if pkg == nil {
+			continue
+		}
+
+		switch tag {
+		case "SPDXID":
+			pkg.SPDXID = value
+		case "PackageVersion":
+			pkg.PackageVersion = value
+		case "PackageLicenseConcluded":
+			pkg.PackageLicenseConcluded = value
+		case "PackageLicenseDeclared":
+			pkg.PackageLicenseDeclared = value
+		}
+	}
+	return document, scanner.Err()
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const diagnosticPackageCap = "package-cap-exceeded"
+
+// capModules keeps at most max modules, the root first and then the dependencies by increasing depth
+// from it, so direct and shallow dependencies are kept over deep ones. Dependencies on the omitted
+// modules are dropped from the kept ones. It returns the kept modules and how many were omitted
+func capModules(modules []models.Module, max int) ([]models.Module, int) {
+	if max <= 0 || len(modules) <= max {
+		return modules, 0
+	}
+
+	index := map[string]int{}
+	for i, module := range modules {
+		if _, ok := index[moduleKey(module)]; !ok {
+			index[moduleKey(module)] = i
+		}
+	}
+
+	// breadth first from the root, modules unreachable from it come last in their original order
+	var order []int
+	visited := map[int]bool{}
+	queue := []int{0}
+	visited[0] = true
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		order = append(order, i)
+
+		names := make([]string, 0, len(modules[i].Modules))
+		for name := range modules[i].Modules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dep := modules[i].Modules[name]
+			if dep == nil {
+				continue
+			}
+			if j, ok := index[moduleKey(*dep)]; ok && !visited[j] {
+				visited[j] = true
+				queue = append(queue, j)
+			}
+		}
+	}
+	for i := range modules {
+		if !visited[i] {
+			order = append(order, i)
+		}
+	}
+
+	kept := map[string]bool{}
+	for _, i := range order[:max] {
+		kept[moduleKey(modules[i])] = true
+	}
+
+	capped := make([]models.Module, 0, max)
+	for i, module := range modules {
+		if !kept[moduleKey(module)] || index[moduleKey(module)] != i {
+			continue
+		}
+		deps := make(map[string]*models.Module, len(module.Modules))
+		for name, dep := range module.Modules {
+			if dep != nil && kept[moduleKey(*dep)] {
+				deps[name] = dep
+			}
+		}
+		module.Modules = deps
+		capped = append(capped, module)
+	}
+	return capped, len(modules) - len(capped)
+}
+
+func moduleKey(module models.Module) string {
+	return module.Name + "@" + module.Version
+}
+
+// reportOmittedPackages records the overflow diagnostic of a capped document
+func (f *Format) reportOmittedPackages(omitted int) {
+	if omitted == 0 || f.Config.Diagnostics == nil {
+		return
+	}
+	f.Config.Diagnostics.Add(models.Diagnostic{
+		Severity: models.DiagnosticWarning,
+		Code:     diagnosticPackageCap,
+		Message:  fmt.Sprintf("%d packages omitted to keep the document within %d packages, deepest dependencies first", omitted, f.Config.MaxPackages),
+	})
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// deepModules builds root -> a, b; a -> c -> d; b -> e
+func deepModules() []models.Module {
+	module := func(name string, deps ...*models.Module) *models.Module {
+		m := &models.Module{
+			Name:     name,
+			Version:  "1.0.0",
+			CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: fakeChecksum(name)},
+			Modules:  map[string]*models.Module{},
+		}
+		for _, dep := range deps {
+			m.Modules[dep.Name] = dep
+		}
+		return m
+	}
+	d := module("d")
+	c := module("c", d)
+	e := module("e")
+	a := module("a", c)
+	b := module("b", e)
+	root := module("root", a, b)
+	root.Root = true
+	return []models.Module{*d, *c, *root, *e, *a, *b}
+}
+
+func fakeChecksum(name string) string {
+	return strings.Repeat(name, 40)
+}
+
+func TestCapModulesKeepsShallowDependencies(t *testing.T) {
+	modules, omitted := capModules(sortModules(deepModules()), 4)
+	assert.Equal(t, 2, omitted)
+
+	var names []string
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	assert.Equal(t, []string{"root", "c", "a", "b"}, names)
+
+	// c and b lost their omitted dependencies
+	assert.Empty(t, modules[1].Modules)
+	assert.Empty(t, modules[3].Modules)
+	assert.Len(t, modules[0].Modules, 2)
+
+	modules, omitted = capModules(deepModules(), 0)
+	assert.Len(t, modules, 6)
+	assert.Equal(t, 0, omitted)
+}
+
+func TestRenderCappedDocument(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	diagnostics := &models.Diagnostics{}
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    deepModules,
+		MaxPackages:  3,
+		Diagnostics:  diagnostics,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(document), "PackageName: "))
+	assert.NotContains(t, string(document), "SPDXRef-Package-c-1.0.0")
+
+	assert.Equal(t, []models.Diagnostic{{
+		Severity: models.DiagnosticWarning,
+		Code:     diagnosticPackageCap,
+		Message:  "3 packages omitted to keep the document within 3 packages, deepest dependencies first",
+	}}, diagnostics.List())
+}
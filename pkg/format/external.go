@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	documentRefPrefix = "DocumentRef-"
+	documentSPDXID    = "SPDXRef-DOCUMENT"
+)
+
+var (
+	errInvalidExternalDocument = errors.New("invalid external document")
+	// documentRefIDPattern is the syntax of the external document identifiers, after DocumentRef-
+	documentRefIDPattern = regexp.MustCompile(`^[A-Za-z0-9.\-]+$`)
+)
+
+// ExternalDocument is the SBOM a dependency ships, referenced by the document as an ExternalDocumentRef
+type ExternalDocument struct {
+	// ID identifies the reference, as `DocumentRef-<ID>`. Derived from the module name and version when empty
+	ID string `json:"id"`
+	// Namespace is the document namespace of the external SBOM
+	Namespace string `json:"namespace"`
+	// Checksum is the checksum of the external SBOM file, as `<algorithm>: <value>`, e.g. `SHA1: d6a770ba38...`
+	Checksum string `json:"checksum"`
+	// ElementID is the element of the external SBOM describing the dependency, SPDXRef-DOCUMENT when empty
+	ElementID string `json:"elementId"`
+}
+
+// validateExternalDocuments checks the external documents have a namespace, a checksum and valid identifiers
+func validateExternalDocuments(documents map[string]ExternalDocument) error {
+	for coordinates, document := range documents {
+		if document.Namespace == "" {
+			return fmt.Errorf("%w: no namespace for %s", errInvalidExternalDocument, coordinates)
+		}
+		if _, err := parseDocumentChecksum(document.Checksum); err != nil {
+			return fmt.Errorf("%w: %s: %v", errInvalidExternalDocument, coordinates, err)
+		}
+		if document.ID != "" && !documentRefIDPattern.MatchString(strings.TrimPrefix(document.ID, documentRefPrefix)) {
+			return fmt.Errorf("%w: invalid id %s for %s", errInvalidExternalDocument, document.ID, coordinates)
+		}
+		if document.ElementID != "" && !spdxIDPattern.MatchString(document.ElementID) && document.ElementID != documentSPDXID {
+			return fmt.Errorf("%w: invalid element id %s for %s", errInvalidExternalDocument, document.ElementID, coordinates)
+		}
+	}
+	return nil
+}
+
+// parseDocumentChecksum parses an `<algorithm>: <value>` checksum
+func parseDocumentChecksum(checksum string) (models.PackageChecksum, error) {
+	parts := strings.SplitN(checksum, ":", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" || strings.TrimSpace(parts[1]) == "" {
+		return models.PackageChecksum{}, fmt.Errorf("checksum %q is not <algorithm>: <value>", checksum)
+	}
+	return models.PackageChecksum{
+		Algorithm: models.HashAlgorithm(strings.ToUpper(strings.TrimSpace(parts[0]))),
+		Value:     strings.ToLower(strings.TrimSpace(parts[1])),
+	}, nil
+}
+
+// lookupExternalDocument returns the external document configured for a module, keyed by its package url, its
+// package url without version, `<name>@<version>` or its name, in that order
+func (f *Format) lookupExternalDocument(module models.Module) (ExternalDocument, bool) {
+	var keys []string
+	if strings.HasPrefix(module.PackageURL, purlPrefix) {
+		keys = append(keys, module.PackageURL)
+		unversioned := strings.SplitN(strings.SplitN(module.PackageURL, "?", 2)[0], "#", 2)[0]
+		if i := strings.LastIndex(unversioned, "@"); i > len(purlPrefix) {
+			keys = append(keys, unversioned[:i])
+		}
+	}
+	keys = append(keys, module.Name+"@"+module.Version, module.Name)
+	for _, key := range keys {
+		if document, ok := f.Config.ExternalDocuments[key]; ok {
+			return document, true
+		}
+	}
+	return ExternalDocument{}, false
+}
+
+// annotateDocumentWithExternalDocuments references the SBOMs of the dependencies configured with one, each one
+// once, and relates their package to the external document with DESCRIBED_BY
+func (f *Format) annotateDocumentWithExternalDocuments(modules []models.Module, document *models.Document) error {
+	referenced := map[string]bool{}
+	for _, module := range modules {
+		external, ok := f.lookupExternalDocument(module)
+		if !ok {
+			continue
+		}
+		checksum, err := parseDocumentChecksum(external.Checksum)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", errInvalidExternalDocument, module.Name, err)
+		}
+
+		id := strings.TrimPrefix(external.ID, documentRefPrefix)
+		if id == "" {
+			id = strings.Trim(spdxIDInvalidChars.ReplaceAllString(buildName(module.Name, module.Version), "-"), "-")
+		}
+		id = documentRefPrefix + id
+		if !referenced[id] {
+			referenced[id] = true
+			document.ExternalDocumentRefs = append(document.ExternalDocumentRefs, models.ExternalDocumentRef{
+				ExternalDocumentID: id,
+				SPDXDocument:       external.Namespace,
+				Checksum:           checksum,
+			})
+		}
+
+		elementID := external.ElementID
+		if elementID == "" {
+			elementID = documentSPDXID
+		}
+		document.Relationships = append(document.Relationships, models.Relationship{
+			SPDXElementID:      f.buildSPDXID(module),
+			RelatedSPDXElement: id + ":" + elementID,
+			RelationshipType:   "DESCRIBED_BY",
+		})
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func testExternalDocuments() map[string]ExternalDocument {
+	return map[string]ExternalDocument{
+		"junit@4.13.2": {
+			Namespace: "https://example.com/sbom/junit-4.13.2",
+			Checksum:  "SHA1: D6A770BA38583ED4BB4525BD96A50461655D2759",
+		},
+	}
+}
+
+func TestRenderExternalDocumentRefs(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:          filename,
+		ToolVersion:       "test",
+		OutputFormat:      models.OutputFormatSpdx,
+		GetSource:         testModules,
+		ExternalDocuments: testExternalDocuments(),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(document), "ExternalDocumentRef: DocumentRef-junit-4.13.2 https://example.com/sbom/junit-4.13.2 SHA1: d6a770ba38583ed4bb4525bd96a50461655d2759\n")
+	assert.Contains(t, string(document), "Relationship: SPDXRef-Package-junit-4.13.2 DESCRIBED_BY DocumentRef-junit-4.13.2:SPDXRef-DOCUMENT")
+}
+
+func TestRenderExternalDocumentRefsJSON(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.json")
+	documents := testExternalDocuments()
+	documents["junit@4.13.2"] = ExternalDocument{
+		ID:        "junit",
+		Namespace: "https://example.com/sbom/junit-4.13.2",
+		Checksum:  "SHA256: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		ElementID: "SPDXRef-Package-junit",
+	}
+	f, err := New(Config{
+		Filename:          filename,
+		ToolVersion:       "test",
+		OutputFormat:      models.OutputFormatJson,
+		GetSource:         testModules,
+		ExternalDocuments: documents,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	var document models.Document
+	assert.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, []models.ExternalDocumentRef{{
+		ExternalDocumentID: "DocumentRef-junit",
+		SPDXDocument:       "https://example.com/sbom/junit-4.13.2",
+		Checksum:           models.PackageChecksum{Algorithm: models.HashAlgoSHA256, Value: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+	}}, document.ExternalDocumentRefs)
+	assert.Contains(t, document.Relationships, models.Relationship{
+		SPDXElementID:      "SPDXRef-Package-junit-4.13.2",
+		RelatedSPDXElement: "DocumentRef-junit:SPDXRef-Package-junit",
+		RelationshipType:   "DESCRIBED_BY",
+	})
+}
+
+func TestNewInvalidExternalDocument(t *testing.T) {
+	for _, document := range []ExternalDocument{
+		{Checksum: "SHA1: d6a770ba38583ed4bb4525bd96a50461655d2759"},
+		{Namespace: "https://example.com/sbom/junit", Checksum: "d6a770ba38583ed4bb4525bd96a50461655d2759"},
+		{ID: "junit/4", Namespace: "https://example.com/sbom/junit", Checksum: "SHA1: d6a770ba"},
+	} {
+		_, err := New(Config{
+			Filename:          filepath.Join(t.TempDir(), "bom.spdx"),
+			OutputFormat:      models.OutputFormatSpdx,
+			GetSource:         testModules,
+			ExternalDocuments: map[string]ExternalDocument{"junit": document},
+		})
+		assert.True(t, errors.Is(err, errInvalidExternalDocument), "%+v", document)
+	}
+}
@@ -3,22 +3,43 @@
 package format
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/google/uuid"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
 const (
-	noAssertion = "NOASSERTION"
-	httpPrefix  = "http"
+	noAssertion      = "NOASSERTION"
+	httpPrefix       = "http"
+	purlPrefix       = "pkg:"
+	licenseRefPrefix = "LicenseRef-"
+	sourceSPDXID     = "SPDXRef-Package-Source"
+	purposeSource    = "SOURCE"
+	// annotationTypeOther is the type of the annotations that are not reviews
+	annotationTypeOther = "OTHER"
 )
 
+// scopeRelationships maps the dependency scopes to the relationship type relating the dependency to its dependent
+var scopeRelationships = map[string]string{
+	"runtime":  "RUNTIME_DEPENDENCY_OF",
+	"provided": "PROVIDED_DEPENDENCY_OF",
+	"test":     "TEST_DEPENDENCY_OF",
+}
+
 var replacer *strings.Replacer
 
 // Format ...
@@ -32,8 +53,105 @@ type Config struct {
 	Filename     string
 	OutputFormat models.OutputFormat
 	GetSource    func() []models.Module
+	// Signer optionally signs the serialized document, the detached signature is written next to it
+	Signer Signer
+	// SourceReference identifies the sources the root package was built from, e.g. a VCS url or purl.
+	// When set, a package representing them is linked from the root with a GENERATED_FROM relationship
+	SourceReference string
+	// ScopedRelationships relates dependencies according to their scope, e.g. RUNTIME_DEPENDENCY_OF for
+	// runtime-only dependencies or TEST_DEPENDENCY_OF for test ones, instead of using DEPENDS_ON for all of them
+	ScopedRelationships bool
+	// BuildEnvironment describes the toolchain used to build the project, it is written as the creator comment
+	BuildEnvironment []models.Property
+	// LicenseTexts provides the text of the LicenseRef-* licenses, keyed by LicenseRef id, used when no text
+	// could be extracted for them
+	LicenseTexts map[string]string
+	// SchemaVersion is the SPDX version of the document, DefaultSchemaVersion when empty
+	SchemaVersion string
+	// RelationshipDirection tells whether dependencies are related with DEPENDS_ON from the dependent,
+	// DEPENDENCY_OF from the dependency, or both. RelationshipDependsOn when empty
+	RelationshipDirection string
+	// MaxPackages caps the number of packages of the document, the deepest dependencies are omitted first.
+	// There is no cap when zero
+	MaxPackages int
+	// RootSPDXID is the SPDXID of the root package the document describes, e.g. `SPDXRef-RootPackage`, or
+	// RootSPDXIDFromPurl to derive it from the root package url. Derived from the root module name when empty
+	RootSPDXID string
+	// Diagnostics optionally collects the caveats of the rendered document, e.g. the omitted packages
+	Diagnostics *models.Diagnostics
+	// PluginDiagnostics are the caveats the plugin reported while reading the modules
+	PluginDiagnostics []models.Diagnostic
+	// Compress writes the document gzip compressed, the detached signature being computed over the compressed
+	// bytes. The filename is expected to end with GzipSuffix
+	Compress bool
+	// DiagnosticAnnotations embeds the plugin diagnostics followed by the ones of the rendering as annotations
+	// of the document, the comment of each annotation holding a diagnostic as JSON
+	DiagnosticAnnotations bool
+	// NTIA ensures the NTIA minimum elements are present, NTIAReport or NTIAStrict, and reports the missing ones
+	// and the compliance of the document as diagnostics. Not checked when empty
+	NTIA string
+	// Author is the person or organization creating the document, e.g. `Organization: Example Inc`, added to the
+	// creators. In NTIA mode it defaults to the supplier of the root package
+	Author string
+	// ExternalDocuments are the SBOMs shipped by dependencies, keyed by the package url, the package url without
+	// version, `<name>@<version>` or the name of the dependency. Each one is referenced as an ExternalDocumentRef
+	// and the package of the dependency is related to it with DESCRIBED_BY
+	ExternalDocuments map[string]ExternalDocument
+	// Writer receives the document instead of the file, e.g. os.Stdout to pipe it. The documents cannot be signed then
+	Writer io.Writer
+	// FormatHeader precedes the document written to the Writer with its `Content-Type`, see Detect, for the
+	// consumers reading several formats
+	FormatHeader bool
+	// TrimNoAssertion omits from the JSON document the optional package fields valued NOASSERTION, the required
+	// ones being kept
+	TrimNoAssertion bool
+	// VEXFilename is the file the companion VEX component list of the document is written to, see
+	// VEXComponentList. Not written when empty
+	VEXFilename string
+	// AnalyzedPath is the directory of the root package whose files are analyzed: the root package is written with
+	// FilesAnalyzed and the verification code of the files under it, the document and its companion files excluded.
+	// The files are not analyzed when empty
+	AnalyzedPath string
 }
 
+// Supported relationship directions
+const (
+	RelationshipDependsOn    = "depends-on"
+	RelationshipDependencyOf = "dependency-of"
+	RelationshipBoth         = "both"
+)
+
+var errUnsupportedRelationshipDirection = errors.New("unsupported relationship direction")
+
+// RootSPDXIDFromPurl derives the root SPDXID from the root package url, falling back to the module name
+const RootSPDXIDFromPurl = "purl"
+
+var (
+	errInvalidRootSPDXID = errors.New("invalid root SPDXID")
+	// spdxIDPattern is the SPDX element identifier syntax, `SPDXRef-` followed by letters, digits, `.` and `-`
+	spdxIDPattern = regexp.MustCompile(`^SPDXRef-[A-Za-z0-9.\-]+$`)
+	// spdxIDInvalidChars are replaced when deriving an identifier
+	spdxIDInvalidChars = regexp.MustCompile(`[^A-Za-z0-9.\-]+`)
+)
+
+// Supported SPDX schema versions
+const (
+	SchemaVersion22      = "2.2"
+	SchemaVersion23      = "2.3"
+	DefaultSchemaVersion = SchemaVersion23
+)
+
+var errUnsupportedSchemaVersion = errors.New("unsupported SPDX schema version")
+
+// Signer produces a detached signature over the exact bytes of the written document
+type Signer func(document []byte) ([]byte, error)
+
+// signatureSuffix is appended to the document filename to name its detached signature
+const signatureSuffix = ".sig"
+
+// GzipSuffix is appended to the filename of the compressed documents
+const GzipSuffix = ".gz"
+
 func init() {
 	replacers := []string{"/", ".", "_", "-"}
 	replacer = strings.NewReplacer(replacers...)
@@ -41,6 +159,44 @@ func init() {
 
 // New ...
 func New(cfg Config) (Format, error) {
+	switch cfg.SchemaVersion {
+	case "":
+		cfg.SchemaVersion = DefaultSchemaVersion
+	case SchemaVersion22, SchemaVersion23:
+	default:
+		return Format{}, fmt.Errorf("%w: %s", errUnsupportedSchemaVersion, cfg.SchemaVersion)
+	}
+
+	if cfg.RootSPDXID != "" && cfg.RootSPDXID != RootSPDXIDFromPurl && !spdxIDPattern.MatchString(cfg.RootSPDXID) {
+		return Format{}, fmt.Errorf("%w: %s", errInvalidRootSPDXID, cfg.RootSPDXID)
+	}
+
+	switch cfg.RelationshipDirection {
+	case "":
+		cfg.RelationshipDirection = RelationshipDependsOn
+	case RelationshipDependsOn, RelationshipDependencyOf, RelationshipBoth:
+	default:
+		return Format{}, fmt.Errorf("%w: %s", errUnsupportedRelationshipDirection, cfg.RelationshipDirection)
+	}
+
+	switch cfg.NTIA {
+	case "", NTIAReport, NTIAStrict:
+	default:
+		return Format{}, fmt.Errorf("%w: %s", errUnsupportedNTIAMode, cfg.NTIA)
+	}
+
+	if cfg.Writer != nil && cfg.Signer != nil {
+		return Format{}, errSignedStreamOutput
+	}
+
+	if err := validateExternalDocuments(cfg.ExternalDocuments); err != nil {
+		return Format{}, err
+	}
+
+	if (cfg.DiagnosticAnnotations || cfg.NTIA != "") && cfg.Diagnostics == nil {
+		cfg.Diagnostics = &models.Diagnostics{}
+	}
+
 	return Format{
 		Config: cfg,
 	}, nil
@@ -53,8 +209,9 @@ type SPDXRenderer interface {
 
 // Render prepares and generates the final SPDX document in the specified format
 func (f *Format) Render() error {
-	modules := sortModules(f.Config.GetSource())
-	document, err := buildBaseDocument(f.Config.ToolVersion, modules[0])
+	modules, omitted := capModules(sortModules(f.Config.GetSource()), f.Config.MaxPackages)
+	f.reportOmittedPackages(omitted)
+	document, err := buildBaseDocument(f.Config.ToolVersion, f.schemaVersion(), modules[0])
 	if err != nil {
 		return err
 	}
@@ -63,12 +220,31 @@ func (f *Format) Render() error {
 	if err != nil {
 		return err
 	}
-
-	file, err := os.Create(f.Config.Filename)
-	if err != nil {
+	if err := f.annotateDocumentWithExternalDocuments(modules, document); err != nil {
+		return err
+	}
+	f.annotateDocumentWithSource(document)
+	document.CreationInfo.Comment = buildCreatorComment(f.Config.BuildEnvironment)
+	if author := f.buildAuthor(document); author != "" {
+		document.CreationInfo.Creators = append(document.CreationInfo.Creators, author)
+	}
+	if err := f.ensureNTIAMinimumElements(document); err != nil {
+		return err
+	}
+	if err := f.annotateDocumentWithDiagnostics(document); err != nil {
 		return err
 	}
 
+	if f.Config.TrimNoAssertion && f.Config.OutputFormat == models.OutputFormatJson {
+		trimNoAssertion(document, f.schemaVersion())
+	}
+
+	if f.Config.VEXFilename != "" {
+		if err := f.renderVEXComponentList(*document); err != nil {
+			return fmt.Errorf("failed to write VEX component list: %w", err)
+		}
+	}
+
 	var spdxRenderer SPDXRenderer
 
 	switch f.Config.OutputFormat {
@@ -76,23 +252,66 @@ func (f *Format) Render() error {
 		spdxRenderer = TagValueSPDXRenderer{}
 	case models.OutputFormatJson:
 		spdxRenderer = JsonSPDXRenderer{}
+	case models.OutputFormatInventory:
+		spdxRenderer = InventoryRenderer{}
 	}
 
 	outputBytes, err := spdxRenderer.RenderDocument(*document)
 	if err != nil {
 		return err
 	}
+	if f.Config.Compress {
+		if outputBytes, err = compress(outputBytes); err != nil {
+			return fmt.Errorf("failed to compress document: %w", err)
+		}
+	}
+
+	if f.Config.Writer != nil {
+		if f.Config.FormatHeader {
+			outputBytes = append(f.buildFormatHeader(), outputBytes...)
+		}
+		_, err := f.Config.Writer.Write(outputBytes)
+		return err
+	}
 
 	// Write to file
-	file.Write(outputBytes)
-	file.Sync()
+	if err := writeFile(f.Config.Filename, outputBytes); err != nil {
+		return err
+	}
 
+	if f.Config.Signer != nil {
+		signature, err := f.Config.Signer(outputBytes)
+		if err != nil {
+			return fmt.Errorf("failed to sign document: %w", err)
+		}
+		return writeFile(f.Config.Filename+signatureSuffix, signature)
+	}
 	return nil
 }
 
-func buildBaseDocument(toolVersion string, module models.Module) (*models.Document, error) {
+// compress returns the gzip compressed content, the writer being closed to flush the gzip footer
+func compress(content []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func writeFile(filename string, content []byte) error {
+	return helper.WriteFileAtomic(filename, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	})
+}
+
+func buildBaseDocument(toolVersion, schemaVersion string, module models.Module) (*models.Document, error) {
 	return &models.Document{
-		SPDXVersion:       "SPDX-2.2",
+		SPDXVersion:       "SPDX-" + schemaVersion,
 		DataLicense:       "CC0-1.0",
 		SPDXID:            "SPDXRef-DOCUMENT",
 		DocumentName:      buildName(module.Name, module.Version),
@@ -107,11 +326,18 @@ func buildBaseDocument(toolVersion string, module models.Module) (*models.Docume
 	}, nil
 }
 
-// WIP
+// annotateDocumentWithPackages adds a package per module, the root one being described by the document, and relates
+// each module to the dependencies of its Modules graph edges. The edges are walked per module, in dependency name
+// order, so that a dependency reached along several paths or through a cycle is related once per dependent
 func (f *Format) annotateDocumentWithPackages(modules []models.Module, document *models.Document) error {
+	related := map[models.Relationship]bool{}
 	for _, module := range modules {
 		pkg, err := f.convertToPackage(module)
+		if err == nil && pkg.RootPackage {
+			err = f.analyzeFiles(&pkg)
+		}
 		if pkg.RootPackage {
+			document.DocumentDescribes = append(document.DocumentDescribes, pkg.SPDXID)
 			document.Relationships = append(document.Relationships, models.Relationship{
 				SPDXElementID:      document.SPDXID,
 				RelatedSPDXElement: pkg.SPDXID,
@@ -121,55 +347,297 @@ func (f *Format) annotateDocumentWithPackages(modules []models.Module, document
 		if err != nil {
 			return fmt.Errorf("failed to convert module %w", err)
 		}
-		for _, subMod := range module.Modules {
+		names := make([]string, 0, len(module.Modules))
+		for name := range module.Modules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			subMod := module.Modules[name]
 			subPkg, err := f.convertToPackage(*subMod)
 			if err != nil {
 				return fmt.Errorf("failed to convert submodule %w", err)
 			}
-			document.Relationships = append(document.Relationships, models.Relationship{
-				SPDXElementID:      pkg.SPDXID,
-				RelatedSPDXElement: subPkg.SPDXID,
-				RelationshipType:   "DEPENDS_ON",
-			})
+			for _, relationship := range f.buildDependencyRelationships(pkg.SPDXID, subPkg.SPDXID, subMod.Scope) {
+				if !related[relationship] {
+					related[relationship] = true
+					document.Relationships = append(document.Relationships, relationship)
+				}
+			}
 		}
-		for licence := range module.OtherLicense {
-			document.ExtractedLicensingInfos = append(document.ExtractedLicensingInfos, models.ExtractedLicensingInfo{
-				LicenseID:      module.OtherLicense[licence].ID,
-				ExtractedText:  module.OtherLicense[licence].ExtractedText,
-				LicenseName:    module.OtherLicense[licence].Name,
-				LicenseComment: module.OtherLicense[licence].Comments,
-			})
+		for _, licence := range module.OtherLicense {
+			f.annotateDocumentWithLicense(licence, document)
 		}
 		document.Packages = append(document.Packages, pkg)
 	}
 	return nil
 }
 
+// analyzeFiles sets the verification code of the files of the root package under the analyzed path, leaving out the
+// document, its signature and its VEX component list when they are written there, as they change with the code
+func (f *Format) analyzeFiles(pkg *models.Package) error {
+	if f.Config.AnalyzedPath == "" {
+		return nil
+	}
+	root, err := filepath.Abs(f.Config.AnalyzedPath)
+	if err != nil {
+		return err
+	}
+	var outputs, excluded []string
+	if f.Config.Writer == nil && f.Config.Filename != "" {
+		outputs = append(outputs, f.Config.Filename, f.Config.Filename+signatureSuffix)
+	}
+	if f.Config.VEXFilename != "" {
+		outputs = append(outputs, f.Config.VEXFilename)
+	}
+	for _, file := range outputs {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(root, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			excluded = append(excluded, rel)
+		}
+	}
+
+	code, err := helper.GetVerificationCode(root, excluded...)
+	if err != nil {
+		return fmt.Errorf("failed to compute the verification code of %s: %w", root, err)
+	}
+	pkg.FilesAnalyzed = true
+	pkg.PackageVerificationCode = &code
+	return nil
+}
+
+// annotateDocumentWithDiagnostics records the diagnostics as annotations of the document by the tool
+func (f *Format) annotateDocumentWithDiagnostics(document *models.Document) error {
+	if !f.Config.DiagnosticAnnotations {
+		return nil
+	}
+
+	diagnostics := append([]models.Diagnostic{}, f.Config.PluginDiagnostics...)
+	if f.Config.Diagnostics != nil {
+		diagnostics = append(diagnostics, f.Config.Diagnostics.List()...)
+	}
+	for _, diagnostic := range diagnostics {
+		comment, err := json.Marshal(diagnostic)
+		if err != nil {
+			return fmt.Errorf("failed to encode diagnostic %s: %w", diagnostic.Code, err)
+		}
+		document.Annotations = append(document.Annotations, models.Annotation{
+			Annotator:      document.CreationInfo.Creators[0],
+			AnnotationDate: document.CreationInfo.Created,
+			AnnotationType: annotationTypeOther,
+			Comment:        string(comment),
+		})
+	}
+	return nil
+}
+
+// schemaVersion returns the SPDX version of the document, for formats built without New
+func (f *Format) schemaVersion() string {
+	if f.Config.SchemaVersion == "" {
+		return DefaultSchemaVersion
+	}
+	return f.Config.SchemaVersion
+}
+
+// buildPrimaryPackagePurpose returns the purpose of a package, the field only exists from SPDX 2.3 on
+func (f *Format) buildPrimaryPackagePurpose(purpose string) string {
+	if f.schemaVersion() == SchemaVersion22 {
+		return ""
+	}
+	return purpose
+}
+
+// annotateDocumentWithLicense defines a non-standard license once per document, under its LicenseRef id
+// and along with the text it was extracted from
+func (f *Format) annotateDocumentWithLicense(licence *models.License, document *models.Document) {
+	licenseID := buildLicenseRef(licence.ID)
+	for _, info := range document.ExtractedLicensingInfos {
+		if info.LicenseID == licenseID {
+			return
+		}
+	}
+
+	document.ExtractedLicensingInfos = append(document.ExtractedLicensingInfos, models.ExtractedLicensingInfo{
+		LicenseID:      licenseID,
+		ExtractedText:  f.buildExtractedText(licenseID, licence.ExtractedText),
+		LicenseName:    licence.Name,
+		LicenseComment: licence.Comments,
+	})
+}
+
+// buildExtractedText returns the license text found for the module, falling back to the text provided by the user
+func (f *Format) buildExtractedText(licenseID, text string) string {
+	text = strings.TrimSpace(text)
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "<text>"), "</text>"))
+	if text == "" {
+		text = strings.TrimSpace(f.Config.LicenseTexts[licenseID])
+	}
+	return setPkgValue(text)
+}
+
+func buildLicenseRef(licenseID string) string {
+	if strings.HasPrefix(licenseID, licenseRefPrefix) {
+		return licenseID
+	}
+	return licenseRefPrefix + licenseID
+}
+
+// buildDependencyRelationships relates a package to one of its dependencies in the configured direction.
+// The inverse of DEPENDS_ON is DEPENDENCY_OF, or the scope specific type when scoped relationships are enabled
+func (f *Format) buildDependencyRelationships(pkgID, depID, scope string) []models.Relationship {
+	inverse := models.Relationship{
+		SPDXElementID:      depID,
+		RelatedSPDXElement: pkgID,
+		RelationshipType:   "DEPENDENCY_OF",
+	}
+	if relationshipType, ok := scopeRelationships[scope]; ok && f.Config.ScopedRelationships {
+		inverse.RelationshipType = relationshipType
+	}
+
+	switch f.Config.RelationshipDirection {
+	case RelationshipDependencyOf:
+		return []models.Relationship{inverse}
+	case RelationshipBoth:
+		return []models.Relationship{{
+			SPDXElementID:      pkgID,
+			RelatedSPDXElement: depID,
+			RelationshipType:   "DEPENDS_ON",
+		}, inverse}
+	}
+	return []models.Relationship{f.buildDependencyRelationship(pkgID, depID, scope)}
+}
+
+// buildDependencyRelationship relates a package to one of its dependencies. Scopes with a dedicated
+// relationship type are only honored when scoped relationships are enabled
+func (f *Format) buildDependencyRelationship(pkgID, depID, scope string) models.Relationship {
+	if relationshipType, ok := scopeRelationships[scope]; ok && f.Config.ScopedRelationships {
+		return models.Relationship{
+			SPDXElementID:      depID,
+			RelatedSPDXElement: pkgID,
+			RelationshipType:   relationshipType,
+		}
+	}
+	return models.Relationship{
+		SPDXElementID:      pkgID,
+		RelatedSPDXElement: depID,
+		RelationshipType:   "DEPENDS_ON",
+	}
+}
+
+// annotateDocumentWithSource adds the package representing the project sources and links the root package to it
+func (f *Format) annotateDocumentWithSource(document *models.Document) {
+	if f.Config.SourceReference == "" {
+		return
+	}
+
+	source := models.Package{
+		PackageName:             "source",
+		SPDXID:                  sourceSPDXID,
+		PackageSupplier:         noAssertion,
+		PackageDownloadLocation: noAssertion,
+		FilesAnalyzed:           false,
+		PackageChecksums:        []models.PackageChecksum{},
+		PackageHomePage:         noAssertion,
+		PackageLicenseConcluded: noAssertion,
+		PackageLicenseDeclared:  noAssertion,
+		PackageCopyrightText:    noAssertion,
+		PackageLicenseComments:  noAssertion,
+		PackageComment:          fmt.Sprintf("Sources the root package was built from: %s", f.Config.SourceReference),
+		PrimaryPackagePurpose:   f.buildPrimaryPackagePurpose(purposeSource),
+	}
+	if !strings.HasPrefix(f.Config.SourceReference, purlPrefix) {
+		source.PackageDownloadLocation = f.Config.SourceReference
+	}
+
+	for _, pkg := range document.Packages {
+		if pkg.RootPackage {
+			document.Relationships = append(document.Relationships, models.Relationship{
+				SPDXElementID:      pkg.SPDXID,
+				RelatedSPDXElement: source.SPDXID,
+				RelationshipType:   "GENERATED_FROM",
+			})
+		}
+	}
+	document.Packages = append(document.Packages, source)
+}
+
 // WIP
 func (f *Format) convertToPackage(module models.Module) (models.Package, error) {
 	return models.Package{
 		PackageName:             module.Name,
-		SPDXID:                  setPkgSPDXID(module.Name, module.Version, module.Root),
+		SPDXID:                  f.buildSPDXID(module),
 		PackageVersion:          buildVersion(module),
 		PackageSupplier:         setPkgValue(module.Supplier.Get()),
 		PackageDownloadLocation: setPkgValue(module.PackageDownloadLocation),
 		FilesAnalyzed:           false,
-		PackageChecksums: []models.PackageChecksum{{
-			Algorithm: module.CheckSum.Algorithm,
-			Value:     module.CheckSum.String(),
-		}},
-		PackageHomePage:         buildHomepageURL(module.PackageURL),
-		PackageLicenseConcluded: noAssertion, // setPkgValue(module.LicenseConcluded),
-		PackageLicenseDeclared:  noAssertion, // setPkgValue(module.LicenseDeclared),
-		PackageCopyrightText:    noAssertion, // setPkgValue(module.Copyright),
+		PackageChecksums:        buildPackageChecksums(module),
+		PackageHomePage:         buildHomepageURL(module),
+		PackageLicenseConcluded: setPkgValue(module.LicenseConcluded),
+		PackageLicenseDeclared:  setPkgValue(module.LicenseDeclared),
+		PackageCopyrightText:    setPkgValue(module.Copyright),
 		PackageLicenseComments:  setPkgValue(""),
-		PackageComment:          setPkgValue(""),
+		PackageComment:          setPkgValue(buildPackageComment(module)),
+		PackageSourceInfo:       module.SourceInfo,
 		RootPackage:             module.Root,
+		PrimaryPackagePurpose:   f.buildPrimaryPackagePurpose(buildPackagePurpose(module)),
+		ExternalRefs:            buildExternalRefs(module),
 	}, nil
 }
 
+// buildPackageChecksums returns the module checksum followed by its additional checksums, none when the plugin
+// did not compute it
+func buildPackageChecksums(module models.Module) []models.PackageChecksum {
+	if module.CheckSum == nil {
+		return nil
+	}
+	checksums := []models.PackageChecksum{{
+		Algorithm: module.CheckSum.Algorithm,
+		Value:     module.CheckSum.String(),
+	}}
+	for i := range module.AdditionalCheckSums {
+		checksum := &module.AdditionalCheckSums[i]
+		checksums = append(checksums, models.PackageChecksum{
+			Algorithm: checksum.Algorithm,
+			Value:     checksum.String(),
+		})
+	}
+	return checksums
+}
+
+// buildPackagePurpose returns the purpose the plugin classified the module with. Other dependencies are
+// libraries, while the purpose of the root package cannot be told from the module
+func buildPackagePurpose(module models.Module) string {
+	if module.Purpose != "" {
+		return module.Purpose
+	}
+	if module.Root {
+		return ""
+	}
+	return models.PurposeLibrary
+}
+
+// buildExternalRefs references the module package url, for plugins setting a purl as PackageURL
+func buildExternalRefs(module models.Module) []models.ExternalRef {
+	if !strings.HasPrefix(module.PackageURL, purlPrefix) {
+		return nil
+	}
+	return []models.ExternalRef{{
+		ReferenceCategory: "PACKAGE-MANAGER",
+		ReferenceType:     "purl",
+		ReferenceLocator:  module.PackageURL,
+	}}
+}
+
 // todo: complete build package homepage rules
-func buildHomepageURL(url string) string {
+// The PackageURL is the homepage, unless the plugin set a purl there, in which case PackageHomePage is
+func buildHomepageURL(module models.Module) string {
+	url := module.PackageURL
+	if strings.HasPrefix(url, purlPrefix) {
+		url = module.PackageHomePage
+	}
 	if url == "" {
 		return noAssertion
 	}
@@ -181,6 +649,27 @@ func buildHomepageURL(url string) string {
 	return fmt.Sprintf("https://%s", url)
 }
 
+// buildPackageComment appends the module properties to its comment, one `name: value` per line
+func buildPackageComment(module models.Module) string {
+	lines := []string{}
+	if module.PackageComment != "" {
+		lines = append(lines, module.PackageComment)
+	}
+	for _, p := range module.Properties {
+		lines = append(lines, fmt.Sprintf("%s: %s", p.Name, p.Value))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// buildCreatorComment describes the build environment, one property per line
+func buildCreatorComment(environment []models.Property) string {
+	lines := []string{}
+	for _, p := range environment {
+		lines = append(lines, fmt.Sprintf("%s: %s", p.Name, p.Value))
+	}
+	return strings.Join(lines, "\n")
+}
+
 func buildVersion(module models.Module) string {
 	if module.Version != "" {
 		return module.Version
@@ -211,20 +700,43 @@ func setPkgValue(s string) string {
 	return s
 }
 
-func setPkgSPDXID(s, v string, root bool) string {
+// buildSPDXID returns the SPDXID of a module package, the root one being configurable
+func (f *Format) buildSPDXID(module models.Module) string {
+	if module.Root {
+		switch f.Config.RootSPDXID {
+		case "":
+		case RootSPDXIDFromPurl:
+			if strings.HasPrefix(module.PackageURL, purlPrefix) {
+				return "SPDXRef-" + strings.Trim(spdxIDInvalidChars.ReplaceAllString(module.PackageURL, "-"), "-")
+			}
+		default:
+			return f.Config.RootSPDXID
+		}
+	}
+	return setPkgSPDXID(module.Group, module.Name, module.Version, module.Root)
+}
+
+// setPkgSPDXID returns `SPDXRef-Package-<name>` for the root package and `SPDXRef-Package-[<group>-]<name>-<version>`
+// otherwise, the group telling apart the packages of distinct groups sharing a name
+func setPkgSPDXID(group, s, v string, root bool) string {
 	if root {
 		return fmt.Sprintf("SPDXRef-Package-%s", replacer.Replace(s))
 	}
+	if group != "" {
+		s = group + "-" + s
+	}
 
 	return fmt.Sprintf("SPDXRef-Package-%s-%s", replacer.Replace(s), v)
 }
 
-// todo: improve this logic
+// sortModules returns the modules with the root one first. The modules of the source are left in place, as they are
+// read again to render the merged document of several package managers
 func sortModules(modules []models.Module) []models.Module {
 	for i, m := range modules {
 		if m.Root {
-			modules = append(modules[:i], modules[i+1:]...)
-			return append([]models.Module{m}, modules...)
+			sorted := make([]models.Module, 0, len(modules))
+			sorted = append(append(append(sorted, m), modules[:i]...), modules[i+1:]...)
+			return sorted
 		}
 	}
 
@@ -0,0 +1,639 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func testModules() []models.Module {
+	dep := models.Module{
+		Name:     "junit",
+		Version:  "4.13.2",
+		CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57"},
+		Modules:  map[string]*models.Module{},
+	}
+	return []models.Module{
+		{
+			Name:     "example",
+			Version:  "1.0.0",
+			Root:     true,
+			CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "c3499c2729730a7f807efb8676a92dcb6f8a3f8f"},
+			Modules:  map[string]*models.Module{"junit": &dep},
+		},
+		dep,
+	}
+}
+
+func TestRenderSignsDocument(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	var signed []byte
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    testModules,
+		Signer: func(document []byte) ([]byte, error) {
+			signed = document
+			sum := sha256.Sum256(document)
+			return sum[:], nil
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, document, signed)
+
+	signature, err := ioutil.ReadFile(filename + signatureSuffix)
+	assert.NoError(t, err)
+	sum := sha256.Sum256(document)
+	assert.Equal(t, sum[:], signature)
+}
+
+func TestRenderGeneratedFromSource(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:        filename,
+		ToolVersion:     "test",
+		OutputFormat:    models.OutputFormatSpdx,
+		GetSource:       testModules,
+		SourceReference: "git+https://github.com/example/example.git",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(document), "Relationship: SPDXRef-Package-example GENERATED_FROM SPDXRef-Package-Source")
+	assert.Contains(t, string(document), "PackageDownloadLocation: git+https://github.com/example/example.git")
+}
+
+func TestRenderWithoutSource(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    testModules,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(document), "GENERATED_FROM")
+}
+
+func TestScopedDependencyRelationships(t *testing.T) {
+	tests := []struct {
+		scope   string
+		scoped  bool
+		element string
+		related string
+		kind    string
+	}{
+		{scope: "runtime", scoped: true, element: "dep", related: "pkg", kind: "RUNTIME_DEPENDENCY_OF"},
+		{scope: "compile", scoped: true, element: "pkg", related: "dep", kind: "DEPENDS_ON"},
+		{scope: "", scoped: true, element: "pkg", related: "dep", kind: "DEPENDS_ON"},
+		{scope: "runtime", scoped: false, element: "pkg", related: "dep", kind: "DEPENDS_ON"},
+		{scope: "test", scoped: true, element: "dep", related: "pkg", kind: "TEST_DEPENDENCY_OF"},
+		{scope: "test", scoped: false, element: "pkg", related: "dep", kind: "DEPENDS_ON"},
+	}
+
+	for _, tt := range tests {
+		f := Format{Config: Config{ScopedRelationships: tt.scoped}}
+		relationship := f.buildDependencyRelationship("pkg", "dep", tt.scope)
+		assert.Equal(t, models.Relationship{
+			SPDXElementID:      tt.element,
+			RelatedSPDXElement: tt.related,
+			RelationshipType:   tt.kind,
+		}, relationship, "scope %q, scoped %v", tt.scope, tt.scoped)
+	}
+}
+
+func TestRenderBuildEnvironment(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    testModules,
+		BuildEnvironment: []models.Property{
+			{Name: "Maven version", Value: "3.8.1"},
+			{Name: "Java version", Value: "11.0.11"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(document), "CreatorComment: <text>Maven version: 3.8.1\nJava version: 11.0.11</text>\n")
+}
+
+func TestRenderExtractedLicensingInfo(t *testing.T) {
+	custom := &models.License{ID: "Custom", Name: "Custom License", ExtractedText: "Custom terms\nline two"}
+	modules := testModules()
+	modules[0].OtherLicense = []*models.License{custom}
+	modules[1].OtherLicense = []*models.License{custom, {ID: "LicenseRef-Internal", Name: "Internal"}}
+
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    func() []models.Module { return modules },
+		LicenseTexts: map[string]string{"LicenseRef-Internal": "Internal use only\n"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(document), "LicenseID: LicenseRef-Custom\n"))
+	assert.Contains(t, string(document), "LicenseID: LicenseRef-Custom\nExtractedText: <text>Custom terms\nline two</text>\nLicenseName: Custom License\n")
+	assert.Contains(t, string(document), "LicenseID: LicenseRef-Internal\nExtractedText: Internal use only\nLicenseName: Internal\n")
+}
+
+func TestRenderSchemaVersions(t *testing.T) {
+	render := func(schemaVersion string, outputFormat models.OutputFormat) string {
+		modules := testModules()
+		modules[1].SourceInfo = "resolved via mvn dependency:list"
+
+		filename := filepath.Join(t.TempDir(), "bom-Java-Maven")
+		f, err := New(Config{
+			Filename:        filename,
+			ToolVersion:     "test",
+			OutputFormat:    outputFormat,
+			GetSource:       testModules,
+			SourceReference: "https://github.com/example/example",
+			SchemaVersion:   schemaVersion,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Render())
+
+		document, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		return string(document)
+	}
+
+	document := render("", models.OutputFormatSpdx)
+	assert.Contains(t, document, "SPDXVersion: SPDX-2.3\n")
+	assert.Contains(t, document, "PrimaryPackagePurpose: LIBRARY\n")
+	assert.Contains(t, document, "PrimaryPackagePurpose: SOURCE\n")
+
+	document = render(SchemaVersion22, models.OutputFormatSpdx)
+	assert.Contains(t, document, "SPDXVersion: SPDX-2.2\n")
+	assert.NotContains(t, document, "PrimaryPackagePurpose")
+
+	document = render(SchemaVersion22, models.OutputFormatJson)
+	assert.Contains(t, document, `"spdxVersion": "SPDX-2.2"`)
+	assert.NotContains(t, document, "primaryPackagePurpose")
+
+	_, err := New(Config{SchemaVersion: "2.1"})
+	assert.Error(t, err)
+}
+
+func TestRenderPurlExternalRef(t *testing.T) {
+	modules := testModules()
+	modules[0].PackageURL = "pkg:maven/com.example/example@1.0.0"
+	modules[0].PackageHomePage = "https://example.com"
+	modules[1].PackageURL = "github.com/junit-team/junit4"
+
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    func() []models.Module { return modules },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(document), "PackageHomePage: https://example.com\n")
+	assert.Contains(t, string(document), "ExternalRef: PACKAGE-MANAGER purl pkg:maven/com.example/example@1.0.0\n")
+	assert.Contains(t, string(document), "PackageHomePage: https://github.com/junit-team/junit4\n")
+	assert.Equal(t, 1, strings.Count(string(document), "ExternalRef:"))
+}
+
+func TestDependencyRelationshipDirections(t *testing.T) {
+	for _, direction := range []string{RelationshipDependsOn, RelationshipDependencyOf, RelationshipBoth} {
+		t.Run(direction, func(t *testing.T) {
+			modules := testModules()
+			runtime := models.Module{
+				Name:     "hamcrest-core",
+				Version:  "1.3",
+				Scope:    "runtime",
+				CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "42a25dc3219429f0e5d060061f71acb49bf010a0"},
+				Modules:  map[string]*models.Module{},
+			}
+			modules[0].Modules["hamcrest-core"] = &runtime
+			test := models.Module{
+				Name:     "mockito-core",
+				Version:  "4.0.0",
+				Scope:    "test",
+				CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "d6b4e4d6e0ac54fc4eaafe720ac8e4b0fd9ae8b2"},
+				Modules:  map[string]*models.Module{},
+			}
+			modules[0].Modules["mockito-core"] = &test
+			// a module listed twice must not duplicate its relationships
+			core := models.Module{
+				Name:     "core",
+				Version:  "2.0.0",
+				CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "a3b2c1d0e9f8a7b6c5d4e3f2a1b0c9d8e7f6a5b4"},
+				Modules:  map[string]*models.Module{"junit": modules[0].Modules["junit"]},
+			}
+			modules = append(modules, runtime, test, core, core)
+
+			filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+			f, err := New(Config{
+				Filename:              filename,
+				ToolVersion:           "test",
+				OutputFormat:          models.OutputFormatSpdx,
+				GetSource:             func() []models.Module { return modules },
+				ScopedRelationships:   true,
+				RelationshipDirection: direction,
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, f.Render())
+
+			document, err := ioutil.ReadFile(filename)
+			assert.NoError(t, err)
+			var relationships []string
+			for _, line := range strings.Split(string(document), "\n") {
+				if strings.HasPrefix(line, "Relationship: ") {
+					relationships = append(relationships, line)
+				}
+			}
+			sort.Strings(relationships)
+
+			golden, err := ioutil.ReadFile(filepath.Join("testdata", "relationships-"+direction+".golden"))
+			assert.NoError(t, err)
+			assert.Equal(t, strings.Split(strings.TrimSpace(string(golden)), "\n"), relationships)
+		})
+	}
+
+	_, err := New(Config{RelationshipDirection: "sideways"})
+	assert.True(t, errors.Is(err, errUnsupportedRelationshipDirection))
+}
+
+func TestDependencyRelationshipsOfGraph(t *testing.T) {
+	module := func(name string, root bool) *models.Module {
+		return &models.Module{
+			Name:     name,
+			Version:  "1.0.0",
+			Root:     root,
+			CheckSum: &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57"},
+			Modules:  map[string]*models.Module{},
+		}
+	}
+	// app depends on d along two paths, and d depends back on b
+	app, b, c, d := module("app", true), module("b", false), module("c", false), module("d", false)
+	app.Modules["c"], app.Modules["b"] = c, b
+	b.Modules["d"], c.Modules["d"] = d, d
+	d.Modules["b"] = b
+
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    func() []models.Module { return []models.Module{*app, *b, *c, *d} },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	var relationships []string
+	for _, line := range strings.Split(string(document), "\n") {
+		if strings.HasPrefix(line, "Relationship: ") {
+			relationships = append(relationships, strings.TrimPrefix(line, "Relationship: "))
+		}
+	}
+	assert.Equal(t, []string{
+		"SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-app",
+		"SPDXRef-Package-app DEPENDS_ON SPDXRef-Package-b-1.0.0",
+		"SPDXRef-Package-app DEPENDS_ON SPDXRef-Package-c-1.0.0",
+		"SPDXRef-Package-b-1.0.0 DEPENDS_ON SPDXRef-Package-d-1.0.0",
+		"SPDXRef-Package-c-1.0.0 DEPENDS_ON SPDXRef-Package-d-1.0.0",
+		"SPDXRef-Package-d-1.0.0 DEPENDS_ON SPDXRef-Package-b-1.0.0",
+	}, relationships)
+}
+
+func TestRenderPackageSourceInfo(t *testing.T) {
+	for _, output := range []struct {
+		format   models.OutputFormat
+		expected string
+	}{
+		{format: models.OutputFormatSpdx, expected: "PackageSourceInfo: resolved via mvn dependency:list\n"},
+		{format: models.OutputFormatJson, expected: `"sourceInfo": "resolved via mvn dependency:list"`},
+	} {
+		modules := testModules()
+		modules[1].SourceInfo = "resolved via mvn dependency:list"
+
+		filename := filepath.Join(t.TempDir(), "bom-Java-Maven")
+		f, err := New(Config{
+			Filename:     filename,
+			ToolVersion:  "test",
+			OutputFormat: output.format,
+			GetSource:    func() []models.Module { return modules },
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Render())
+
+		document, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		assert.Contains(t, string(document), output.expected)
+		assert.Equal(t, 1, strings.Count(string(document), "ourceInfo"))
+	}
+}
+
+func TestRenderRootSPDXID(t *testing.T) {
+	tests := []struct {
+		rootSPDXID string
+		expected   string
+	}{
+		{rootSPDXID: "", expected: "SPDXRef-Package-example"},
+		{rootSPDXID: "SPDXRef-RootPackage", expected: "SPDXRef-RootPackage"},
+		{rootSPDXID: RootSPDXIDFromPurl, expected: "SPDXRef-pkg-maven-com.example-example-1.0.0"},
+	}
+
+	for _, tt := range tests {
+		modules := testModules()
+		modules[0].PackageURL = "pkg:maven/com.example/example@1.0.0"
+
+		filename := filepath.Join(t.TempDir(), "bom-Java-Maven.json")
+		f, err := New(Config{
+			Filename:     filename,
+			ToolVersion:  "test",
+			OutputFormat: models.OutputFormatJson,
+			GetSource:    func() []models.Module { return modules },
+			RootSPDXID:   tt.rootSPDXID,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Render())
+
+		data, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		var document models.Document
+		assert.NoError(t, json.Unmarshal(data, &document))
+
+		assert.Equal(t, tt.expected, document.Packages[0].SPDXID)
+		assert.Equal(t, []string{tt.expected}, document.DocumentDescribes)
+		assert.Contains(t, document.Relationships, models.Relationship{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelatedSPDXElement: tt.expected,
+			RelationshipType:   "DESCRIBES",
+		})
+		assert.Contains(t, document.Relationships, models.Relationship{
+			SPDXElementID:      tt.expected,
+			RelatedSPDXElement: "SPDXRef-Package-junit-4.13.2",
+			RelationshipType:   "DEPENDS_ON",
+		})
+	}
+
+	_, err := New(Config{RootSPDXID: "RootPackage"})
+	assert.True(t, errors.Is(err, errInvalidRootSPDXID))
+}
+
+func TestRenderDiagnosticAnnotations(t *testing.T) {
+	unresolved := models.Diagnostic{
+		Severity: models.DiagnosticWarning,
+		Code:     "unresolved-version",
+		Module:   "junit",
+		Message:  "version ${junit.version} could not be resolved",
+	}
+	render := func(outputFormat models.OutputFormat, annotations bool) []byte {
+		filename := filepath.Join(t.TempDir(), "bom-Java-Maven")
+		f, err := New(Config{
+			Filename:              filename,
+			ToolVersion:           "test",
+			OutputFormat:          outputFormat,
+			GetSource:             testModules,
+			MaxPackages:           1,
+			PluginDiagnostics:     []models.Diagnostic{unresolved},
+			DiagnosticAnnotations: annotations,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Render())
+
+		data, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		return data
+	}
+
+	var document models.Document
+	assert.NoError(t, json.Unmarshal(render(models.OutputFormatJson, true), &document))
+	assert.Len(t, document.Annotations, 2)
+	var diagnostics []models.Diagnostic
+	for _, annotation := range document.Annotations {
+		assert.Equal(t, "Tool: spdx-sbom-generator-test", annotation.Annotator)
+		assert.Equal(t, document.CreationInfo.Created, annotation.AnnotationDate)
+		assert.Equal(t, "OTHER", annotation.AnnotationType)
+
+		var diagnostic models.Diagnostic
+		assert.NoError(t, json.Unmarshal([]byte(annotation.Comment), &diagnostic))
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	assert.Equal(t, unresolved, diagnostics[0])
+	assert.Equal(t, diagnosticPackageCap, diagnostics[1].Code)
+
+	tagValue := string(render(models.OutputFormatSpdx, true))
+	assert.Contains(t, tagValue, "AnnotationType: OTHER\nSPDXREF: SPDXRef-DOCUMENT\nAnnotationComment: <text>{\"severity\":\"warning\",\"code\":\"unresolved-version\",")
+	assert.Equal(t, 2, strings.Count(tagValue, "Annotator: Tool: spdx-sbom-generator-test\n"))
+
+	assert.NotContains(t, string(render(models.OutputFormatJson, false)), "annotations")
+	assert.NotContains(t, string(render(models.OutputFormatSpdx, false)), "Annotator")
+}
+
+func TestRenderCompressedDocument(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.json"+GzipSuffix)
+	var signed []byte
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatJson,
+		GetSource:    testModules,
+		Compress:     true,
+		Signer: func(document []byte) ([]byte, error) {
+			signed = document
+			return []byte("signature"), nil
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	compressed, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, compressed, signed)
+
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+
+	var document models.Document
+	assert.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Len(t, document.Packages, 2)
+	assert.Equal(t, "SPDXRef-Package-example", document.Packages[0].SPDXID)
+}
+
+func TestRenderWithoutChecksum(t *testing.T) {
+	modules := testModules()
+	modules[1].CheckSum = nil
+
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    func() []models.Module { return modules },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(document), "PackageChecksum: "))
+	assert.Contains(t, string(document), "PackageChecksum: SHA1: c3499c2729730a7f807efb8676a92dcb6f8a3f8f")
+}
+
+func TestRenderAdditionalChecksums(t *testing.T) {
+	modules := testModules()
+	modules[1].AdditionalCheckSums = []models.CheckSum{
+		{Algorithm: models.HashAlgoSHA256, Value: "0d80367a1768ceaf1220f7caabafef1670a393cdb2efc165d02a8c9cdec4726c"},
+	}
+
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    func() []models.Module { return modules },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	document, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(document), "PackageChecksum: "))
+	assert.Contains(t, string(document), "PackageChecksum: SHA1: 2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57\nPackageChecksum: SHA256: 0d80367a1768ceaf1220f7caabafef1670a393cdb2efc165d02a8c9cdec4726c")
+}
+
+func TestRenderPackageLicenses(t *testing.T) {
+	licensed := func() []models.Module {
+		modules := testModules()
+		modules[0].LicenseDeclared = "Apache-2.0"
+		modules[0].LicenseConcluded = "Apache-2.0"
+		modules[0].Copyright = "Copyright 2021 Example Inc\nCopyright 2022 Example Labs"
+		modules[1].LicenseDeclared = "EPL-1.0 OR MIT"
+		modules[1].LicenseConcluded = "EPL-1.0"
+		return modules
+	}
+	render := func(outputFormat models.OutputFormat, getSource func() []models.Module) string {
+		filename := filepath.Join(t.TempDir(), "bom-Java-Maven")
+		f, err := New(Config{
+			Filename:     filename,
+			ToolVersion:  "test",
+			OutputFormat: outputFormat,
+			GetSource:    getSource,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Render())
+
+		document, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		return string(document)
+	}
+
+	var document models.Document
+	assert.NoError(t, json.Unmarshal([]byte(render(models.OutputFormatJson, licensed)), &document))
+	assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+	assert.Len(t, document.Packages, 2)
+	root, junit := document.Packages[0], document.Packages[1]
+	assert.Equal(t, "Apache-2.0", root.PackageLicenseDeclared)
+	assert.Equal(t, "Apache-2.0", root.PackageLicenseConcluded)
+	assert.Equal(t, "Copyright 2021 Example Inc\nCopyright 2022 Example Labs", root.PackageCopyrightText)
+	assert.Equal(t, "EPL-1.0 OR MIT", junit.PackageLicenseDeclared)
+	assert.Equal(t, "EPL-1.0", junit.PackageLicenseConcluded)
+	assert.Equal(t, noAssertion, junit.PackageCopyrightText, "no copyright found is no assertion")
+
+	document = models.Document{}
+	assert.NoError(t, json.Unmarshal([]byte(render(models.OutputFormatJson, testModules)), &document))
+	assert.Equal(t, noAssertion, document.Packages[1].PackageLicenseDeclared)
+	assert.Equal(t, noAssertion, document.Packages[1].PackageLicenseConcluded)
+
+	tagValue := render(models.OutputFormatSpdx, licensed)
+	assert.Contains(t, tagValue, "PackageLicenseConcluded: EPL-1.0\nPackageLicenseDeclared: EPL-1.0 OR MIT\n")
+	assert.Contains(t, tagValue, "PackageCopyrightText: <text>Copyright 2021 Example Inc\nCopyright 2022 Example Labs</text>\n")
+}
+
+func TestSortModulesKeepsSource(t *testing.T) {
+	for _, modules := range [][]models.Module{testModules(), {testModules()[1], testModules()[0]}} {
+		source := append([]models.Module{}, modules...)
+		sorted := sortModules(modules)
+		assert.Equal(t, []string{"example", "junit"}, []string{sorted[0].Name, sorted[1].Name})
+		assert.Equal(t, source, modules, "the source is rendered again for the merged document")
+	}
+}
+
+func TestRenderVerificationCode(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "pom.xml"), []byte("<project/>"), 0644))
+	expected, err := helper.GetVerificationCode(dir)
+	assert.NoError(t, err)
+
+	render := func(outputFormat models.OutputFormat, filename string) []byte {
+		f, err := New(Config{
+			Filename:     filename,
+			ToolVersion:  "test",
+			OutputFormat: outputFormat,
+			GetSource:    testModules,
+			AnalyzedPath: dir,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Render())
+		document, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		return document
+	}
+
+	tagValue := string(render(models.OutputFormatSpdx, filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")))
+	assert.Contains(t, tagValue, "FilesAnalyzed: true\nPackageVerificationCode: "+expected.Value+"\n")
+	assert.Equal(t, 1, strings.Count(tagValue, "PackageVerificationCode: "))
+
+	// rendered twice, the document the first render wrote being left out of the second
+	filename := filepath.Join(dir, "bom-Java-Maven.json")
+	render(models.OutputFormatJson, filename)
+	var document models.Document
+	assert.NoError(t, json.Unmarshal(render(models.OutputFormatJson, filename), &document))
+	assert.True(t, document.Packages[0].FilesAnalyzed)
+	assert.Equal(t, &models.PackageVerificationCode{
+		Value:         expected.Value,
+		ExcludedFiles: []string{"./bom-Java-Maven.json"},
+	}, document.Packages[0].PackageVerificationCode)
+	assert.False(t, document.Packages[1].FilesAnalyzed)
+	assert.Nil(t, document.Packages[1].PackageVerificationCode)
+
+}
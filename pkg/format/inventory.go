@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// InventoryItem is a package of the inventory format, a compact JSON array of the packages meant for programmatic
+// ingestion rather than SBOM exchange. Every field is always present, values the document asserts nothing about
+// being empty
+type InventoryItem struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Purl is the package url of the package
+	Purl string `json:"purl"`
+	// License is the concluded license expression
+	License   string              `json:"license"`
+	Checksums []InventoryChecksum `json:"checksums"`
+	// DirectParents identifies the packages depending directly on this one, by their purl, or `<name>@<version>`
+	// for the packages without one, sorted
+	DirectParents []string `json:"directParents"`
+}
+
+// InventoryChecksum is a checksum of an inventory package, e.g. `{"algorithm": "SHA1", "value": "2b8f..."}`
+type InventoryChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// InventoryRenderer implements an SPDXRenderer that outputs the packages of the document as an inventory
+type InventoryRenderer struct{}
+
+// RenderDocument lists the packages of the document in document order, their parents given by the dependency
+// relationships
+func (i InventoryRenderer) RenderDocument(document models.Document) ([]byte, error) {
+	return json.MarshalIndent(buildInventory(document), "", "\t")
+}
+
+func buildInventory(document models.Document) []InventoryItem {
+	keys := map[string]string{}
+	for _, pkg := range document.Packages {
+		keys[pkg.SPDXID] = inventoryKey(pkg)
+	}
+
+	parents := map[string][]string{}
+	for _, relationship := range document.Relationships {
+		parent, child := relationship.SPDXElementID, relationship.RelatedSPDXElement
+		switch {
+		case relationship.RelationshipType == "DEPENDS_ON":
+		case strings.HasSuffix(relationship.RelationshipType, "DEPENDENCY_OF"):
+			parent, child = child, parent
+		default:
+			continue
+		}
+		if key, ok := keys[parent]; ok {
+			if _, ok := keys[child]; ok && !containsString(parents[child], key) {
+				parents[child] = append(parents[child], key)
+			}
+		}
+	}
+
+	inventory := make([]InventoryItem, 0, len(document.Packages))
+	for _, pkg := range document.Packages {
+		item := InventoryItem{
+			Name:          pkg.PackageName,
+			Version:       pkg.PackageVersion,
+			Purl:          packagePurl(pkg),
+			License:       assertedValue(pkg.PackageLicenseConcluded),
+			Checksums:     []InventoryChecksum{},
+			DirectParents: []string{},
+		}
+		for _, checksum := range pkg.PackageChecksums {
+			item.Checksums = append(item.Checksums, InventoryChecksum{Algorithm: string(checksum.Algorithm), Value: checksum.Value})
+		}
+		item.DirectParents = append(item.DirectParents, parents[pkg.SPDXID]...)
+		sort.Strings(item.DirectParents)
+		inventory = append(inventory, item)
+	}
+	return inventory
+}
+
+// inventoryKey identifies a package by its purl, or else its name and version
+func inventoryKey(pkg models.Package) string {
+	if purl := packagePurl(pkg); purl != "" {
+		return purl
+	}
+	return pkg.PackageName + "@" + pkg.PackageVersion
+}
+
+func packagePurl(pkg models.Package) string {
+	for _, ref := range pkg.ExternalRefs {
+		if ref.ReferenceType == "purl" {
+			return ref.ReferenceLocator
+		}
+	}
+	return ""
+}
+
+// assertedValue returns the value, or nothing for NOASSERTION and NONE
+func assertedValue(value string) string {
+	if value == noAssertion || value == "NONE" {
+		return ""
+	}
+	return value
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestRenderInventory(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.inventory.json")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatInventory,
+		GetSource: func() []models.Module {
+			modules := testModules()
+			modules[1].PackageURL = "pkg:maven/junit/junit@4.13.2"
+			modules[1].LicenseDeclared = "EPL-1.0 OR MIT"
+			modules[1].LicenseConcluded = "EPL-1.0"
+			return modules
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	var inventory []InventoryItem
+	assert.NoError(t, json.Unmarshal(data, &inventory))
+	expected := []InventoryItem{
+		{
+			Name:          "example",
+			Version:       "1.0.0",
+			Checksums:     []InventoryChecksum{{Algorithm: "SHA1", Value: "c3499c2729730a7f807efb8676a92dcb6f8a3f8f"}},
+			DirectParents: []string{},
+		},
+		{
+			Name:          "junit",
+			Version:       "4.13.2",
+			Purl:          "pkg:maven/junit/junit@4.13.2",
+			License:       "EPL-1.0",
+			Checksums:     []InventoryChecksum{{Algorithm: "SHA1", Value: "2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57"}},
+			DirectParents: []string{"example@1.0.0"},
+		},
+	}
+	assert.Equal(t, expected, inventory)
+
+	// the items marshal back to the rendered inventory, every field being present
+	roundTrip, err := json.MarshalIndent(inventory, "", "\t")
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(roundTrip))
+	// the concluded license is listed, the root package with neither a purl nor a license keeping both fields
+	assert.Contains(t, string(data), `"license": "EPL-1.0"`)
+	assert.Contains(t, string(data), `"purl": ""`)
+	assert.Contains(t, string(data), `"license": ""`)
+}
+
+func TestRenderInventoryDependencyOfParents(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.inventory.json")
+	f, err := New(Config{
+		Filename:              filename,
+		OutputFormat:          models.OutputFormatInventory,
+		RelationshipDirection: RelationshipDependencyOf,
+		GetSource:             testModules,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	var inventory []InventoryItem
+	assert.NoError(t, json.Unmarshal(data, &inventory))
+	assert.Len(t, inventory, 2)
+	assert.Equal(t, []string{"example@1.0.0"}, inventory[1].DirectParents)
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Supported NTIA minimum elements modes
+const (
+	// NTIAReport fills the author and timestamp of the document and reports the missing minimum elements
+	NTIAReport = "report"
+	// NTIAStrict also fails the rendering when the supplier or the version of a package cannot be determined
+	NTIAStrict = "strict"
+)
+
+const (
+	diagnosticNTIAMissingElement = "ntia-missing-element"
+	diagnosticNTIACompliant      = "ntia-compliant"
+	diagnosticNTIANonCompliant   = "ntia-non-compliant"
+)
+
+// NTIA minimum elements checked for each package, along with the author and timestamp of the document
+const (
+	ntiaSupplier     = "supplier"
+	ntiaName         = "component name"
+	ntiaVersion      = "version"
+	ntiaIdentifier   = "unique identifier"
+	ntiaRelationship = "dependency relationship"
+	ntiaAuthor       = "author"
+	ntiaTimestamp    = "timestamp"
+)
+
+// ntiaStrictElements cannot be filled in by the tool, the strict mode fails when they are missing
+var ntiaStrictElements = map[string]bool{
+	ntiaSupplier: true,
+	ntiaVersion:  true,
+}
+
+var (
+	errUnsupportedNTIAMode = errors.New("unsupported NTIA minimum elements mode")
+	errNTIANonCompliant    = errors.New("document misses NTIA minimum elements")
+)
+
+// ntiaGap is a minimum element missing from the document, Package is empty for the document level ones
+type ntiaGap struct {
+	Package string
+	Element string
+}
+
+// ensureNTIAMinimumElements fills the timestamp of the document, then reports each missing minimum element and
+// whether the document is compliant. In strict mode, missing suppliers or versions are an error
+func (f *Format) ensureNTIAMinimumElements(document *models.Document) error {
+	if f.Config.NTIA == "" {
+		return nil
+	}
+
+	if document.CreationInfo.Created == "" {
+		document.CreationInfo.Created = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	gaps := checkNTIAMinimumElements(document)
+	var strict []string
+	for _, gap := range gaps {
+		f.Config.Diagnostics.Add(models.Diagnostic{
+			Severity: models.DiagnosticWarning,
+			Code:     diagnosticNTIAMissingElement,
+			Module:   gap.Package,
+			Message:  fmt.Sprintf("NTIA minimum element %s is missing", gap.Element),
+		})
+		if ntiaStrictElements[gap.Element] {
+			strict = append(strict, fmt.Sprintf("%s of %s", gap.Element, gap.Package))
+		}
+	}
+
+	if len(gaps) == 0 {
+		f.Config.Diagnostics.Add(models.Diagnostic{
+			Severity: models.DiagnosticInfo,
+			Code:     diagnosticNTIACompliant,
+			Message:  "document has all the NTIA minimum elements",
+		})
+		return nil
+	}
+	f.Config.Diagnostics.Add(models.Diagnostic{
+		Severity: models.DiagnosticWarning,
+		Code:     diagnosticNTIANonCompliant,
+		Message:  fmt.Sprintf("document misses %d NTIA minimum elements", len(gaps)),
+	})
+	if f.Config.NTIA == NTIAStrict && len(strict) > 0 {
+		return fmt.Errorf("%w: %s", errNTIANonCompliant, strings.Join(strict, ", "))
+	}
+	return nil
+}
+
+// buildAuthor returns the configured author of the document. In NTIA mode, it defaults to the supplier of the
+// root package, nothing being returned when the document already has a person or organization creator
+func (f *Format) buildAuthor(document *models.Document) string {
+	if f.Config.Author != "" {
+		return f.Config.Author
+	}
+	if f.Config.NTIA == "" || hasAuthor(document.CreationInfo.Creators) {
+		return ""
+	}
+	for _, pkg := range document.Packages {
+		if pkg.RootPackage && isAsserted(pkg.PackageSupplier) {
+			return pkg.PackageSupplier
+		}
+	}
+	return ""
+}
+
+// checkNTIAMinimumElements lists the minimum elements missing from the document and its packages, the package
+// representing the project sources aside
+func checkNTIAMinimumElements(document *models.Document) []ntiaGap {
+	var gaps []ntiaGap
+	if !hasAuthor(document.CreationInfo.Creators) {
+		gaps = append(gaps, ntiaGap{Element: ntiaAuthor})
+	}
+	if document.CreationInfo.Created == "" {
+		gaps = append(gaps, ntiaGap{Element: ntiaTimestamp})
+	}
+
+	related := map[string]bool{}
+	for _, relationship := range document.Relationships {
+		if relationship.SPDXElementID != document.SPDXID {
+			related[relationship.SPDXElementID] = true
+			related[relationship.RelatedSPDXElement] = true
+		}
+	}
+	for _, pkg := range document.Packages {
+		if pkg.SPDXID == sourceSPDXID {
+			continue
+		}
+		missing := func(element string) {
+			gaps = append(gaps, ntiaGap{Package: pkg.PackageName, Element: element})
+		}
+		if !isAsserted(pkg.PackageSupplier) {
+			missing(ntiaSupplier)
+		}
+		if pkg.PackageName == "" {
+			missing(ntiaName)
+		}
+		if !isAsserted(pkg.PackageVersion) {
+			missing(ntiaVersion)
+		}
+		if !hasPurl(pkg) {
+			missing(ntiaIdentifier)
+		}
+		if !pkg.RootPackage && !related[pkg.SPDXID] {
+			missing(ntiaRelationship)
+		}
+	}
+	return gaps
+}
+
+// hasAuthor tells whether a person or an organization is among the creators, the tool alone is not an author
+func hasAuthor(creators []string) bool {
+	for _, creator := range creators {
+		if strings.HasPrefix(creator, string(models.Person)+":") || strings.HasPrefix(creator, string(models.Organization)+":") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPurl(pkg models.Package) bool {
+	for _, ref := range pkg.ExternalRefs {
+		if ref.ReferenceType == "purl" && ref.ReferenceLocator != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isAsserted(value string) bool {
+	value = strings.TrimSpace(value)
+	return value != "" && value != noAssertion
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// ntiaModules returns modules with suppliers, versions and purls, the dependency of the incomplete ones having
+// neither supplier nor version
+func ntiaModules(complete bool) func() []models.Module {
+	return func() []models.Module {
+		dep := models.Module{
+			Name:       "junit",
+			Version:    "4.13.2",
+			Supplier:   models.SupplierContact{Name: "JUnit"},
+			PackageURL: "pkg:maven/junit/junit@4.13.2",
+			Modules:    map[string]*models.Module{},
+		}
+		if !complete {
+			dep.Version = ""
+			dep.Supplier = models.SupplierContact{}
+			dep.PackageURL = "pkg:maven/junit/junit"
+		}
+		return []models.Module{
+			{
+				Name:       "example",
+				Version:    "1.0.0",
+				Root:       true,
+				Supplier:   models.SupplierContact{Name: "Example Inc"},
+				PackageURL: "pkg:maven/com.example/example@1.0.0",
+				Modules:    map[string]*models.Module{"junit": &dep},
+			},
+			dep,
+		}
+	}
+}
+
+func TestNTIAMinimumElements(t *testing.T) {
+	render := func(mode string, complete bool) (models.Document, []models.Diagnostic, error) {
+		filename := filepath.Join(t.TempDir(), "bom-Java-Maven.json")
+		diagnostics := &models.Diagnostics{}
+		f, err := New(Config{
+			Filename:     filename,
+			ToolVersion:  "test",
+			OutputFormat: models.OutputFormatJson,
+			GetSource:    ntiaModules(complete),
+			Diagnostics:  diagnostics,
+			NTIA:         mode,
+		})
+		assert.NoError(t, err)
+		if err := f.Render(); err != nil {
+			return models.Document{}, diagnostics.List(), err
+		}
+
+		var document models.Document
+		data, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &document))
+		return document, diagnostics.List(), nil
+	}
+
+	document, diagnostics, err := render(NTIAReport, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tool: spdx-sbom-generator-test", "Organization: Example Inc"}, document.CreationInfo.Creators)
+	assert.NotEmpty(t, document.CreationInfo.Created)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, diagnosticNTIACompliant, diagnostics[0].Code)
+
+	_, diagnostics, err = render(NTIAReport, false)
+	assert.NoError(t, err)
+	var missing []string
+	for _, diagnostic := range diagnostics[:len(diagnostics)-1] {
+		assert.Equal(t, diagnosticNTIAMissingElement, diagnostic.Code)
+		assert.Equal(t, "junit", diagnostic.Module)
+		missing = append(missing, diagnostic.Message)
+	}
+	assert.Equal(t, []string{"NTIA minimum element supplier is missing", "NTIA minimum element version is missing"}, missing)
+	assert.Equal(t, diagnosticNTIANonCompliant, diagnostics[len(diagnostics)-1].Code)
+
+	_, _, err = render(NTIAStrict, true)
+	assert.NoError(t, err)
+	_, _, err = render(NTIAStrict, false)
+	assert.True(t, errors.Is(err, errNTIANonCompliant))
+	assert.Contains(t, err.Error(), "supplier of junit")
+
+	document, diagnostics, err = render("", false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Tool: spdx-sbom-generator-test"}, document.CreationInfo.Creators)
+	assert.Empty(t, diagnostics)
+
+	_, err = New(Config{NTIA: "lenient"})
+	assert.True(t, errors.Is(err, errUnsupportedNTIAMode))
+}
+
+func TestNTIAConfiguredAuthor(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.json")
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatJson,
+		GetSource:    testModules,
+		Author:       "Person: Jane Doe",
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	var document models.Document
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &document))
+	assert.Equal(t, []string{"Tool: spdx-sbom-generator-test", "Person: Jane Doe"}, document.CreationInfo.Creators)
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Media types of the output formats, announced by the format header
+const (
+	MediaTypeSpdx      = "text/spdx"
+	MediaTypeJson      = "application/spdx+json"
+	MediaTypeInventory = "application/vnd.spdx-sbom-generator.inventory+json"
+)
+
+const (
+	contentTypeHeader     = "Content-Type: "
+	contentEncodingHeader = "Content-Encoding: "
+	gzipEncoding          = "gzip"
+)
+
+var (
+	errUnknownFormat      = errors.New("unknown document format")
+	errSignedStreamOutput = errors.New("signed documents must be written to a file")
+	mediaTypes            = map[models.OutputFormat]string{
+		models.OutputFormatSpdx:      MediaTypeSpdx,
+		models.OutputFormatJson:      MediaTypeJson,
+		models.OutputFormatInventory: MediaTypeInventory,
+	}
+)
+
+// MediaType returns the media type of an output format
+func MediaType(outputFormat models.OutputFormat) string {
+	return mediaTypes[outputFormat]
+}
+
+// buildFormatHeader returns the `Content-Type` header preceding a streamed document, followed by its
+// `Content-Encoding` when compressed and an empty line, as in a MIME message
+func (f *Format) buildFormatHeader() []byte {
+	header := contentTypeHeader + MediaType(f.Config.OutputFormat) + "\n"
+	if f.Config.Compress {
+		header += contentEncodingHeader + gzipEncoding + "\n"
+	}
+	return []byte(header + "\n")
+}
+
+// Detect tells the format of a streamed document, along with the document. The format header is read when the
+// stream starts with one, otherwise the format is told by the document itself: the `SPDXVersion` header of the
+// tag-value documents, the `spdxVersion` field of the JSON ones, an array being an inventory
+func Detect(r io.Reader) (models.OutputFormat, []byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if bytes.HasPrefix(data, []byte(contentTypeHeader)) {
+		end := bytes.Index(data, []byte("\n\n"))
+		if end < 0 {
+			return 0, nil, fmt.Errorf("%w: unterminated format header", errUnknownFormat)
+		}
+		header, document := string(data[:end]), data[end+2:]
+		for _, line := range strings.Split(header, "\n") {
+			if !strings.HasPrefix(line, contentTypeHeader) {
+				continue
+			}
+			mediaType := strings.TrimSpace(strings.TrimPrefix(line, contentTypeHeader))
+			for outputFormat, known := range mediaTypes {
+				if known == mediaType {
+					return outputFormat, document, nil
+				}
+			}
+			return 0, nil, fmt.Errorf("%w: %s", errUnknownFormat, mediaType)
+		}
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("SPDXVersion:")):
+		return models.OutputFormatSpdx, data, nil
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var inventory []InventoryItem
+		if err := json.Unmarshal(trimmed, &inventory); err == nil {
+			return models.OutputFormatInventory, data, nil
+		}
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		var document struct {
+			SPDXVersion string `json:"spdxVersion"`
+		}
+		if err := json.Unmarshal(trimmed, &document); err == nil && strings.HasPrefix(document.SPDXVersion, "SPDX-") {
+			return models.OutputFormatJson, data, nil
+		}
+	}
+	return 0, nil, errUnknownFormat
+}
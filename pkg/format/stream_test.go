@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestDetectPipedFormats(t *testing.T) {
+	for _, outputFormat := range []models.OutputFormat{models.OutputFormatSpdx, models.OutputFormatJson, models.OutputFormatInventory} {
+		for _, header := range []bool{false, true} {
+			var piped bytes.Buffer
+			f, err := New(Config{
+				ToolVersion:  "test",
+				OutputFormat: outputFormat,
+				GetSource:    testModules,
+				Writer:       &piped,
+				FormatHeader: header,
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, f.Render())
+
+			detected, document, err := Detect(bytes.NewReader(piped.Bytes()))
+			assert.NoError(t, err)
+			assert.Equal(t, outputFormat, detected, "header %v", header)
+			assert.Contains(t, string(document), "junit")
+			if header {
+				assert.True(t, strings.HasPrefix(piped.String(), "Content-Type: "+MediaType(outputFormat)+"\n\n"))
+				assert.False(t, strings.HasPrefix(string(document), "Content-Type"))
+			}
+		}
+	}
+}
+
+func TestFormatHeaderAnnouncesCompression(t *testing.T) {
+	var piped bytes.Buffer
+	f, err := New(Config{
+		OutputFormat: models.OutputFormatJson,
+		GetSource:    testModules,
+		Writer:       &piped,
+		FormatHeader: true,
+		Compress:     true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+	assert.True(t, strings.HasPrefix(piped.String(), "Content-Type: application/spdx+json\nContent-Encoding: gzip\n\n"))
+
+	detected, _, err := Detect(&piped)
+	assert.NoError(t, err)
+	assert.Equal(t, models.OutputFormatJson, detected)
+}
+
+func TestDetectUnknownFormat(t *testing.T) {
+	for _, content := range []string{"", "{\"bomFormat\": \"CycloneDX\"}", "Content-Type: text/plain\n\nhello"} {
+		_, _, err := Detect(strings.NewReader(content))
+		assert.True(t, errors.Is(err, errUnknownFormat), content)
+	}
+}
+
+func TestSignedStreamOutput(t *testing.T) {
+	_, err := New(Config{
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    testModules,
+		Writer:       &bytes.Buffer{},
+		Signer:       func(document []byte) ([]byte, error) { return nil, nil },
+	})
+	assert.True(t, errors.Is(err, errSignedStreamOutput))
+}
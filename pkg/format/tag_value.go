@@ -4,6 +4,7 @@ package format
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"text/template"
 
@@ -18,8 +19,14 @@ DataLicense: {{ .DataLicense }}
 SPDXID: {{ .SPDXID }}
 DocumentName: {{ .DocumentName }}
 DocumentNamespace: {{ .DocumentNamespace }}
+{{- range .ExternalDocumentRefs }}
+ExternalDocumentRef: {{ .ExternalDocumentID }} {{ .SPDXDocument }} {{ .Checksum.Algorithm }}: {{ .Checksum.Value }}
+{{- end }}
 Creator: {{ range .CreationInfo.Creators }}{{ . -}} {{ end }}
 Created: {{ .CreationInfo.Created }}
+{{ with .CreationInfo.Comment -}}
+CreatorComment: {{ text . }}
+{{ end -}}
 
 {{ range .Packages }}
 ##### Package representing the {{.PackageName}}
@@ -32,25 +39,50 @@ PackageVersion: {{ . }}
 PackageSupplier: {{ .PackageSupplier }}
 PackageDownloadLocation: {{ .PackageDownloadLocation }}
 FilesAnalyzed: {{ .FilesAnalyzed }}
+{{- with .PackageVerificationCode }}
+PackageVerificationCode: {{ .String }}
+{{- end }}
 {{- range .PackageChecksums }}
 PackageChecksum: {{ .Algorithm }}: {{ .Value }}
 {{- end }}
 PackageHomePage: {{ .PackageHomePage }}
 PackageLicenseConcluded: {{ .PackageLicenseConcluded }}
 PackageLicenseDeclared: {{ .PackageLicenseDeclared }}
-PackageCopyrightText: {{ .PackageCopyrightText }}
+PackageCopyrightText: {{ text .PackageCopyrightText }}
 PackageLicenseComments: {{ .PackageLicenseComments }}
-PackageComment: {{ .PackageComment }}
+{{- with .PackageSourceInfo }}
+PackageSourceInfo: {{ text . }}
+{{- end }}
+PackageComment: {{ text .PackageComment }}
+{{- with .PrimaryPackagePurpose }}
+PrimaryPackagePurpose: {{ . }}
+{{- end }}
+{{- range .ExternalRefs }}
+ExternalRef: {{ .ReferenceCategory }} {{ .ReferenceType }} {{ .ReferenceLocator }}
+{{- end }}
 {{ end }}
 {{- range .Relationships }}
 Relationship: {{ .SPDXElementID }} {{ .RelationshipType }} {{ .RelatedSPDXElement }}
 {{- end }}
 
+{{- with .Annotations }}
+
+##### Annotations
+{{- range . }}
+
+Annotator: {{ .Annotator }}
+AnnotationDate: {{ .AnnotationDate }}
+AnnotationType: {{ .AnnotationType }}
+SPDXREF: {{ $.SPDXID }}
+AnnotationComment: <text>{{ .Comment }}</text>
+{{- end }}
+{{ end }}
+
 {{- with .ExtractedLicensingInfos -}}
 ##### Non-standard license
 {{ range . }}
 LicenseID: {{ .LicenseID }}
-ExtractedText: {{ .ExtractedText }}
+ExtractedText: {{ text .ExtractedText }}
 LicenseName: {{ .LicenseName }}
 LicenseComment: {{ .LicenseComment }}
 {{- end -}}
@@ -63,6 +95,7 @@ func (t TagValueSPDXRenderer) RenderDocument(document models.Document) ([]byte,
 		"isAsserted": func(s string) bool {
 			return !strings.Contains(s, noAssertion)
 		},
+		"text": wrapMultilineText,
 	}).Parse(tagValueTemplate)
 
 	if err != nil {
@@ -75,3 +108,11 @@ func (t TagValueSPDXRenderer) RenderDocument(document models.Document) ([]byte,
 	}
 	return templateBuffer.Bytes(), err
 }
+
+// wrapMultilineText wraps values spanning several lines in the tag value <text> delimiters
+func wrapMultilineText(s string) string {
+	if !strings.Contains(s, "\n") {
+		return s
+	}
+	return fmt.Sprintf("<text>%s</text>", s)
+}
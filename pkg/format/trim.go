@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// trimNoAssertion empties the optional package fields valued NOASSERTION, so that the JSON document omits them as it
+// omits the blank ones, NONE being an assertion. The fields the schema of the document version requires are kept
+// whatever their value: the download location, and in SPDX 2.2 the licenses and the copyright text too
+func trimNoAssertion(document *models.Document, schemaVersion string) {
+	for i := range document.Packages {
+		pkg := &document.Packages[i]
+		optional := []*string{
+			&pkg.PackageSupplier,
+			&pkg.PackageHomePage,
+			&pkg.PackageLicenseComments,
+			&pkg.PackageComment,
+			&pkg.PackageSourceInfo,
+		}
+		if schemaVersion != SchemaVersion22 {
+			optional = append(optional, &pkg.PackageLicenseConcluded, &pkg.PackageLicenseDeclared, &pkg.PackageCopyrightText)
+		}
+		for _, field := range optional {
+			if *field == noAssertion {
+				*field = ""
+			}
+		}
+	}
+}
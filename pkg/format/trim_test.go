@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// schemaRequired lists the properties the SPDX JSON schema of each version requires, by object
+var schemaRequired = map[string]map[string][]string{
+	SchemaVersion22: {
+		"document":     {"SPDXID", "creationInfo", "dataLicense", "name", "spdxVersion"},
+		"creationInfo": {"created", "creators"},
+		"package":      {"SPDXID", "copyrightText", "downloadLocation", "licenseConcluded", "licenseDeclared", "name"},
+		"relationship": {"spdxElementId", "relatedSpdxElement", "relationshipType"},
+		"checksum":     {"algorithm", "checksumValue"},
+	},
+	SchemaVersion23: {
+		"document":     {"SPDXID", "creationInfo", "dataLicense", "name", "spdxVersion", "documentNamespace"},
+		"creationInfo": {"created", "creators"},
+		"package":      {"SPDXID", "downloadLocation", "name"},
+		"relationship": {"spdxElementId", "relatedSpdxElement", "relationshipType"},
+		"checksum":     {"algorithm", "checksumValue"},
+	},
+}
+
+// assertSchemaRequired asserts that the JSON document has the properties its schema version requires
+func assertSchemaRequired(t *testing.T, schemaVersion string, document map[string]interface{}) {
+	required := schemaRequired[schemaVersion]
+	assertProperties := func(object string, value interface{}) {
+		properties, ok := value.(map[string]interface{})
+		if !assert.True(t, ok, "%s is an object", object) {
+			return
+		}
+		for _, property := range required[object] {
+			assert.Contains(t, properties, property, "%s of SPDX %s", object, schemaVersion)
+		}
+	}
+	each := func(object string, values interface{}, visit func(interface{})) {
+		items, _ := values.([]interface{})
+		for _, item := range items {
+			assertProperties(object, item)
+			if visit != nil {
+				visit(item)
+			}
+		}
+	}
+
+	assertProperties("document", document)
+	assertProperties("creationInfo", document["creationInfo"])
+	each("package", document["packages"], func(pkg interface{}) {
+		each("checksum", pkg.(map[string]interface{})["checksums"], nil)
+	})
+	each("relationship", document["relationships"], nil)
+}
+
+func TestTrimNoAssertion(t *testing.T) {
+	render := func(schemaVersion string, trim bool) map[string]interface{} {
+		filename := filepath.Join(t.TempDir(), "bom-Java-Maven.json")
+		f, err := New(Config{
+			Filename:        filename,
+			ToolVersion:     "test",
+			OutputFormat:    models.OutputFormatJson,
+			GetSource:       testModules,
+			SourceReference: "pkg:github/example/example",
+			SchemaVersion:   schemaVersion,
+			TrimNoAssertion: trim,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, f.Render())
+
+		data, err := ioutil.ReadFile(filename)
+		assert.NoError(t, err)
+		var document map[string]interface{}
+		assert.NoError(t, json.Unmarshal(data, &document))
+		return document
+	}
+	packages := func(document map[string]interface{}) []map[string]interface{} {
+		var packages []map[string]interface{}
+		for _, pkg := range document["packages"].([]interface{}) {
+			packages = append(packages, pkg.(map[string]interface{}))
+		}
+		return packages
+	}
+
+	for _, schemaVersion := range []string{SchemaVersion22, SchemaVersion23} {
+		full, trimmed := render(schemaVersion, false), render(schemaVersion, true)
+		assertSchemaRequired(t, schemaVersion, full)
+		assertSchemaRequired(t, schemaVersion, trimmed)
+
+		fullPackages, trimmedPackages := packages(full), packages(trimmed)
+		assert.Len(t, trimmedPackages, len(fullPackages))
+		for i, pkg := range fullPackages {
+			// only NOASSERTION values are omitted, the other ones are kept as is
+			for property, value := range pkg {
+				if trimmedValue, ok := trimmedPackages[i][property]; ok {
+					assert.Equal(t, value, trimmedValue, property)
+				} else {
+					assert.Equal(t, noAssertion, value, property)
+				}
+			}
+			assert.Subset(t, keys(pkg), keys(trimmedPackages[i]))
+		}
+
+		// the source package asserts nothing but its required download location
+		source := trimmedPackages[len(trimmedPackages)-1]
+		assert.Equal(t, noAssertion, source["downloadLocation"])
+		assert.NotContains(t, source, "supplier")
+		assert.NotContains(t, source, "homepage")
+		if schemaVersion == SchemaVersion22 {
+			assert.Equal(t, noAssertion, source["licenseConcluded"])
+			assert.Equal(t, noAssertion, source["copyrightText"])
+		} else {
+			assert.NotContains(t, source, "licenseConcluded")
+			assert.NotContains(t, source, "copyrightText")
+		}
+	}
+}
+
+func keys(object map[string]interface{}) []string {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// VEXSuffix names the companion component list of a document, written next to it
+const VEXSuffix = ".vex.json"
+
+// VEXStatusUnderInvestigation is the placeholder status of the components, no analysis being known yet
+const VEXStatusUnderInvestigation = "under_investigation"
+
+// VEXComponentList is the companion of a document listing its packages ready for VEX annotation, the identifiers
+// being the ones of the document so that the statements can be matched back to its packages
+type VEXComponentList struct {
+	// DocumentNamespace is the namespace of the document the components are packages of
+	DocumentNamespace string         `json:"documentNamespace"`
+	Components        []VEXComponent `json:"components"`
+}
+
+// VEXComponent is a package of the document, its status and justification to be filled in by the VEX author. Every
+// field is always present, identifiers the document has none of being empty
+type VEXComponent struct {
+	SPDXID  string `json:"spdxId"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Purl and CPE are the external references of the package, verbatim
+	Purl string `json:"purl"`
+	CPE  string `json:"cpe"`
+	// Status is one of the VEX statuses, e.g. `not_affected` or `affected`, VEXStatusUnderInvestigation until
+	// the component is analyzed
+	Status        string `json:"status"`
+	Justification string `json:"justification"`
+}
+
+// buildVEXComponentList lists the packages of the document in document order
+func buildVEXComponentList(document models.Document) VEXComponentList {
+	list := VEXComponentList{
+		DocumentNamespace: document.DocumentNamespace,
+		Components:        make([]VEXComponent, 0, len(document.Packages)),
+	}
+	for _, pkg := range document.Packages {
+		list.Components = append(list.Components, VEXComponent{
+			SPDXID:  pkg.SPDXID,
+			Name:    pkg.PackageName,
+			Version: pkg.PackageVersion,
+			Purl:    packagePurl(pkg),
+			CPE:     packageCPE(pkg),
+			Status:  VEXStatusUnderInvestigation,
+		})
+	}
+	return list
+}
+
+// renderVEXComponentList writes the companion component list of the document to the configured file
+func (f *Format) renderVEXComponentList(document models.Document) error {
+	content, err := json.MarshalIndent(buildVEXComponentList(document), "", "\t")
+	if err != nil {
+		return err
+	}
+	return writeFile(f.Config.VEXFilename, content)
+}
+
+// packageCPE returns the CPE of the package, preferring the 2.3 binding
+func packageCPE(pkg models.Package) string {
+	cpe := ""
+	for _, ref := range pkg.ExternalRefs {
+		switch {
+		case ref.ReferenceType == "cpe23Type":
+			return ref.ReferenceLocator
+		case ref.ReferenceType == "cpe22Type" && cpe == "":
+			cpe = ref.ReferenceLocator
+		}
+	}
+	return cpe
+}
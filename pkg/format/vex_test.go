@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestRenderVEXComponentList(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "bom-Java-Maven.json")
+	vexFilename := filepath.Join(dir, "bom-Java-Maven"+VEXSuffix)
+	f, err := New(Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatJson,
+		VEXFilename:  vexFilename,
+		GetSource: func() []models.Module {
+			modules := testModules()
+			modules[0].PackageURL = "pkg:maven/com.example/example@1.0.0"
+			modules[1].PackageURL = "pkg:maven/junit/junit@4.13.2?type=test-jar"
+			return modules
+		},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, f.Render())
+
+	data, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	var document models.Document
+	assert.NoError(t, json.Unmarshal(data, &document))
+
+	data, err = ioutil.ReadFile(vexFilename)
+	assert.NoError(t, err)
+	var list VEXComponentList
+	assert.NoError(t, json.Unmarshal(data, &list))
+
+	// the components are the packages of the document, identified alike
+	assert.Equal(t, document.DocumentNamespace, list.DocumentNamespace)
+	assert.Len(t, list.Components, len(document.Packages))
+	for i, pkg := range document.Packages {
+		component := list.Components[i]
+		assert.Equal(t, pkg.SPDXID, component.SPDXID)
+		assert.Equal(t, packagePurl(pkg), component.Purl)
+		assert.Equal(t, VEXStatusUnderInvestigation, component.Status)
+	}
+	assert.Equal(t, "pkg:maven/junit/junit@4.13.2?type=test-jar", list.Components[1].Purl)
+	assert.Contains(t, string(data), `"cpe": ""`)
+	assert.Contains(t, string(data), `"justification": ""`)
+}
+
+func TestVEXComponentCPE(t *testing.T) {
+	pkg := models.Package{ExternalRefs: []models.ExternalRef{
+		{ReferenceCategory: "SECURITY", ReferenceType: "cpe22Type", ReferenceLocator: "cpe:/a:junit:junit:4.13.2"},
+		{ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: "cpe:2.3:a:junit:junit:4.13.2:*:*:*:*:*:*:*"},
+	}}
+	assert.Equal(t, "cpe:2.3:a:junit:junit:4.13.2:*:*:*:*:*:*:*", packageCPE(pkg))
+
+	pkg.ExternalRefs = pkg.ExternalRefs[:1]
+	assert.Equal(t, "cpe:/a:junit:junit:4.13.2", packageCPE(pkg))
+}
@@ -5,18 +5,32 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/spdx/spdx-sbom-generator/pkg/blocklist"
 	"github.com/spdx/spdx-sbom-generator/pkg/format"
-	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules"
 )
 
 var errNoModuleManagerFound = errors.New("No module manager found")
-var errOutputDirDoesNotExist = errors.New("Output Directory does not exist")
+var errOutputDirIsNotDirectory = errors.New("Output Directory is not a directory")
+var errLicensePolicyViolated = errors.New("license policy violated")
+var errBlocklistMatched = errors.New("blocklist matched")
+
+// declaredViewSuffix names the document holding the declared view of the modules
+const declaredViewSuffix = "-declared"
+
+// mergedDocumentSlug names the document of the modules of all the package managers, see SPDXSettings.MergeDocuments
+const mergedDocumentSlug = "merged"
+
+// StdoutOutput is the output directory writing the documents to stdout, one after the other
+const StdoutOutput = "-"
 
 // SPDXSettings ...
 type SPDXSettings struct {
@@ -27,6 +41,57 @@ type SPDXSettings struct {
 	OutputDir string
 	Schema    string
 	Format    models.OutputFormat
+	Signer    format.Signer
+	Source    string
+	// DeclaredView also writes the modules as declared in the manifest, next to the effective ones
+	DeclaredView bool
+	// ScopedRelationships uses scope specific relationship types, e.g. RUNTIME_DEPENDENCY_OF
+	ScopedRelationships bool
+	// RelationshipDirection relates dependencies with DEPENDS_ON, DEPENDENCY_OF or both, see format.Config
+	RelationshipDirection string
+	// RootSPDXID is the SPDXID of the described root package, see format.Config
+	RootSPDXID string
+	// MaxPackages caps the number of packages of each document, no cap when zero
+	MaxPackages int
+	// Compress writes the documents gzip compressed, with a .gz suffix
+	Compress bool
+	// FormatHeader precedes each document written to stdout, see StdoutOutput, with its media type
+	FormatHeader bool
+	// DiagnosticAnnotations embeds the generation diagnostics as annotations of the documents, see format.Config
+	DiagnosticAnnotations bool
+	// NTIA checks the NTIA minimum elements of the documents, see format.Config
+	NTIA string
+	// Author is the person or organization creating the documents, see format.Config
+	Author string
+	// ExternalDocuments are the SBOMs of dependencies referenced by the documents, see format.Config
+	ExternalDocuments map[string]format.ExternalDocument
+	// TrimNoAssertion omits the optional NOASSERTION fields from the JSON documents, see format.Config
+	TrimNoAssertion bool
+	// VEX also writes the companion VEX component list of each document, see format.VEXComponentList
+	VEX bool
+	// VerificationCode analyzes the files of the project directory, the root package being written with their
+	// verification code, see format.Config
+	VerificationCode bool
+	// BuildEnvironment records the toolchain versions reported by the package manager in the document
+	BuildEnvironment bool
+	// LicenseTexts provides the text of LicenseRef-* licenses no text could be extracted for, keyed by LicenseRef id
+	LicenseTexts map[string]string
+	// LicensePolicy flags the modules whose concluded license is not allowed or is denied
+	LicensePolicy licenses.Policy
+	// FailOnLicenseViolation does not write the document of a package manager with license policy violations
+	FailOnLicenseViolation bool
+	// Blocklist flags the modules matching known-bad coordinates or typosquat patterns as error diagnostics
+	Blocklist blocklist.List
+	// FailOnBlocklistMatch does not write the document of a package manager with modules matching the blocklist
+	FailOnBlocklistMatch bool
+	// Parallelism is the number of package managers run at the same time, all the detected ones when zero
+	Parallelism int
+	// MergeDocuments writes the modules of all the package managers to a single document, rooted at the project
+	// directory, instead of one document per package manager
+	MergeDocuments bool
+	// DryRun logs a preview of the documents read statically from the manifests, without running the package
+	// managers, hashing files or writing documents
+	DryRun bool
 }
 
 type spdxHandler struct {
@@ -44,19 +109,64 @@ func getFiletypeForOutputFormat(outputFormat models.OutputFormat) string {
 		return "spdx"
 	case models.OutputFormatJson:
 		return "json"
+	case models.OutputFormatInventory:
+		return "inventory.json"
 	default:
 		return "spdx"
 	}
 }
 
+// outputPath returns the path of a document in the output directory, suffixed when it is compressed, or stdout
+func (sh *spdxHandler) outputPath(filename string) string {
+	if sh.config.OutputDir == StdoutOutput {
+		return "stdout"
+	}
+	if sh.config.Compress {
+		filename += format.GzipSuffix
+	}
+	return filepath.Join(sh.config.OutputDir, filename)
+}
+
+// vexPath returns the path of the companion VEX component list of a package manager document, in the current
+// directory when the documents are written to stdout. Nothing when it is not written
+func (sh *spdxHandler) vexPath(slug string) string {
+	if !sh.config.VEX {
+		return ""
+	}
+	filename := fmt.Sprintf("bom-%s%s", slug, format.VEXSuffix)
+	if sh.config.OutputDir == StdoutOutput {
+		return filename
+	}
+	return filepath.Join(sh.config.OutputDir, filename)
+}
+
+// analyzedPath returns the project directory whose files the root package is written with the verification code of,
+// nothing unless configured
+func (sh *spdxHandler) analyzedPath() string {
+	if !sh.config.VerificationCode {
+		return ""
+	}
+	return sh.config.Path
+}
+
+// writer returns stdout when the documents are written there rather than to the output directory
+func (sh *spdxHandler) writer() io.Writer {
+	if sh.config.OutputDir == StdoutOutput {
+		return os.Stdout
+	}
+	return nil
+}
+
 // NewSPDX ...
 func NewSPDX(settings SPDXSettings) (Handler, error) {
-	if !helper.Exists(settings.OutputDir) {
-		return nil, errOutputDirDoesNotExist
+	// a missing output directory is created when writing the documents
+	if info, err := os.Stat(settings.OutputDir); err == nil && !info.IsDir() {
+		return nil, errOutputDirIsNotDirectory
 	}
 
 	mm, err := modules.New(modules.Config{
-		Path: settings.Path,
+		Path:   settings.Path,
+		DryRun: settings.DryRun,
 	})
 	if err != nil {
 		return nil, err
@@ -76,36 +186,203 @@ func (sh *spdxHandler) Run() error {
 		return errNoModuleManagerFound
 	}
 
+	if sh.config.DryRun {
+		for _, mm := range sh.modulesManager {
+			plugin := mm.Plugin.GetMetadata()
+			sh.preview(mm, sh.outputPath(sh.documentFilename(plugin.Slug)))
+		}
+		return nil
+	}
+
 	for _, mm := range sh.modulesManager {
-		plugin := mm.Plugin.GetMetadata()
-		filename := fmt.Sprintf("bom-%s.%s", plugin.Slug, getFiletypeForOutputFormat(sh.config.Format))
-		outputFile := filepath.Join(sh.config.OutputDir, filename)
+		log.Infof("Running generator for Module Manager: `%s`", mm.Plugin.GetMetadata().Slug)
+	}
+	for slug, err := range modules.RunAll(sh.modulesManager, sh.config.Parallelism) {
+		sh.errors[slug] = err
+	}
 
-		log.Infof("Running generator for Module Manager: `%s` with output `%s`", plugin.Slug, outputFile)
-		if err := mm.Run(); err != nil {
-			sh.errors[plugin.Slug] = err
+	var merged []*modules.Manager
+	var mergedEnvironment []models.Property
+	var mergedDiagnostics []models.Diagnostic
+	for _, mm := range sh.modulesManager {
+		plugin := mm.Plugin.GetMetadata()
+		if _, failed := sh.errors[plugin.Slug]; failed {
 			continue
 		}
-
-		format, err := format.New(format.Config{
-			Filename:     outputFile,
-			ToolVersion:  sh.config.Version,
-			OutputFormat: sh.config.Format,
-			GetSource: func() []models.Module {
-				return mm.GetSource()
-			},
-		})
-		if err != nil {
-			sh.errors[plugin.Slug] = err
+		for _, diagnostic := range mm.GetDiagnostics() {
+			log.Warnf("Plugin %s reported %s `%s`: %s", plugin.Slug, diagnostic.Severity, diagnostic.Code, diagnostic.Message)
+		}
+		blocked := sh.config.Blocklist.Check(mm.GetSource())
+		for _, diagnostic := range blocked {
+			log.Errorf("Plugin %s module %s: %s", plugin.Slug, diagnostic.Module, diagnostic.Message)
+		}
+		if len(blocked) > 0 && sh.config.FailOnBlocklistMatch {
+			sh.errors[plugin.Slug] = fmt.Errorf("%w by %d modules", errBlocklistMatched, len(blocked))
 			continue
 		}
-		if err := format.Render(); err != nil {
+		violations := sh.config.LicensePolicy.Evaluate(mm.GetSource())
+		for _, violation := range violations {
+			log.Warnf("Plugin %s module %s %s violates the license policy: %s", plugin.Slug, violation.Module, violation.Version, violation.Message)
+		}
+		if len(violations) > 0 && sh.config.FailOnLicenseViolation {
+			sh.errors[plugin.Slug] = fmt.Errorf("%w by %d modules", errLicensePolicyViolated, len(violations))
+			continue
+		}
+
+		var environment []models.Property
+		if sh.config.BuildEnvironment {
+			properties, err := mm.GetBuildEnvironment()
+			if err != nil {
+				log.Warnf("Plugin %s build environment is not recorded: %v", plugin.Slug, err)
+			}
+			environment = properties
+		}
+
+		pluginDiagnostics := append(mm.GetDiagnostics(), blocked...)
+		if sh.config.MergeDocuments {
+			merged = append(merged, mm)
+			mergedEnvironment = append(mergedEnvironment, environment...)
+			mergedDiagnostics = append(mergedDiagnostics, pluginDiagnostics...)
+		} else if err := sh.renderDocument(plugin.Slug, mm.GetSource, environment, pluginDiagnostics); err != nil {
 			sh.errors[plugin.Slug] = err
 			continue
 		}
-		sh.outputFiles[plugin.Slug] = outputFile
+
+		if sh.config.DeclaredView {
+			if err := sh.renderDeclaredView(mm); err != nil {
+				sh.errors[plugin.Slug+declaredViewSuffix] = err
+			}
+		}
+	}
+
+	if len(merged) > 0 {
+		source := modules.MergeSources(sh.config.Path, merged)
+		getSource := func() []models.Module {
+			return source
+		}
+		if err := sh.renderDocument(mergedDocumentSlug, getSource, mergedEnvironment, mergedDiagnostics); err != nil {
+			sh.errors[mergedDocumentSlug] = err
+		}
+	}
+
+	return nil
+}
+
+// documentFilename is the name of the document of a package manager, or of the merged document
+func (sh *spdxHandler) documentFilename(slug string) string {
+	return fmt.Sprintf("bom-%s.%s", slug, getFiletypeForOutputFormat(sh.config.Format))
+}
+
+// renderDocument writes the document of the modules of a package manager, or of the merged modules of several
+func (sh *spdxHandler) renderDocument(slug string, getSource func() []models.Module, environment []models.Property, pluginDiagnostics []models.Diagnostic) error {
+	outputFile := sh.outputPath(sh.documentFilename(slug))
+	log.Infof("Writing the document of `%s` to `%s`", slug, outputFile)
+
+	diagnostics := &models.Diagnostics{}
+	format, err := format.New(format.Config{
+		Filename:              outputFile,
+		ToolVersion:           sh.config.Version,
+		OutputFormat:          sh.config.Format,
+		Signer:                sh.config.Signer,
+		SourceReference:       sh.config.Source,
+		ScopedRelationships:   sh.config.ScopedRelationships,
+		RelationshipDirection: sh.config.RelationshipDirection,
+		BuildEnvironment:      environment,
+		LicenseTexts:          sh.config.LicenseTexts,
+		SchemaVersion:         sh.config.Schema,
+		MaxPackages:           sh.config.MaxPackages,
+		RootSPDXID:            sh.config.RootSPDXID,
+		Diagnostics:           diagnostics,
+		PluginDiagnostics:     pluginDiagnostics,
+		DiagnosticAnnotations: sh.config.DiagnosticAnnotations,
+		Compress:              sh.config.Compress,
+		Writer:                sh.writer(),
+		FormatHeader:          sh.config.FormatHeader,
+		TrimNoAssertion:       sh.config.TrimNoAssertion,
+		NTIA:                  sh.config.NTIA,
+		Author:                sh.config.Author,
+		ExternalDocuments:     sh.config.ExternalDocuments,
+		VEXFilename:           sh.vexPath(slug),
+		AnalyzedPath:          sh.analyzedPath(),
+		GetSource:             getSource,
+	})
+	if err != nil {
+		return err
+	}
+	if err := format.Render(); err != nil {
+		return err
+	}
+	for _, diagnostic := range diagnostics.List() {
+		log.Warnf("Document %s reported %s `%s`: %s", outputFile, diagnostic.Severity, diagnostic.Code, diagnostic.Message)
+	}
+	sh.outputFiles[slug] = outputFile
+	return nil
+}
+
+// preview logs the document a package manager would generate and the expensive steps generating it would run
+func (sh *spdxHandler) preview(mm *modules.Manager, outputFile string) {
+	plugin := mm.Plugin.GetMetadata()
+	preview, err := mm.GetPreview()
+	if err != nil {
+		sh.errors[plugin.Slug] = err
+		return
+	}
+
+	log.Infof("Dry run of Module Manager: `%s` with output `%s` in %s format, SPDX %s", plugin.Slug, outputFile, getFiletypeForOutputFormat(sh.config.Format), sh.config.Schema)
+	log.Infof("Plugin %s root module %s declares %d packages", plugin.Slug, preview.Root, preview.PackageCount)
+	if sh.config.DeclaredView {
+		log.Infof("Plugin %s declared view would be written next to the output", plugin.Slug)
+	}
+	for _, step := range preview.Steps {
+		if step.Run {
+			log.Infof("Plugin %s would run %s: %s", plugin.Slug, step.Name, step.Detail)
+		} else {
+			log.Infof("Plugin %s would skip %s: %s", plugin.Slug, step.Name, step.Detail)
+		}
+	}
+}
+
+// renderDeclaredView writes a second document listing the modules as authored in the project manifest,
+// so they can be compared with the effective modules resolved by the package manager
+func (sh *spdxHandler) renderDeclaredView(mm *modules.Manager) error {
+	declared, err := mm.GetDeclaredSource()
+	if err != nil {
+		return err
 	}
 
+	plugin := mm.Plugin.GetMetadata()
+	outputFile := sh.outputPath(sh.documentFilename(plugin.Slug + declaredViewSuffix))
+	format, err := format.New(format.Config{
+		Filename:              outputFile,
+		ToolVersion:           sh.config.Version,
+		OutputFormat:          sh.config.Format,
+		Signer:                sh.config.Signer,
+		ScopedRelationships:   sh.config.ScopedRelationships,
+		RelationshipDirection: sh.config.RelationshipDirection,
+		MaxPackages:           sh.config.MaxPackages,
+		RootSPDXID:            sh.config.RootSPDXID,
+		DiagnosticAnnotations: sh.config.DiagnosticAnnotations,
+		Compress:              sh.config.Compress,
+		Writer:                sh.writer(),
+		FormatHeader:          sh.config.FormatHeader,
+		TrimNoAssertion:       sh.config.TrimNoAssertion,
+		NTIA:                  sh.config.NTIA,
+		Author:                sh.config.Author,
+		ExternalDocuments:     sh.config.ExternalDocuments,
+		LicenseTexts:          sh.config.LicenseTexts,
+		SchemaVersion:         sh.config.Schema,
+		AnalyzedPath:          sh.analyzedPath(),
+		GetSource: func() []models.Module {
+			return declared
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := format.Render(); err != nil {
+		return err
+	}
+	sh.outputFiles[plugin.Slug+declaredViewSuffix] = outputFile
 	return nil
 }
 
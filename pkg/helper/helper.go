@@ -3,6 +3,7 @@
 package helper
 
 import (
+	"archive/zip"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,6 +22,9 @@ import (
 
 const copyrightLookup = "copyright"
 
+// noticeFiles are the attribution file names looked up at a module root or inside a jar
+var noticeFiles = []string{"NOTICE", "NOTICE.txt", "NOTICE.md"}
+
 // copyright matching level of prefernece
 const (
 	matchLevel1 = iota
@@ -171,6 +175,67 @@ func GetCopyright(content string) string {
 	return cr.get()
 }
 
+// GetNotice reads the NOTICE attribution file found at the module path, if any
+func GetNotice(modulePath string) string {
+	for _, name := range noticeFiles {
+		path := filepath.Join(modulePath, name)
+		if !Exists(path) {
+			continue
+		}
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Errorf("Could not read notice file: %v", err)
+			continue
+		}
+		return string(bytes)
+	}
+	return ""
+}
+
+// GetJarNotice reads the NOTICE attribution file bundled under META-INF of a jar archive, if any
+func GetJarNotice(jarPath string) string {
+	archive, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return ""
+	}
+	defer archive.Close()
+
+	for _, name := range noticeFiles {
+		for _, f := range archive.File {
+			if f.Name != "META-INF/"+name {
+				continue
+			}
+			r, err := f.Open()
+			if err != nil {
+				return ""
+			}
+			bytes, err := ioutil.ReadAll(r)
+			r.Close()
+			if err != nil {
+				return ""
+			}
+			return string(bytes)
+		}
+	}
+	return ""
+}
+
+// MergeCopyright joins copyright statements gathered from LICENSE and NOTICE files,
+// dropping empty and duplicated entries while keeping the original order
+func MergeCopyright(copyrights ...string) string {
+	seen := map[string]bool{}
+	merged := []string{}
+	for _, c := range copyrights {
+		c = strings.TrimSpace(c)
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		merged = append(merged, c)
+	}
+	return strings.Join(merged, "\n")
+}
+
 // BuildManifestContent builds a content with directory tree
 func BuildManifestContent(path string) []byte {
 	manifest := []FileInfo{}
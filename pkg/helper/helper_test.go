@@ -3,6 +3,8 @@
 package helper
 
 import (
+	"archive/zip"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -37,3 +39,31 @@ func getPath() string {
 
 	return path
 }
+
+func TestGetCopyrightWithNotice(t *testing.T) {
+	fixturePath := filepath.Join(getPath(), "testdata", "notice")
+	license := reader.New(filepath.Join(fixturePath, "LICENSE")).StringFromFile()
+
+	licenseCopyright := GetCopyright(license)
+	noticeCopyright := GetCopyright(GetNotice(fixturePath))
+	assert.Equal(t, "Copyright (c) 2021 Example Corp", licenseCopyright)
+	assert.Equal(t, "Copyright 2015-2021 The Apache Software Foundation", noticeCopyright)
+
+	res := MergeCopyright(licenseCopyright, noticeCopyright, licenseCopyright, "")
+	assert.Equal(t, "Copyright (c) 2021 Example Corp\nCopyright 2015-2021 The Apache Software Foundation", res)
+}
+
+func TestGetJarNotice(t *testing.T) {
+	jarPath := filepath.Join(t.TempDir(), "example.jar")
+	f, err := os.Create(jarPath)
+	assert.NoError(t, err)
+	w := zip.NewWriter(f)
+	entry, err := w.Create("META-INF/NOTICE")
+	assert.NoError(t, err)
+	entry.Write([]byte("Copyright 2019 Example Corp\n"))
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+
+	assert.Equal(t, "Copyright 2019 Example Corp", GetCopyright(GetJarNotice(jarPath)))
+	assert.Equal(t, "", GetJarNotice(filepath.Join(t.TempDir(), "missing.jar")))
+}
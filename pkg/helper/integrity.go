@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// integrityAlgorithms are the hash algorithms of the subresource integrity strings, the strongest first
+var integrityAlgorithms = []struct {
+	prefix    string
+	algorithm models.HashAlgorithm
+}{
+	{"sha512-", models.HashAlgoSHA512},
+	{"sha384-", models.HashAlgoSHA384},
+	{"sha256-", models.HashAlgoSHA256},
+	{"sha1-", models.HashAlgoSHA1},
+}
+
+// ParseIntegrity returns the strongest hash of a subresource integrity string, e.g. `sha512-<base64>`, as a
+// hexadecimal checksum, as recorded by the npm, yarn and pnpm lockfiles. Nothing is returned without a valid hash
+func ParseIntegrity(integrity string) *models.CheckSum {
+	hashes := strings.Fields(integrity)
+	for _, candidate := range integrityAlgorithms {
+		for _, hash := range hashes {
+			if !strings.HasPrefix(hash, candidate.prefix) {
+				continue
+			}
+			// options may follow the digest, e.g. `sha512-<base64>?foo`
+			digest := strings.SplitN(strings.TrimPrefix(hash, candidate.prefix), "?", 2)[0]
+			sum, err := base64.StdEncoding.DecodeString(digest)
+			if err != nil {
+				continue
+			}
+			return &models.CheckSum{Algorithm: candidate.algorithm, Value: hex.EncodeToString(sum)}
+		}
+	}
+	return nil
+}
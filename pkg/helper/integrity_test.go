@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestParseIntegrity(t *testing.T) {
+	sha512Sum := sha512.Sum512([]byte("package"))
+	sha1Sum := sha1.Sum([]byte("package"))
+	strong := "sha512-" + base64.StdEncoding.EncodeToString(sha512Sum[:])
+	weak := "sha1-" + base64.StdEncoding.EncodeToString(sha1Sum[:])
+	expected := &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: hex.EncodeToString(sha512Sum[:])}
+
+	// the strongest hash wins whatever its position
+	assert.Equal(t, expected, ParseIntegrity(weak+" "+strong))
+	// the options following the digest are left out
+	assert.Equal(t, expected, ParseIntegrity(strong+"?foo"))
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: hex.EncodeToString(sha1Sum[:])}, ParseIntegrity("sha512-!invalid "+weak))
+	assert.Nil(t, ParseIntegrity("md5-abc"))
+	assert.Nil(t, ParseIntegrity(""))
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// GetVerificationCode computes the SPDX package verification code of the files under root: the SHA1 of the
+// sorted SHA1 checksums of the files, concatenated. The excluded files, given relative to root such as
+// `./bom.spdx` or `bom.spdx`, are left out of the code and listed in it
+func GetVerificationCode(root string, excluded ...string) (models.PackageVerificationCode, error) {
+	excludes := map[string]bool{}
+	for _, file := range excluded {
+		excludes[verificationPath(file)] = true
+	}
+
+	var checksums, excludedFiles []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if name := verificationPath(rel); excludes[name] {
+			excludedFiles = append(excludedFiles, name)
+			return nil
+		}
+
+		checksum, err := fileSHA1(path)
+		if err != nil {
+			return err
+		}
+		checksums = append(checksums, checksum)
+		return nil
+	})
+	if err != nil {
+		return models.PackageVerificationCode{}, err
+	}
+
+	sort.Strings(checksums)
+	sort.Strings(excludedFiles)
+	sum := sha1.Sum([]byte(strings.Join(checksums, "")))
+	return models.PackageVerificationCode{
+		Value:         hex.EncodeToString(sum[:]),
+		ExcludedFiles: excludedFiles,
+	}, nil
+}
+
+// verificationPath returns the `./` prefixed slash separated form SPDX lists the files of a package with
+func verificationPath(file string) string {
+	file = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(file)), "./")
+	return "./" + file
+}
+
+func fileSHA1(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
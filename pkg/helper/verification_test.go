@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVerificationCodeExcludesFiles(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "lib"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "README"), []byte("readme\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "lib", "core.jar"), []byte("core\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "bom.spdx"), []byte("SPDXVersion: SPDX-2.3\n"), 0644))
+
+	// the sha1 of the sorted sha1 of `readme\n` and `core\n`
+	sum := sha1.Sum([]byte("0753dda729217d9bd892d252bdd35f2ee6774a5b" + "e29cd8d329d3c6826e9660b51325bee22e037838"))
+	expected := hex.EncodeToString(sum[:])
+
+	code, err := GetVerificationCode(root, "./bom.spdx")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, code.Value)
+	assert.Equal(t, []string{"./bom.spdx"}, code.ExcludedFiles)
+	assert.Equal(t, expected+" (excludes: ./bom.spdx)", code.String())
+
+	withSBOM, err := GetVerificationCode(root)
+	assert.NoError(t, err)
+	assert.NotEqual(t, expected, withSBOM.Value)
+	assert.Empty(t, withSBOM.ExcludedFiles)
+	assert.Equal(t, withSBOM.Value, withSBOM.String())
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// fileMode is the mode of the files written by WriteFileAtomic
+const fileMode os.FileMode = 0644
+
+// WriteFileAtomic writes a file through a temporary file created in the same directory,
+// renaming it over the target only once write has succeeded and the content is flushed to disk.
+// On failure the temporary file is removed so no partially written target is left behind.
+// Missing parent directories are created, with permissions subject to the umask. The file is
+// written with mode 0644, as by ioutil.WriteFile, rather than the 0600 of the temporary file.
+func WriteFileAtomic(filename string, write func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", dir, err)
+	}
+
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(filename)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	buffer := bufio.NewWriter(tmp)
+	if err = write(buffer); err != nil {
+		return err
+	}
+	if err = buffer.Flush(); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(fileMode); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package helper
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "bom.spdx")
+
+	err := WriteFileAtomic(target, func(w io.Writer) error {
+		_, err := w.Write([]byte("SPDXVersion: SPDX-2.2\n"))
+		return err
+	})
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "SPDXVersion: SPDX-2.2\n", string(content))
+
+	// readable by others, unlike the temporary file it is renamed from
+	info, err := os.Stat(target)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+}
+
+func TestWriteFileAtomic_WriteError(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "bom.spdx")
+	errWrite := errors.New("disk full")
+
+	err := WriteFileAtomic(target, func(w io.Writer) error {
+		w.Write([]byte("SPDXVersion: SPD"))
+		return errWrite
+	})
+	assert.Equal(t, errWrite, err)
+	assert.False(t, Exists(target))
+
+	entries, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestWriteFileAtomic_CreatesParentDirectories(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "reports", "2021", "bom.spdx")
+
+	err := WriteFileAtomic(target, func(w io.Writer) error {
+		_, err := w.Write([]byte("SPDXVersion: SPDX-2.3\n"))
+		return err
+	})
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "SPDXVersion: SPDX-2.3\n", string(content))
+}
+
+func TestWriteFileAtomic_ParentIsFile(t *testing.T) {
+	dir := t.TempDir()
+	parent := filepath.Join(dir, "reports")
+	assert.NoError(t, ioutil.WriteFile(parent, []byte{}, 0644))
+
+	err := WriteFileAtomic(filepath.Join(parent, "bom.spdx"), func(w io.Writer) error {
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to create output directory")
+}
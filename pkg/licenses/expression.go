@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errEmptyExpression = errors.New("empty license expression")
+
+// Expression is a parsed SPDX license expression, see https://spdx.github.io/spdx-spec/appendix-IV-SPDX-license-expressions/
+type Expression struct {
+	// Operator is AND or OR for compound expressions, empty for a single license
+	Operator string
+	// License is the license identifier, along with its `WITH` exception if any
+	License  string
+	Operands []*Expression
+}
+
+// ParseExpression parses an SPDX license expression, `WITH` binds tighter than `AND` which binds tighter than `OR`
+func ParseExpression(expression string) (*Expression, error) {
+	p := expressionParser{tokens: tokenizeExpression(expression)}
+	if len(p.tokens) == 0 {
+		return nil, errEmptyExpression
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected `%s` in license expression `%s`", p.tokens[p.pos], expression)
+	}
+	return expr, nil
+}
+
+func tokenizeExpression(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(expression)
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *expressionParser) parseOr() (*Expression, error) {
+	return p.parseCompound("OR", p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (*Expression, error) {
+	return p.parseCompound("AND", p.parseLicense)
+}
+
+func (p *expressionParser) parseCompound(operator string, operand func() (*Expression, error)) (*Expression, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	operands := []*Expression{first}
+	for strings.EqualFold(p.peek(), operator) {
+		p.pos++
+		next, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, next)
+	}
+
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return &Expression{Operator: operator, Operands: operands}, nil
+}
+
+func (p *expressionParser) parseLicense() (*Expression, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, errors.New("license expression ends unexpectedly")
+	case token == "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing `)` in license expression")
+		}
+		p.pos++
+		return expr, nil
+	case token == ")" || isOperator(token):
+		return nil, fmt.Errorf("unexpected `%s` in license expression", token)
+	}
+
+	p.pos++
+	license := token
+	if strings.EqualFold(p.peek(), "WITH") {
+		p.pos++
+		exception := p.peek()
+		if exception == "" || exception == "(" || exception == ")" || isOperator(exception) {
+			return nil, fmt.Errorf("missing exception after `%s WITH`", license)
+		}
+		p.pos++
+		license = fmt.Sprintf("%s WITH %s", license, exception)
+	}
+	return &Expression{License: license}, nil
+}
+
+func isOperator(token string) bool {
+	return strings.EqualFold(token, "AND") || strings.EqualFold(token, "OR") || strings.EqualFold(token, "WITH")
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"strings"
+)
+
+// licenseURLs maps the well-known license urls, without scheme and trailing slash, to their SPDX identifier
+var licenseURLs = map[string]string{
+	"www.apache.org/licenses/license-2.0":       "Apache-2.0",
+	"www.apache.org/licenses/license-2.0.txt":   "Apache-2.0",
+	"www.apache.org/licenses/license-2.0.html":  "Apache-2.0",
+	"www.eclipse.org/legal/epl-v10.html":        "EPL-1.0",
+	"www.eclipse.org/legal/epl-2.0":             "EPL-2.0",
+	"www.eclipse.org/org/documents/edl-v10.php": "BSD-3-Clause",
+	"www.gnu.org/licenses/lgpl-2.1.html":        "LGPL-2.1-only",
+	"www.gnu.org/licenses/lgpl-3.0.html":        "LGPL-3.0-only",
+	"www.gnu.org/licenses/gpl-2.0.html":         "GPL-2.0-only",
+	"www.gnu.org/licenses/gpl-3.0.html":         "GPL-3.0-only",
+	"www.mozilla.org/mpl/2.0":                   "MPL-2.0",
+}
+
+// licenseURLPrefixes are the license list sites whose urls end with the SPDX identifier
+var licenseURLPrefixes = []string{"opensource.org/licenses/", "spdx.org/licenses/"}
+
+// licenseAliases maps common license names that differ from the SPDX full names, lower cased
+var licenseAliases = map[string]string{
+	"the apache software license, version 2.0": "Apache-2.0",
+	"apache license, version 2.0":              "Apache-2.0",
+	"apache software license - version 2.0":    "Apache-2.0",
+	"apache 2.0":                               "Apache-2.0",
+	"apache 2":                                 "Apache-2.0",
+	"the mit license":                          "MIT",
+	"mit license":                              "MIT",
+	"eclipse public license - v 1.0":           "EPL-1.0",
+	"eclipse public license - v 2.0":           "EPL-2.0",
+	"eclipse distribution license - v 1.0":     "BSD-3-Clause",
+	"the bsd license":                          "BSD-3-Clause",
+	"new bsd license":                          "BSD-3-Clause",
+}
+
+// Normalize returns the SPDX identifier of a license given by its identifier, in any case, its full or
+// common name, or its url
+func Normalize(license string) (string, bool) {
+	license = strings.TrimSpace(license)
+	if license == "" {
+		return "", false
+	}
+	if _, ok := DB[license]; ok {
+		return license, true
+	}
+
+	lower := strings.ToLower(license)
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
+		return normalizeURL(lower)
+	}
+	if id, ok := licenseAliases[lower]; ok {
+		return id, true
+	}
+	for id, name := range DB {
+		if strings.EqualFold(id, license) || strings.EqualFold(name, license) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func normalizeURL(url string) (string, bool) {
+	url = strings.TrimPrefix(strings.TrimPrefix(url, "http://"), "https://")
+	url = strings.TrimSuffix(url, "/")
+	if id, ok := licenseURLs[url]; ok {
+		return id, true
+	}
+	if id, ok := licenseURLs["www."+url]; ok {
+		return id, true
+	}
+
+	for _, prefix := range licenseURLPrefixes {
+		if i := strings.Index(url, prefix); i >= 0 {
+			id := strings.TrimSuffix(strings.TrimSuffix(url[i+len(prefix):], ".html"), ".php")
+			for known := range DB {
+				if strings.EqualFold(known, id) {
+					return known, true
+				}
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Apache-2.0": "Apache-2.0",
+		"apache-2.0": "Apache-2.0",
+		"The Apache Software License, Version 2.0": "Apache-2.0",
+		"MIT License": "MIT",
+		"https://www.apache.org/licenses/LICENSE-2.0": "Apache-2.0",
+		"http://opensource.org/licenses/BSD-2-Clause": "BSD-2-Clause",
+		"https://spdx.org/licenses/epl-2.0.html":      "EPL-2.0",
+	}
+	for license, expected := range tests {
+		id, ok := Normalize(license)
+		assert.True(t, ok, license)
+		assert.Equal(t, expected, id, license)
+	}
+
+	for _, license := range []string{"", "Proprietary", "https://example.com/license"} {
+		_, ok := Normalize(license)
+		assert.False(t, ok, license)
+	}
+}
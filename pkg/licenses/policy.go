@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const noAssertion = "NOASSERTION"
+
+// ViolationReason tells why a license does not comply with a policy
+type ViolationReason string
+
+// ViolationReason values
+const (
+	ViolationDenied            ViolationReason = "denied"
+	ViolationNotAllowed        ViolationReason = "not-allowed"
+	ViolationInvalidExpression ViolationReason = "invalid-expression"
+)
+
+// Policy approves or forbids license identifiers. A license complies when it is not denied and, if an
+// allowlist is given, when it is allowed. Identifiers are matched case insensitively, a license with an
+// exception (`X WITH Y`) is matched as a whole first and then by its license identifier
+type Policy struct {
+	Allowed []string
+	Denied  []string
+}
+
+// Violation is a module whose concluded license does not comply with the policy
+type Violation struct {
+	Module  string          `json:"module"`
+	Version string          `json:"version,omitempty"`
+	License string          `json:"license"`
+	Reason  ViolationReason `json:"reason"`
+	Message string          `json:"message"`
+}
+
+// IsEmpty tells whether the policy has no rules, in which case every license complies
+func (p Policy) IsEmpty() bool {
+	return len(p.Allowed) == 0 && len(p.Denied) == 0
+}
+
+// Evaluate checks the concluded license of the modules. Compound expressions comply when all the operands
+// of an AND comply, and when any operand of an OR does
+func (p Policy) Evaluate(modules []models.Module) []Violation {
+	var violations []Violation
+	if p.IsEmpty() {
+		return violations
+	}
+
+	for _, module := range modules {
+		license := strings.TrimSpace(module.LicenseConcluded)
+		if license == "" {
+			license = noAssertion
+		}
+		violation := Violation{
+			Module:  module.Name,
+			Version: module.Version,
+			License: license,
+		}
+
+		expr, err := ParseExpression(license)
+		if err != nil {
+			if len(p.Allowed) == 0 {
+				continue
+			}
+			violation.Reason = ViolationInvalidExpression
+			violation.Message = fmt.Sprintf("license `%s` cannot be checked against the allowlist: %v", license, err)
+			violations = append(violations, violation)
+			continue
+		}
+
+		if reason, offending, ok := p.check(expr); !ok {
+			violation.Reason = reason
+			violation.Message = fmt.Sprintf("license `%s` is %s", offending, reason)
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// check returns whether the expression complies, and otherwise why and because of which license
+func (p Policy) check(expr *Expression) (ViolationReason, string, bool) {
+	switch expr.Operator {
+	case "AND":
+		for _, operand := range expr.Operands {
+			if reason, offending, ok := p.check(operand); !ok {
+				return reason, offending, false
+			}
+		}
+		return "", "", true
+	case "OR":
+		reason, offending := ViolationReason(""), ""
+		for i, operand := range expr.Operands {
+			r, o, ok := p.check(operand)
+			if ok {
+				return "", "", true
+			}
+			if i == 0 {
+				reason, offending = r, o
+			}
+		}
+		return reason, offending, false
+	}
+
+	license := expr.License
+	for _, candidate := range []string{license, strings.Fields(license)[0]} {
+		if matchLicense(p.Denied, candidate) {
+			return ViolationDenied, license, false
+		}
+		if matchLicense(p.Allowed, candidate) {
+			return "", "", true
+		}
+	}
+	if len(p.Allowed) > 0 {
+		return ViolationNotAllowed, license, false
+	}
+	return "", "", true
+}
+
+func matchLicense(list []string, license string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), license) {
+			return true
+		}
+	}
+	return false
+}
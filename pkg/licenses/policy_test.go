@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func concluded(licenses ...string) []models.Module {
+	var modules []models.Module
+	for _, license := range licenses {
+		modules = append(modules, models.Module{Name: license, LicenseConcluded: license})
+	}
+	return modules
+}
+
+func violatingLicenses(violations []Violation) map[string]ViolationReason {
+	reasons := map[string]ViolationReason{}
+	for _, violation := range violations {
+		reasons[violation.License] = violation.Reason
+	}
+	return reasons
+}
+
+func TestPolicyAllowlist(t *testing.T) {
+	policy := Policy{Allowed: []string{"Apache-2.0", "MIT"}}
+	violations := policy.Evaluate(concluded("Apache-2.0", "mit", "GPL-3.0-only", "", "LicenseRef-Custom"))
+
+	assert.Equal(t, map[string]ViolationReason{
+		"GPL-3.0-only":      ViolationNotAllowed,
+		"NOASSERTION":       ViolationNotAllowed,
+		"LicenseRef-Custom": ViolationNotAllowed,
+	}, violatingLicenses(violations))
+}
+
+func TestPolicyDenylist(t *testing.T) {
+	policy := Policy{Denied: []string{"GPL-3.0-only", "AGPL-3.0-only"}}
+	violations := policy.Evaluate(concluded("Apache-2.0", "GPL-3.0-only", "NOASSERTION", "agpl-3.0-only"))
+
+	assert.Equal(t, map[string]ViolationReason{
+		"GPL-3.0-only":  ViolationDenied,
+		"agpl-3.0-only": ViolationDenied,
+	}, violatingLicenses(violations))
+	assert.Equal(t, "license `GPL-3.0-only` is denied", violations[0].Message)
+}
+
+func TestPolicyExpressions(t *testing.T) {
+	policy := Policy{
+		Allowed: []string{"Apache-2.0", "MIT", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		Denied:  []string{"GPL-2.0-only", "GPL-3.0-only"},
+	}
+	violations := policy.Evaluate(concluded(
+		"GPL-3.0-only OR MIT",
+		"(GPL-3.0-only OR Apache-2.0) AND MIT",
+		"MIT AND GPL-3.0-only",
+		"GPL-2.0-only WITH Classpath-exception-2.0",
+		"GPL-2.0-only",
+		"MIT OR BSD-3-Clause",
+		"BSD-3-Clause OR ISC",
+		"MIT AND (Apache-2.0",
+	))
+
+	assert.Equal(t, map[string]ViolationReason{
+		"MIT AND GPL-3.0-only": ViolationDenied,
+		"GPL-2.0-only":         ViolationDenied,
+		"BSD-3-Clause OR ISC":  ViolationNotAllowed,
+		"MIT AND (Apache-2.0":  ViolationInvalidExpression,
+	}, violatingLicenses(violations))
+}
+
+func TestParseExpression(t *testing.T) {
+	expr, err := ParseExpression("MIT OR (Apache-2.0 AND GPL-2.0-or-later WITH Classpath-exception-2.0)")
+	assert.NoError(t, err)
+	assert.Equal(t, &Expression{Operator: "OR", Operands: []*Expression{
+		{License: "MIT"},
+		{Operator: "AND", Operands: []*Expression{
+			{License: "Apache-2.0"},
+			{License: "GPL-2.0-or-later WITH Classpath-exception-2.0"},
+		}},
+	}}, expr)
+
+	for _, invalid := range []string{"", "MIT OR", "AND MIT", "(MIT", "MIT)", "MIT WITH"} {
+		_, err := ParseExpression(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
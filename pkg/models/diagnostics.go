@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"sync"
+)
+
+// DiagnosticSeverity ...
+type DiagnosticSeverity string
+
+const (
+	DiagnosticInfo    DiagnosticSeverity = "info"
+	DiagnosticWarning DiagnosticSeverity = "warning"
+	DiagnosticError   DiagnosticSeverity = "error"
+)
+
+// Diagnostic describes a quality caveat found while a plugin builds its modules,
+// e.g. a dependency whose version could not be resolved
+type Diagnostic struct {
+	Severity DiagnosticSeverity `json:"severity"`
+	Code     string             `json:"code"`
+	Module   string             `json:"module,omitempty"`
+	Message  string             `json:"message"`
+}
+
+// Diagnostics collects diagnostics and is safe for concurrent use
+type Diagnostics struct {
+	mu    sync.Mutex
+	items []Diagnostic
+}
+
+// Add ...
+func (d *Diagnostics) Add(diagnostic Diagnostic) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.items = append(d.items, diagnostic)
+}
+
+// List returns a copy of the collected diagnostics in the order they were added
+func (d *Diagnostics) List() []Diagnostic {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Diagnostic{}, d.items...)
+}
+
+// IDiagnosticsPlugin is implemented by plugins able to report the diagnostics of their last run
+type IDiagnosticsPlugin interface {
+	GetDiagnostics() []Diagnostic
+}
@@ -24,6 +24,24 @@ type IPlugin interface {
 	HasModulesInstalled(path string) error
 }
 
+// IDeclaredModulesPlugin is implemented by plugins able to list the modules as authored in the
+// project manifest, without resolving them through the package manager
+type IDeclaredModulesPlugin interface {
+	ListDeclaredModules(path string) ([]Module, error)
+}
+
+// IBuildEnvironmentPlugin is implemented by plugins able to describe the toolchain used to build the project,
+// e.g. the package manager, runtime and OS versions
+type IBuildEnvironmentPlugin interface {
+	GetBuildEnvironment(path string) ([]Property, error)
+}
+
+// IInventoryPlugin is implemented by plugins able to list the resolved modules without linking them to their
+// transitive dependencies, skipping the expensive dependency tree resolution
+type IInventoryPlugin interface {
+	ListInventory(path string) ([]Module, error)
+}
+
 // PluginMetadata ...
 type PluginMetadata struct {
 	Name       string
@@ -36,11 +54,13 @@ type PluginMetadata struct {
 type Module struct {
 	Version                 string `json:"Version,omitempty"`
 	Name                    string
+	Group                   string `json:"Group,omitempty"`
 	Path                    string `json:"Path,omitempty"`
 	LocalPath               string `json:"Dir,noempty"`
 	Supplier                SupplierContact
 	PackageURL              string
 	CheckSum                *CheckSum
+	AdditionalCheckSums     []CheckSum
 	PackageHomePage         string
 	PackageDownloadLocation string
 	LicenseConcluded        string
@@ -49,10 +69,48 @@ type Module struct {
 	OtherLicense            []*License
 	Copyright               string
 	PackageComment          string
+	SourceInfo              string `json:"SourceInfo,omitempty"`
+	Properties              []Property
+	Scope                   string `json:"Scope,omitempty"`
+	Purpose                 string `json:"Purpose,omitempty"`
 	Root                    bool
 	Modules                 map[string]*Module
 }
 
+// Module purposes, as the SPDX primary package purpose
+const (
+	PurposeApplication = "APPLICATION"
+	PurposeLibrary     = "LIBRARY"
+	PurposeOther       = "OTHER"
+)
+
+// Property is a named piece of metadata recorded on a module, e.g. where the plugin discovered it
+type Property struct {
+	Name  string
+	Value string
+}
+
+// GetProperty returns the value of the first property with the given name
+func (m Module) GetProperty(name string) string {
+	for _, p := range m.Properties {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// SetProperty records a property on the module, replacing any previous value with the same name
+func (m *Module) SetProperty(name, value string) {
+	for i := range m.Properties {
+		if m.Properties[i].Name == name {
+			m.Properties[i].Value = value
+			return
+		}
+	}
+	m.Properties = append(m.Properties, Property{Name: name, Value: value})
+}
+
 // SupplierContact ...
 type SupplierContact struct {
 	Type            TypeContact
@@ -155,4 +213,6 @@ type OutputFormat int
 const (
 	OutputFormatSpdx OutputFormat = iota
 	OutputFormatJson
+	// OutputFormatInventory is the compact JSON package inventory, see format.InventoryItem
+	OutputFormatInventory
 )
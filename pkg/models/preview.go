@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+// Preview describes the SBOM a plugin expects to generate, read statically from the project manifest
+type Preview struct {
+	// Root is the coordinate of the root module, e.g. `group:artifact:version`
+	Root string
+	// PackageCount is the number of packages declared in the manifest, the resolved ones may add transitive ones
+	PackageCount int
+	// Steps lists the expensive steps of the generation, such as running the package manager or hashing files
+	Steps []PreviewStep
+}
+
+// PreviewStep is an expensive step of the generation and whether it would run with the current configuration
+type PreviewStep struct {
+	Name   string
+	Run    bool
+	Detail string
+}
+
+// IPreviewPlugin is implemented by plugins able to preview their SBOM without running the package manager
+// or hashing files
+type IPreviewPlugin interface {
+	Preview(path string) (Preview, error)
+}
@@ -2,24 +2,33 @@
 
 package models
 
+import (
+	"fmt"
+	"strings"
+)
+
 // Package
 // JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
 // and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json)
 type Package struct {
-	PackageName             string            `json:"name,omitempty"`
-	SPDXID                  string            `json:"SPDXID,omitempty"`
-	PackageVersion          string            `json:"versionInfo,omitempty"`
-	PackageSupplier         string            `json:"supplier,omitempty"`
-	PackageDownloadLocation string            `json:"downloadLocation,omitempty"`
-	FilesAnalyzed           bool              `json:"filesAnalyzed"`
-	PackageChecksums        []PackageChecksum `json:"checksums"`
-	PackageHomePage         string            `json:"homepage,omitempty"`
-	PackageLicenseConcluded string            `json:"licenseConcluded,omitempty"`
-	PackageLicenseDeclared  string            `json:"licenseDeclared,omitempty"`
-	PackageCopyrightText    string            `json:"copyrightText,omitempty"`
-	PackageLicenseComments  string            `json:"licenseComments,omitempty"`
-	PackageComment          string            `json:"comment,omitempty"`
-	RootPackage             bool              `json:"-"`
+	PackageName             string                   `json:"name,omitempty"`
+	SPDXID                  string                   `json:"SPDXID,omitempty"`
+	PackageVersion          string                   `json:"versionInfo,omitempty"`
+	PackageSupplier         string                   `json:"supplier,omitempty"`
+	PackageDownloadLocation string                   `json:"downloadLocation,omitempty"`
+	FilesAnalyzed           bool                     `json:"filesAnalyzed"`
+	PackageChecksums        []PackageChecksum        `json:"checksums,omitempty"`
+	PackageVerificationCode *PackageVerificationCode `json:"packageVerificationCode,omitempty"`
+	PackageHomePage         string                   `json:"homepage,omitempty"`
+	PackageLicenseConcluded string                   `json:"licenseConcluded,omitempty"`
+	PackageLicenseDeclared  string                   `json:"licenseDeclared,omitempty"`
+	PackageCopyrightText    string                   `json:"copyrightText,omitempty"`
+	PackageLicenseComments  string                   `json:"licenseComments,omitempty"`
+	PackageComment          string                   `json:"comment,omitempty"`
+	PackageSourceInfo       string                   `json:"sourceInfo,omitempty"`
+	PrimaryPackagePurpose   string                   `json:"primaryPackagePurpose,omitempty"` // SPDX 2.3
+	ExternalRefs            []ExternalRef            `json:"externalRefs,omitempty"`
+	RootPackage             bool                     `json:"-"`
 }
 
 // Document
@@ -31,10 +40,13 @@ type Document struct {
 	SPDXID                  string                   `json:"SPDXID,omitempty"`
 	DocumentName            string                   `json:"name,omitempty"`
 	DocumentNamespace       string                   `json:"documentNamespace,omitempty"`
+	ExternalDocumentRefs    []ExternalDocumentRef    `json:"externalDocumentRefs,omitempty"`
 	CreationInfo            CreationInfo             `json:"creationInfo,omitempty"`
+	DocumentDescribes       []string                 `json:"documentDescribes,omitempty"`
 	Packages                []Package                `json:"packages,omitempty"`
 	Relationships           []Relationship           `json:"relationships,omitempty"`
 	ExtractedLicensingInfos []ExtractedLicensingInfo `json:"hasExtractedLicensingInfos,omitempty"`
+	Annotations             []Annotation             `json:"annotations,omitempty"`
 }
 
 // CreationInfo
@@ -73,3 +85,47 @@ type PackageChecksum struct {
 	Algorithm HashAlgorithm `json:"algorithm"`
 	Value     string        `json:"checksumValue"`
 }
+
+// ExternalDocumentRef
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type ExternalDocumentRef struct {
+	ExternalDocumentID string          `json:"externalDocumentId"`
+	SPDXDocument       string          `json:"spdxDocument"`
+	Checksum           PackageChecksum `json:"checksum"`
+}
+
+// ExternalRef
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type ExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// Annotation
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type Annotation struct {
+	Annotator      string `json:"annotator"`
+	AnnotationDate string `json:"annotationDate"`
+	AnnotationType string `json:"annotationType"`
+	Comment        string `json:"comment"`
+}
+
+// PackageVerificationCode
+// JSON tags annotated from official example (https://github.com/spdx/spdx-spec/blob/v2.2.2/examples/SPDXJSONExample-v2.2.spdx.json)
+// and official schema (https://github.com/spdx/spdx-spec/blob/v2.2.2/schemas/spdx-schema.json
+type PackageVerificationCode struct {
+	Value         string   `json:"packageVerificationCodeValue"`
+	ExcludedFiles []string `json:"packageVerificationCodeExcludedFiles,omitempty"`
+}
+
+// String returns the tag value form of the code, `<code> (excludes: ./file, ...)` when files are excluded
+func (c PackageVerificationCode) String() string {
+	if len(c.ExcludedFiles) == 0 {
+		return c.Value
+	}
+	return fmt.Sprintf("%s (excludes: %s)", c.Value, strings.Join(c.ExcludedFiles, ", "))
+}
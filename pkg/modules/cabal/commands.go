@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+type command string
+
+var (
+	CabalVersionCmd command = "cabal --version"
+	StackVersionCmd command = "stack --version"
+	FreezeFileName  string  = "cabal.project.freeze"
+	StackLockName   string  = "stack.yaml.lock"
+	PackageYAMLName string  = "package.yaml"
+	HackageURL      string  = "https://hackage.haskell.org/package"
+)
+
+// Parse ...
+func (c command) Parse() []string {
+	cmd := strings.TrimSpace(string(c))
+	return strings.Fields(cmd)
+}
+
+func (m *cabal) buildCmd(cmd command, path string) error {
+	cmdArgs := cmd.Parse()
+	if cmdArgs[0] != "cabal" && cmdArgs[0] != "stack" {
+		return errNoHaskellCommand
+	}
+
+	command := helper.NewCmd(helper.CmdOptions{
+		Name:      cmdArgs[0],
+		Args:      cmdArgs[1:],
+		Directory: path,
+	})
+
+	m.command = command
+
+	return command.Build()
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"errors"
+)
+
+type errType error
+
+var errDependenciesNotFound = errors.New("no cabal.project.freeze or stack.yaml.lock found. Please pin the dependencies before running spdx-sbom-generator, e.g.: `cabal freeze` or `stack build`")
+var errNoHaskellCommand = errors.New("no Cabal or Stack command")
+var errFailedToReadFreezeFile errType = errors.New("failed to read cabal.project.freeze")
+var errFailedToReadStackLock errType = errors.New("failed to read stack.yaml.lock")
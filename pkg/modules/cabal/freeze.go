@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// versionConstraintPattern matches the pinned version constraints of a freeze file, e.g. `any.aeson ==2.0.3.0`.
+// Flag constraints such as `aeson -cffi` pin no version
+var versionConstraintPattern = regexp.MustCompile(`^(?:(?:any|setup|[A-Za-z0-9-]+:setup)\.)?([A-Za-z0-9][A-Za-z0-9-]*)\s*==\s*([0-9][0-9.]*)$`)
+
+// readFreezeFile reads the packages pinned by the constraints field of cabal.project.freeze, in their order. The
+// freeze file records no hashes, so the modules have no checksum
+func readFreezeFile(path string, p project) ([]models.Module, error) {
+	file, err := os.Open(filepath.Join(path, FreezeFileName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadFreezeFile, err)
+	}
+	defer file.Close()
+
+	var constraints []string
+	inConstraints := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		if inConstraints && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			constraints = append(constraints, trimmed)
+			continue
+		}
+		inConstraints = false
+		if strings.HasPrefix(strings.ToLower(trimmed), "constraints:") {
+			inConstraints = true
+			constraints = append(constraints, strings.TrimSpace(trimmed[len("constraints:"):]))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadFreezeFile, err)
+	}
+
+	var pinned []models.Module
+	seen := map[string]bool{}
+	for _, constraint := range strings.Split(strings.Join(constraints, ","), ",") {
+		match := versionConstraintPattern.FindStringSubmatch(strings.TrimSpace(constraint))
+		if match == nil || seen[match[1]] {
+			continue
+		}
+		seen[match[1]] = true
+		pinned = append(pinned, hackageModule(match[1], match[2]))
+	}
+	return linkProject(p, pinned), nil
+}
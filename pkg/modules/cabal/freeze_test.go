@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func requiredNames(module models.Module) []string {
+	names := make([]string, 0, len(module.Modules))
+	for name := range module.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestReadFreezeFile(t *testing.T) {
+	path := filepath.Join("testdata", "cabal")
+	plugin := New()
+	assert.True(t, plugin.IsValid(path))
+	assert.NoError(t, plugin.SetRootModule(path))
+	assert.False(t, plugin.stack)
+
+	modules, err := plugin.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 6)
+
+	root := modules[0]
+	assert.Equal(t, "greeter", root.Name)
+	assert.Equal(t, "0.2.0.0", root.Version)
+	assert.Equal(t, "pkg:hackage/greeter@0.2.0.0", root.PackageURL)
+	assert.Equal(t, []string{"aeson", "base", "text"}, requiredNames(root))
+
+	var pinned []string
+	for _, module := range modules[1:] {
+		pinned = append(pinned, module.Name+"@"+module.Version)
+		assert.Nil(t, module.CheckSum, "the freeze file records no hashes")
+	}
+	assert.Equal(t, []string{"aeson@2.0.3.0", "base@4.14.3.0", "hashable@1.3.5.0", "Cabal@3.2.1.0", "text@1.2.4.1"}, pinned)
+
+	aeson := modules[1]
+	assert.Equal(t, "pkg:hackage/aeson@2.0.3.0", aeson.PackageURL)
+	assert.Equal(t, "https://hackage.haskell.org/package/aeson-2.0.3.0/aeson-2.0.3.0.tar.gz", aeson.PackageDownloadLocation)
+	assert.Equal(t, "https://hackage.haskell.org/package/aeson", aeson.PackageHomePage)
+}
+
+func TestReadMissingFreezeFile(t *testing.T) {
+	_, err := readFreezeFile(t.TempDir(), project{})
+	assert.True(t, errors.Is(err, errFailedToReadFreezeFile))
+	assert.True(t, errors.Is(New().HasModulesInstalled(t.TempDir()), errDependenciesNotFound))
+}
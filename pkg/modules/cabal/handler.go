@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type cabal struct {
+	metadata models.PluginMetadata
+	command  *helper.Cmd
+	// stack tells the project is pinned by stack.yaml.lock rather than cabal.project.freeze
+	stack bool
+}
+
+// New ...
+func New() *cabal {
+	return &cabal{
+		metadata: models.PluginMetadata{
+			Name:       "Haskell Cabal and Stack",
+			Slug:       "Cabal",
+			Manifest:   []string{StackLockName, FreezeFileName},
+			ModulePath: []string{"."},
+		},
+	}
+}
+
+// GetMetadata ...
+func (m *cabal) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// IsValid ...
+func (m *cabal) IsValid(path string) bool {
+	for i := range m.metadata.Manifest {
+		if helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModulesInstalled checks the dependencies are pinned, the stack lockfile or the cabal freeze file describing them
+func (m *cabal) HasModulesInstalled(path string) error {
+	if m.IsValid(path) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// GetVersion returns the version of stack for the projects pinned by stack.yaml.lock, of cabal otherwise
+func (m *cabal) GetVersion() (string, error) {
+	cmd := CabalVersionCmd
+	if m.stack {
+		cmd = StackVersionCmd
+	}
+	if err := m.buildCmd(cmd, "."); err != nil {
+		return "", err
+	}
+
+	return m.command.Output()
+}
+
+// SetRootModule ...
+func (m *cabal) SetRootModule(path string) error {
+	m.stack = helper.Exists(filepath.Join(path, StackLockName))
+	return nil
+}
+
+// GetRootModule ...
+func (m *cabal) GetRootModule(path string) (*models.Module, error) {
+	root := readProject(path).module()
+	return &root, nil
+}
+
+// ListUsedModules ...
+func (m *cabal) ListUsedModules(path string) ([]models.Module, error) {
+	return m.ListModulesWithDeps(path)
+}
+
+// ListModulesWithDeps reads the pinned packages of stack.yaml.lock, or else of cabal.project.freeze, after the root
+// module described by the package description of the project
+func (m *cabal) ListModulesWithDeps(path string) ([]models.Module, error) {
+	p := readProject(path)
+	if helper.Exists(filepath.Join(path, StackLockName)) {
+		return readStackLock(path, p)
+	}
+	return readFreezeFile(path, p)
+}
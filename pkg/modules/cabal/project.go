@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// packageNamePattern matches the package name leading a dependency, e.g. `aeson` in `aeson ^>=2.0`
+var packageNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*`)
+
+// project is the package of the project directory, described by its .cabal file or its hpack package.yaml
+type project struct {
+	path    string
+	name    string
+	version string
+	// depends are the names of the packages it depends on, in any of its components
+	depends []string
+}
+
+// PackageYAML is the hpack description of a package, its dependencies being listed at the top level or per
+// component
+type PackageYAML struct {
+	Name         string                          `yaml:"name"`
+	Version      string                          `yaml:"version"`
+	Dependencies []string                        `yaml:"dependencies"`
+	Library      PackageYAMLComponent            `yaml:"library"`
+	Executables  map[string]PackageYAMLComponent `yaml:"executables"`
+}
+
+// PackageYAMLComponent is a component of an hpack package
+type PackageYAMLComponent struct {
+	Dependencies []string `yaml:"dependencies"`
+}
+
+// readProject reads the first .cabal file of the project, or else its package.yaml. The project is named after its
+// directory when neither describes it
+func readProject(path string) project {
+	p := project{path: path}
+	cabalFiles, _ := filepath.Glob(filepath.Join(path, "*.cabal"))
+	sort.Strings(cabalFiles)
+	if len(cabalFiles) > 0 {
+		p.readCabalFile(cabalFiles[0])
+	} else if data, err := ioutil.ReadFile(filepath.Join(path, PackageYAMLName)); err == nil {
+		var pkg PackageYAML
+		if yaml.Unmarshal(data, &pkg) == nil {
+			p.name, p.version = pkg.Name, pkg.Version
+			dependencies := append(pkg.Dependencies, pkg.Library.Dependencies...)
+			for _, executable := range pkg.Executables {
+				dependencies = append(dependencies, executable.Dependencies...)
+			}
+			p.addDepends(dependencies...)
+		}
+	}
+	if p.name == "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			p.name = filepath.Base(abs)
+		}
+	}
+	return p
+}
+
+// readCabalFile reads the top level name and version fields and the build-depends fields of all the components,
+// continued on the lines indented deeper than the field
+func (p *project) readCabalFile(cabalFile string) {
+	file, err := os.Open(cabalFile)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	dependsIndent := -1
+	var depends []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if dependsIndent >= 0 && indent > dependsIndent {
+			depends = append(depends, trimmed)
+			continue
+		}
+		dependsIndent = -1
+
+		field := strings.SplitN(trimmed, ":", 2)
+		if len(field) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(field[1])
+		switch name := strings.ToLower(strings.TrimSpace(field[0])); {
+		case name == "name" && indent == 0:
+			p.name = value
+		case name == "version" && indent == 0:
+			p.version = value
+		case name == "build-depends":
+			dependsIndent = indent
+			depends = append(depends, value)
+		}
+	}
+	p.addDepends(strings.Split(strings.Join(depends, ","), ",")...)
+}
+
+// addDepends records the package names of dependencies given with their version constraints
+func (p *project) addDepends(dependencies ...string) {
+	known := map[string]bool{}
+	for _, name := range p.depends {
+		known[name] = true
+	}
+	for _, dependency := range dependencies {
+		name := packageNamePattern.FindString(strings.TrimSpace(dependency))
+		if name != "" && !known[name] {
+			known[name] = true
+			p.depends = append(p.depends, name)
+		}
+	}
+}
+
+// module is the root module of the project
+func (p project) module() models.Module {
+	root := models.Module{
+		Name:       p.name,
+		Version:    p.version,
+		Root:       true,
+		PackageURL: buildPurl(p.name, p.version),
+		Supplier:   models.SupplierContact{Name: p.name},
+		LocalPath:  p.path,
+		Modules:    map[string]*models.Module{},
+	}
+	if licensePkg, err := helper.GetLicenses(p.path); err == nil {
+		root.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
+		root.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
+		root.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
+		root.CommentsLicense = licensePkg.Comments
+	}
+	return root
+}
+
+// hackageModule is the module of a Hackage package, downloaded from its Hackage tarball
+func hackageModule(name, version string) models.Module {
+	return models.Module{
+		Name:                    name,
+		Version:                 version,
+		PackageURL:              buildPurl(name, version),
+		Supplier:                models.SupplierContact{Name: name},
+		PackageHomePage:         HackageURL + "/" + url.PathEscape(name),
+		PackageDownloadLocation: hackageTarball(name, version),
+		Modules:                 map[string]*models.Module{},
+	}
+}
+
+// hackageTarball returns the location of the source tarball of a Hackage package version
+func hackageTarball(name, version string) string {
+	if version == "" {
+		return ""
+	}
+	nameVersion := url.PathEscape(name + "-" + version)
+	return HackageURL + "/" + nameVersion + "/" + nameVersion + ".tar.gz"
+}
+
+// hackagePurl builds the Hackage package urls
+var hackagePurl = purl.Builder{Type: "hackage"}
+
+// buildPurl returns the `pkg:hackage/<name>@<version>` package url
+func buildPurl(name, version string) string {
+	return hackagePurl.Build("", name, version, nil)
+}
+
+// linkProject builds the modules list of the root module followed by the pinned modules, the root module being
+// linked to the ones of the project dependencies
+func linkProject(p project, pinned []models.Module) []models.Module {
+	modules := make([]models.Module, 0, len(pinned)+1)
+	modules = append(modules, p.module())
+	modules = append(modules, pinned...)
+
+	index := map[string]int{}
+	for i := 1; i < len(modules); i++ {
+		index[modules[i].Name] = i
+	}
+	for _, name := range p.depends {
+		if i, ok := index[name]; ok && name != p.name {
+			modules[0].Modules[name] = &modules[i]
+		}
+	}
+	return modules
+}
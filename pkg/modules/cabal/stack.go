@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	sourceProperty   = "source"
+	snapshotProperty = "snapshot"
+	sourceHackage    = "hackage"
+	sourceGit        = "git"
+	sourceArchive    = "archive"
+)
+
+// hackageLocationPattern matches the completed hackage locations, `<name>-<version>[@sha256:<hash>,<size>]` or
+// `<name>-<version>@rev:<revision>`
+var hackageLocationPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)-([0-9][0-9.]*)(?:@(?:sha256:([0-9a-fA-F]+)(?:,\d+)?|rev:\d+))?$`)
+
+// StackLock is stack.yaml.lock, pinning the extra dependencies and the snapshot of stack.yaml
+type StackLock struct {
+	Packages  []StackLockPackage  `yaml:"packages"`
+	Snapshots []StackLockSnapshot `yaml:"snapshots"`
+}
+
+// StackLockPackage is an extra dependency, as written in stack.yaml and as completed by stack
+type StackLockPackage struct {
+	Completed StackLockLocation `yaml:"completed"`
+}
+
+// StackLockLocation is a completed package location, on hackage, in a git repository or in an archive
+type StackLockLocation struct {
+	Hackage string `yaml:"hackage"`
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Git     string `yaml:"git"`
+	Commit  string `yaml:"commit"`
+	URL     string `yaml:"url"`
+	SHA256  string `yaml:"sha256"`
+	Subdir  string `yaml:"subdir"`
+}
+
+// StackLockSnapshot is the resolver snapshot, e.g. `lts-19.0`, or a mapping giving its url
+type StackLockSnapshot struct {
+	Original yaml.Node `yaml:"original"`
+}
+
+// readStackLock reads the extra dependencies pinned by stack.yaml.lock, in their order. The hackage ones are
+// checksummed by the SHA-256 of their cabal file revision, the archives by the one of the archive. The packages of
+// the snapshot are not listed in the lockfile, the snapshot is recorded as a property of the root module
+func readStackLock(path string, p project) ([]models.Module, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, StackLockName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadStackLock, err)
+	}
+	var lock StackLock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadStackLock, err)
+	}
+
+	var pinned []models.Module
+	for _, pkg := range lock.Packages {
+		if mod, ok := convertStackLocation(pkg.Completed); ok {
+			pinned = append(pinned, mod)
+		}
+	}
+
+	modules := linkProject(p, pinned)
+	for _, snapshot := range lock.Snapshots {
+		if name := snapshotName(snapshot.Original); name != "" {
+			modules[0].SetProperty(snapshotProperty, name)
+		}
+	}
+	return modules, nil
+}
+
+// convertStackLocation builds the module of a completed location
+func convertStackLocation(location StackLockLocation) (models.Module, bool) {
+	if location.Hackage != "" {
+		match := hackageLocationPattern.FindStringSubmatch(strings.TrimSpace(location.Hackage))
+		if match == nil {
+			return models.Module{}, false
+		}
+		mod := hackageModule(match[1], match[2])
+		mod.SetProperty(sourceProperty, sourceHackage)
+		if match[3] != "" {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: strings.ToLower(match[3])}
+		}
+		return mod, true
+	}
+	if location.Name == "" {
+		return models.Module{}, false
+	}
+
+	mod := models.Module{
+		Name:       location.Name,
+		Version:    location.Version,
+		PackageURL: buildPurl(location.Name, location.Version),
+		Supplier:   models.SupplierContact{Name: location.Name},
+		Modules:    map[string]*models.Module{},
+	}
+	switch {
+	case location.Git != "":
+		mod.SetProperty(sourceProperty, sourceGit)
+		mod.PackageDownloadLocation = "git+" + location.Git + "@" + location.Commit
+		if location.Subdir != "" {
+			mod.PackageDownloadLocation += "#" + location.Subdir
+		}
+	case location.URL != "":
+		mod.SetProperty(sourceProperty, sourceArchive)
+		mod.PackageDownloadLocation = location.URL
+		if location.SHA256 != "" {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: location.SHA256}
+		}
+	}
+	return mod, true
+}
+
+// snapshotName returns the name of a resolver snapshot, or its url
+func snapshotName(original yaml.Node) string {
+	if original.Kind == yaml.ScalarNode {
+		return original.Value
+	}
+	var location struct {
+		URL string `yaml:"url"`
+	}
+	if original.Decode(&location) == nil {
+		return location.URL
+	}
+	return ""
+}
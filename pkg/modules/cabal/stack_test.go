@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cabal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestReadStackLock(t *testing.T) {
+	path := filepath.Join("testdata", "stack")
+	plugin := New()
+	assert.NoError(t, plugin.SetRootModule(path))
+	assert.True(t, plugin.stack)
+
+	modules, err := plugin.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 4)
+
+	root := modules[0]
+	assert.Equal(t, "rockets", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Equal(t, "lts-19.0", root.GetProperty(snapshotProperty))
+	assert.Equal(t, []string{"acme-missiles", "servant-extras"}, requiredNames(root))
+
+	missiles := modules[1]
+	assert.Equal(t, "acme-missiles", missiles.Name)
+	assert.Equal(t, "0.3", missiles.Version)
+	assert.Equal(t, "pkg:hackage/acme-missiles@0.3", missiles.PackageURL)
+	assert.Equal(t, sourceHackage, missiles.GetProperty(sourceProperty))
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "2ba66a092a32593880a87fb00f3213762d7bca65a687d45965778deb8694c5d1"}, missiles.CheckSum)
+
+	extras := modules[2]
+	assert.Equal(t, "servant-extras", extras.Name)
+	assert.Equal(t, "0.1.0.0", extras.Version)
+	assert.Equal(t, "git+https://github.com/example/servant-extras.git@4f7d2bb1c3a5e6f7a8b9c0d1e2f3a4b5c6d7e8f9", extras.PackageDownloadLocation)
+	assert.Nil(t, extras.CheckSum)
+
+	vendored := modules[3]
+	assert.Equal(t, sourceArchive, vendored.GetProperty(sourceProperty))
+	assert.Equal(t, "https://example.com/vendored-1.0.0.tar.gz", vendored.PackageDownloadLocation)
+	assert.Equal(t, "9c1a7b3e5d2f4a6b8c0d1e3f5a7b9c2d4e6f8a0b1c3d5e7f9a2b4c6d8e0f1a3b", vendored.CheckSum.Value)
+}
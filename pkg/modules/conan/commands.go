@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+type command string
+
+var (
+	VersionCmd   command = "conan --version"
+	LockFileName string  = "conan.lock"
+)
+
+// Parse ...
+func (c command) Parse() []string {
+	cmd := strings.TrimSpace(string(c))
+	return strings.Fields(cmd)
+}
+
+func (m *conan) buildCmd(cmd command, path string) error {
+	cmdArgs := cmd.Parse()
+	if cmdArgs[0] != "conan" {
+		return errNoConanCommand
+	}
+
+	command := helper.NewCmd(helper.CmdOptions{
+		Name:      cmdArgs[0],
+		Args:      cmdArgs[1:],
+		Directory: path,
+	})
+
+	m.command = command
+
+	return command.Build()
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"errors"
+)
+
+type errType error
+
+var errDependenciesNotFound = errors.New("no lockfile found. Please lock the dependencies before running spdx-sbom-generator, e.g.: `conan lock create conanfile.txt`")
+var errNoConanCommand = errors.New("no Conan command")
+var errFailedToReadLockFile errType = errors.New("failed to read conan.lock")
+var errUnsupportedLockFile errType = errors.New("unsupported conan.lock format, neither a graph lock nor requires are recorded")
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type conan struct {
+	metadata models.PluginMetadata
+	command  *helper.Cmd
+}
+
+// New ...
+func New() *conan {
+	return &conan{
+		metadata: models.PluginMetadata{
+			Name:       "Conan Package Manager",
+			Slug:       "Conan",
+			Manifest:   []string{LockFileName},
+			ModulePath: []string{"."},
+		},
+	}
+}
+
+// GetMetadata ...
+func (m *conan) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// IsValid ...
+func (m *conan) IsValid(path string) bool {
+	for i := range m.metadata.Manifest {
+		if helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModulesInstalled checks the dependencies are locked, the lockfile alone describes them
+func (m *conan) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFileName)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// GetVersion ...
+func (m *conan) GetVersion() (string, error) {
+	if err := m.buildCmd(VersionCmd, "."); err != nil {
+		return "", err
+	}
+
+	return m.command.Output()
+}
+
+// SetRootModule ...
+func (m *conan) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule ...
+func (m *conan) GetRootModule(path string) (*models.Module, error) {
+	return nil, nil
+}
+
+// ListUsedModules ...
+func (m *conan) ListUsedModules(path string) ([]models.Module, error) {
+	return m.ListModulesWithDeps(path)
+}
+
+// ListModulesWithDeps reads the modules and their requirements from conan.lock, without running conan
+func (m *conan) ListModulesWithDeps(path string) ([]models.Module, error) {
+	return readLockFile(path)
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// rootNodeID is the graph node of the consumer conanfile in the Conan 1 lockfiles
+const rootNodeID = "0"
+
+// LockFile is conan.lock, either the graph_lock of Conan 1 or the flat reference lists of Conan 2
+type LockFile struct {
+	Version        string    `json:"version"`
+	GraphLock      GraphLock `json:"graph_lock"`
+	Requires       []string  `json:"requires"`
+	BuildRequires  []string  `json:"build_requires"`
+	PythonRequires []string  `json:"python_requires"`
+}
+
+// GraphLock is the dependency graph locked by Conan 1, its nodes keyed by id
+type GraphLock struct {
+	Nodes map[string]LockNode `json:"nodes"`
+}
+
+// LockNode is a locked package, `ref` being empty or the reference of the consumer for the root node
+type LockNode struct {
+	Ref            string   `json:"ref"`
+	PackageID      string   `json:"package_id"`
+	Prev           string   `json:"prev"`
+	Path           string   `json:"path"`
+	Requires       []string `json:"requires"`
+	BuildRequires  []string `json:"build_requires"`
+	PythonRequires []string `json:"python_requires"`
+}
+
+// reference is a parsed `name/version[@user/channel][#recipe_revision][%timestamp]` Conan reference
+type reference struct {
+	name     string
+	version  string
+	user     string
+	channel  string
+	revision string
+}
+
+// parseReference parses a Conan reference, the user and channel being omitted or `_` when not given
+func parseReference(ref string) (reference, bool) {
+	ref = strings.TrimSpace(ref)
+	if i := strings.Index(ref, "%"); i >= 0 {
+		ref = ref[:i]
+	}
+	var parsed reference
+	if i := strings.Index(ref, "#"); i >= 0 {
+		parsed.revision = ref[i+1:]
+		ref = ref[:i]
+	}
+	if i := strings.Index(ref, "@"); i >= 0 {
+		userChannel := strings.SplitN(ref[i+1:], "/", 2)
+		parsed.user = strings.TrimPrefix(userChannel[0], "_")
+		if len(userChannel) == 2 {
+			parsed.channel = strings.TrimPrefix(userChannel[1], "_")
+		}
+		ref = ref[:i]
+	}
+	nameVersion := strings.SplitN(ref, "/", 2)
+	if len(nameVersion) != 2 || nameVersion[0] == "" || nameVersion[1] == "" {
+		return reference{}, false
+	}
+	parsed.name, parsed.version = nameVersion[0], nameVersion[1]
+	return parsed, true
+}
+
+// conanPurl builds the Conan package urls
+var conanPurl = purl.Builder{Type: "conan", Qualifiers: []string{"channel", "user"}}
+
+// purl returns the `pkg:conan/<name>@<version>` package url, qualified with the user and channel when given
+func (r reference) purl() string {
+	return conanPurl.Build("", r.name, r.version, map[string]string{"channel": r.channel, "user": r.user})
+}
+
+// readLockFile reads the root module and the locked modules of conan.lock. The Conan 1 lockfiles link the modules
+// along the requires of their graph, the requirements of the Conan 2 ones are linked to the root module
+func readLockFile(path string) ([]models.Module, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, LockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	var lock LockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+
+	root := rootModule(path, lock)
+	if len(lock.GraphLock.Nodes) > 0 {
+		return convertGraphLock(root, lock.GraphLock), nil
+	}
+	if lock.Version != "" {
+		return convertReferences(root, lock), nil
+	}
+	return nil, errUnsupportedLockFile
+}
+
+// rootModule is the consumer of the locked packages, named after its reference or else its directory
+func rootModule(path string, lock LockFile) models.Module {
+	root := models.Module{
+		Root:    true,
+		Modules: map[string]*models.Module{},
+	}
+	if ref, ok := parseReference(lock.GraphLock.Nodes[rootNodeID].Ref); ok {
+		root.Name, root.Version, root.PackageURL = ref.name, ref.version, ref.purl()
+		return root
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		root.Name = filepath.Base(abs)
+	}
+	return root
+}
+
+// convertReference builds the module of a locked package. Its checksum is the package revision when locked, the
+// binary being identified by it, or else the recipe revision. Both are MD5 hashes of the Conan manifests, they are
+// `0` when the revisions are disabled and not recorded then
+func convertReference(ref reference, packageRevision string) models.Module {
+	mod := models.Module{
+		Name:       ref.name,
+		Version:    ref.version,
+		PackageURL: ref.purl(),
+		Modules:    map[string]*models.Module{},
+	}
+	if ref.user != "" {
+		mod.Supplier = models.SupplierContact{Name: ref.user}
+	}
+	if revision := strings.SplitN(packageRevision, "#", 2)[0]; isRevisionHash(revision) {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: revision}
+	} else if isRevisionHash(ref.revision) {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: ref.revision}
+	}
+	return mod
+}
+
+// isRevisionHash tells whether a revision is an MD5 hash, i.e. 32 hexadecimal digits
+func isRevisionHash(revision string) bool {
+	if len(revision) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(revision)
+	return err == nil
+}
+
+// convertGraphLock builds the modules of the graph nodes, in node id order, and links each one to its requires,
+// build requires and python requires
+func convertGraphLock(root models.Module, graph GraphLock) []models.Module {
+	ids := make([]string, 0, len(graph.Nodes))
+	for id := range graph.Nodes {
+		if id != rootNodeID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+		if errA != nil || errB != nil {
+			return ids[i] < ids[j]
+		}
+		return a < b
+	})
+
+	modules := []models.Module{root}
+	index := map[string]int{rootNodeID: 0}
+	for _, id := range ids {
+		node := graph.Nodes[id]
+		ref, ok := parseReference(node.Ref)
+		if !ok {
+			continue
+		}
+		index[id] = len(modules)
+		modules = append(modules, convertReference(ref, node.Prev))
+	}
+
+	for id, i := range index {
+		node := graph.Nodes[id]
+		for _, requires := range [][]string{node.Requires, node.BuildRequires, node.PythonRequires} {
+			for _, dep := range requires {
+				if j, ok := index[dep]; ok && j != i {
+					modules[i].Modules[modules[j].Name] = &modules[j]
+				}
+			}
+		}
+	}
+	return modules
+}
+
+// convertReferences builds the modules of the Conan 2 reference lists, each one required by the root module since
+// the lockfile does not record the graph
+func convertReferences(root models.Module, lock LockFile) []models.Module {
+	modules := []models.Module{root}
+	seen := map[string]bool{}
+	for _, refs := range [][]string{lock.Requires, lock.BuildRequires, lock.PythonRequires} {
+		for _, item := range refs {
+			ref, ok := parseReference(item)
+			if !ok || seen[ref.name+"/"+ref.version] {
+				continue
+			}
+			seen[ref.name+"/"+ref.version] = true
+			modules = append(modules, convertReference(ref, ""))
+		}
+	}
+	for i := 1; i < len(modules); i++ {
+		modules[0].Modules[modules[i].Name] = &modules[i]
+	}
+	return modules
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package conan
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func requiredNames(module models.Module) []string {
+	names := make([]string, 0, len(module.Modules))
+	for name := range module.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestReadGraphLock(t *testing.T) {
+	modules, err := New().ListModulesWithDeps("testdata")
+	assert.NoError(t, err)
+	assert.Len(t, modules, 5)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Equal(t, "pkg:conan/app@1.0.0?channel=stable&user=acme", root.PackageURL)
+	assert.Equal(t, []string{"cmake", "fmt", "openssl"}, requiredNames(root))
+
+	openssl := modules[1]
+	assert.Equal(t, "openssl", openssl.Name)
+	assert.Equal(t, "pkg:conan/openssl@1.1.1k", openssl.PackageURL)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: "b3c9e1b4a7f6d8e2c1a0f9e8d7c6b5a4"}, openssl.CheckSum)
+	assert.Equal(t, []string{"zlib"}, requiredNames(openssl))
+	assert.Equal(t, "1.2.11", openssl.Modules["zlib"].Version)
+
+	zlib := modules[2]
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoMD5, Value: "e20364c96c45455608a72543f3a53133"}, zlib.CheckSum, "the recipe revision without a package revision")
+	assert.Empty(t, requiredNames(zlib))
+
+	fmtModule := modules[3]
+	assert.Equal(t, "pkg:conan/fmt@8.0.1", fmtModule.PackageURL)
+	assert.Equal(t, "8.0.1", fmtModule.Version)
+
+	cmake := modules[4]
+	assert.Equal(t, "pkg:conan/cmake@3.20.4", cmake.PackageURL)
+	assert.Nil(t, cmake.CheckSum, "revisions are not recorded")
+}
+
+func TestReadReferenceLock(t *testing.T) {
+	modules, err := readLockFile(filepath.Join("testdata", "v2"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 4)
+
+	assert.Equal(t, "v2", modules[0].Name)
+	assert.Equal(t, []string{"cmake", "openssl", "zlib"}, requiredNames(modules[0]))
+	assert.Equal(t, "zlib", modules[1].Name)
+	assert.Equal(t, "1.2.13", modules[1].Version)
+	assert.Equal(t, "pkg:conan/zlib@1.2.13", modules[1].PackageURL)
+	assert.Equal(t, "97d5730b529b4224045fe7090592d4c1", modules[1].CheckSum.Value)
+}
+
+func TestParseReference(t *testing.T) {
+	ref, ok := parseReference("poco/1.11.0@pocoproject/testing#9b4fc2d4a4b8b3a3f2c1d0e9f8a7b6c5%1630000000.0")
+	assert.True(t, ok)
+	assert.Equal(t, reference{name: "poco", version: "1.11.0", user: "pocoproject", channel: "testing", revision: "9b4fc2d4a4b8b3a3f2c1d0e9f8a7b6c5"}, ref)
+
+	_, ok = parseReference("conanfile.txt")
+	assert.False(t, ok)
+}
+
+func TestReadMissingLockFile(t *testing.T) {
+	_, err := readLockFile(t.TempDir())
+	assert.True(t, errors.Is(err, errFailedToReadLockFile))
+}
@@ -719,7 +719,7 @@ func extractRootLicense(path string, filename string) (string, string, string, e
 
 	files, err := ioutil.ReadDir(path)
 	if err != nil {
-		fmt.Println("error extracting licence from :" + path)
+		log.Println("error extracting licence from :" + path)
 		return "", "", "", err
 	}
 	licensePath = path
@@ -731,7 +731,7 @@ func extractRootLicense(path string, filename string) (string, string, string, e
 	}
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		fmt.Println("File reading error", err)
+		log.Println("File reading error", err)
 		return "", "", "", err
 	}
 	text = string(data)
@@ -984,7 +984,7 @@ func getGemPaths() ([]string, []string) {
 	cmd := exec.Command("gem", "env")
 	output, err := cmd.Output()
 	if err != nil {
-		fmt.Println(err)
+		log.Println(err)
 	}
 	paths := strings.Fields(string(output))
 	for i, path := range paths {
@@ -1321,7 +1321,7 @@ func gemDir() string {
 	cmd := exec.Command("gem", "environment", "gemdir")
 	output, err := cmd.Output()
 	if err != nil {
-		fmt.Println(err)
+		log.Println(err)
 	}
 	return filepath.Join(strings.Fields(string(output))[0], CACHE_DEFAULT_DIR)
 }
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	provenanceArchive    = "archive"
+	archiveEntryProperty = "archiveEntry"
+	pomPropertiesSuffix  = "/pom.properties"
+	manifestEntry        = "META-INF/MANIFEST.MF"
+)
+
+// archiveLibraryDirs are the directories of a war, ear or Spring Boot jar holding bundled libraries, the root of
+// an ear holding its modules
+var archiveLibraryDirs = map[string][]string{
+	".jar": {"BOOT-INF/lib"},
+	".war": {"WEB-INF/lib"},
+	".ear": {"", "lib"},
+}
+
+// archiveFileVersion splits a `<name>-<version>.jar` file name, the version starting with a digit
+var archiveFileVersion = regexp.MustCompile(`^(.+?)-(\d[^-]*(?:-.+)?)$`)
+
+// archiveFile is a jar, war or ear whose entries are read in place, from the file on disk or from the archive
+// bundling it, rather than loaded in memory
+type archiveFile struct {
+	name     string
+	content  io.ReaderAt
+	size     int64
+	checksum string
+}
+
+// openArchiveFile opens an archive on disk, its SHA1 checksum being streamed from the file
+func openArchiveFile(file string) (archiveFile, io.Closer, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return archiveFile{}, nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return archiveFile{}, nil, err
+	}
+	checksum, err := hashStream(io.NewSectionReader(f, 0, info.Size()))
+	if err != nil {
+		f.Close()
+		return archiveFile{}, nil, fmt.Errorf("failed to hash archive %s: %w", file, err)
+	}
+	return archiveFile{name: filepath.Base(file), content: f, size: info.Size(), checksum: checksum}, f, nil
+}
+
+// openArchiveEntry opens an archive bundled in another one. A stored entry is read in place from the bundling
+// archive, while a compressed one is inflated in memory to be read at random
+func openArchiveEntry(entry *zip.File, bundling io.ReaderAt) (archiveFile, error) {
+	name := path.Base(entry.Name)
+	if entry.Method == zip.Store {
+		if offset, err := entry.DataOffset(); err == nil {
+			section := io.NewSectionReader(bundling, offset, int64(entry.CompressedSize64))
+			checksum, err := hashStream(io.NewSectionReader(section, 0, section.Size()))
+			if err != nil {
+				return archiveFile{}, fmt.Errorf("failed to hash archive entry %s: %w", entry.Name, err)
+			}
+			return archiveFile{name: name, content: section, size: section.Size(), checksum: checksum}, nil
+		}
+	}
+
+	content, err := readZipEntry(entry)
+	if err != nil {
+		return archiveFile{}, err
+	}
+	sum := sha1.Sum(content)
+	return archiveFile{name: name, content: bytes.NewReader(content), size: int64(len(content)), checksum: hex.EncodeToString(sum[:])}, nil
+}
+
+func (a archiveFile) zipReader() (*zip.Reader, error) {
+	reader, err := zip.NewReader(a.content, a.size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive %s: %w", a.name, err)
+	}
+	return reader, nil
+}
+
+// ListArchiveModules returns the modules of a built war or ear: the archive itself followed by the libraries
+// bundled in it, as actually shipped. Coordinates are read from the pom.properties maven packs into each jar,
+// then from its manifest and lastly from its file name. Checksums are the ones of the bundled files
+func (m *javamaven) ListArchiveModules(archive string) ([]models.Module, error) {
+	file, closer, err := openArchiveFile(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	root, err := readArchiveModule(file)
+	if err != nil {
+		return nil, err
+	}
+	root.Root = true
+	root.SourceInfo = "built archive " + file.name
+
+	libraries, err := readArchiveLibraries(file, &root)
+	if err != nil {
+		return nil, err
+	}
+	return excludeIgnoredGroups(append([]models.Module{root}, libraries...), m.options), nil
+}
+
+// readArchiveLibraries lists the libraries bundled in the library directories of a war, ear or jar, descending
+// into the wars of an ear. The libraries are linked as dependencies of the archive module
+func readArchiveLibraries(file archiveFile, archive *models.Module) ([]models.Module, error) {
+	dirs, ok := archiveLibraryDirs[strings.ToLower(path.Ext(file.name))]
+	if !ok {
+		return nil, nil
+	}
+	reader, err := file.zipReader()
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []models.Module
+	for _, entry := range reader.File {
+		ext := strings.ToLower(path.Ext(entry.Name))
+		if !isArchiveLibrary(entry.Name, dirs) || (ext != ".jar" && ext != ".war") {
+			continue
+		}
+		library, err := openArchiveEntry(entry, file.content)
+		if err != nil {
+			return nil, err
+		}
+
+		mod, err := readArchiveModule(library)
+		if err != nil {
+			return nil, err
+		}
+		mod.SetProperty(provenanceProperty, provenanceArchive)
+		mod.SetProperty(archiveEntryProperty, file.name+"!/"+entry.Name)
+		mod.SourceInfo = "bundled in " + file.name + " at " + entry.Name
+
+		var nested []models.Module
+		if ext == ".war" {
+			if nested, err = readArchiveLibraries(library, &mod); err != nil {
+				return nil, err
+			}
+		}
+		archive.Modules[dependencyKey(mod)] = &mod
+		modules = append(modules, mod)
+		modules = append(modules, nested...)
+	}
+	return modules, nil
+}
+
+func isArchiveLibrary(entry string, dirs []string) bool {
+	dir := path.Dir(entry)
+	if dir == "." {
+		dir = ""
+	}
+	for _, d := range dirs {
+		if dir == d {
+			return true
+		}
+	}
+	return false
+}
+
+// readArchiveModule builds the module of a jar, war or ear from the coordinates it carries
+func readArchiveModule(archive archiveFile) (models.Module, error) {
+	reader, err := archive.zipReader()
+	if err != nil {
+		return models.Module{}, err
+	}
+
+	file := artifact{extension: strings.TrimPrefix(strings.ToLower(path.Ext(archive.name)), ".")}
+	if err := readArchiveCoordinates(reader, &file); err != nil {
+		return models.Module{}, err
+	}
+	if file.artifactID == "" || file.version == "" {
+		base := strings.TrimSuffix(archive.name, path.Ext(archive.name))
+		if match := archiveFileVersion.FindStringSubmatch(base); match != nil {
+			file.artifactID, file.version = match[1], match[2]
+		} else if file.artifactID == "" {
+			file.artifactID = base
+		}
+	}
+
+	mod := models.Module{
+		Name:    artifactModuleName(file.artifactID, ""),
+		Version: file.version,
+		Group:   file.groupID,
+		CheckSum: &models.CheckSum{
+			Algorithm: models.HashAlgoSHA1,
+			Value:     archive.checksum,
+		},
+		Modules: map[string]*models.Module{},
+	}
+	if file.extension != defaultArtifactType {
+		mod.SetProperty(typeProperty, file.extension)
+	}
+	mod.PackageURL = buildPurl(file, file.extension)
+	return mod, nil
+}
+
+// readArchiveCoordinates reads the Maven coordinates of an archive from its pom.properties, falling back to
+// the OSGi and implementation attributes of its manifest
+func readArchiveCoordinates(reader *zip.Reader, file *artifact) error {
+	var manifest *zip.File
+	for _, entry := range reader.File {
+		switch {
+		case strings.HasPrefix(entry.Name, "META-INF/maven/") && strings.HasSuffix(entry.Name, pomPropertiesSuffix):
+			content, err := readZipEntry(entry)
+			if err != nil {
+				return err
+			}
+			properties := parseJavaProperties(content)
+			if properties["artifactId"] != "" {
+				file.groupID = properties["groupId"]
+				file.artifactID = properties["artifactId"]
+				file.version = properties["version"]
+				return nil
+			}
+		case entry.Name == manifestEntry:
+			manifest = entry
+		}
+	}
+	if manifest == nil {
+		return nil
+	}
+
+	content, err := readZipEntry(manifest)
+	if err != nil {
+		return err
+	}
+	attributes := parseManifest(content)
+	file.groupID = attributes["Implementation-Vendor-Id"]
+	file.artifactID = firstNonEmpty(attributes["Bundle-SymbolicName"], attributes["Implementation-Title"])
+	file.version = firstNonEmpty(attributes["Bundle-Version"], attributes["Implementation-Version"])
+	// the symbolic name may carry directives, e.g. `org.example.core;singleton:=true`
+	file.artifactID = strings.TrimSpace(strings.SplitN(file.artifactID, ";", 2)[0])
+	return nil
+}
+
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive entry %s: %w", entry.Name, err)
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+// parseJavaProperties reads the `key=value` lines of a properties file, skipping comments
+func parseJavaProperties(content []byte) map[string]string {
+	properties := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			properties[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+		}
+	}
+	return properties
+}
+
+// parseManifest reads the main attributes of a jar manifest, joining the continuation lines starting with a space
+func parseManifest(content []byte) map[string]string {
+	attributes := map[string]string{}
+	var last string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			// the main section ends at the first blank line
+			break
+		}
+		if strings.HasPrefix(line, " ") && last != "" {
+			attributes[last] += line[1:]
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		last = strings.TrimSpace(parts[0])
+		attributes[last] = strings.TrimSpace(parts[1])
+	}
+	return attributes
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"archive/zip"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListWarModules(t *testing.T) {
+	archive := filepath.Join("testdata", "archive", "webapp-1.0.0.war")
+	modules, err := New().ListArchiveModules(archive)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 4)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "webapp", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Equal(t, "pkg:maven/com.example/webapp@1.0.0?type=war", root.PackageURL)
+	data, err := ioutil.ReadFile(archive)
+	assert.NoError(t, err)
+	sum := sha1.Sum(data)
+	assert.Equal(t, hex.EncodeToString(sum[:]), root.CheckSum.Value)
+	assert.Len(t, root.Modules, 3)
+
+	// coordinates from pom.properties
+	lang := findModule(t, modules, "commons-lang3")
+	assert.Equal(t, "org.apache.commons", lang.Group)
+	assert.Equal(t, "3.12.0", lang.Version)
+	assert.Equal(t, "pkg:maven/org.apache.commons/commons-lang3@3.12.0", lang.PackageURL)
+	assert.Equal(t, "webapp-1.0.0.war!/WEB-INF/lib/commons-lang3-3.12.0.jar", lang.GetProperty(archiveEntryProperty))
+	assert.Equal(t, provenanceArchive, lang.GetProperty(provenanceProperty))
+	assert.Len(t, lang.CheckSum.Value, 40)
+
+	// coordinates from the manifest main section
+	osgi := findModule(t, modules, "org.example.osgi.core")
+	assert.Equal(t, "org.example", osgi.Group)
+	assert.Equal(t, "2.4.1", osgi.Version)
+
+	// coordinates from the file name
+	legacy := findModule(t, modules, "legacy-util")
+	assert.Equal(t, "2.1", legacy.Version)
+	assert.Empty(t, legacy.PackageURL)
+}
+
+func TestListEarModules(t *testing.T) {
+	modules, err := New().ListArchiveModules(filepath.Join("testdata", "archive", "app-1.0.0.ear"))
+	assert.NoError(t, err)
+
+	root := modules[0]
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, "1.0.0", root.Version)
+	assert.Len(t, root.Modules, 2)
+	assert.Contains(t, root.Modules, "com.example:webapp")
+	assert.Contains(t, root.Modules, "org.slf4j:slf4j-api")
+
+	webapp := findModule(t, modules, "webapp")
+	assert.Len(t, webapp.Modules, 3)
+	assert.Contains(t, webapp.Modules, "org.apache.commons:commons-lang3")
+	assert.Len(t, modules, 6)
+}
+
+func TestListImageModules(t *testing.T) {
+	modules, err := New().ListImageModules(filepath.Join("testdata", "image"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 5)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "image", root.Name)
+	assert.Len(t, root.Modules, 2)
+
+	// a Spring Boot jar and the libraries it bundles
+	service := findModule(t, modules, "service")
+	assert.Equal(t, "com.example", service.Group)
+	assert.Equal(t, "pkg:maven/com.example/service@2.0.0", service.PackageURL)
+	assert.Equal(t, provenanceImage, service.GetProperty(provenanceProperty))
+	assert.Equal(t, "3f1c/opt/app/app.jar", service.GetProperty(imagePathProperty))
+	assert.Len(t, service.Modules, 2)
+	jackson := findModule(t, modules, "jackson-core")
+	assert.Equal(t, "pkg:maven/com.fasterxml.jackson.core/jackson-core@2.13.1", jackson.PackageURL)
+	assert.Equal(t, "app.jar!/BOOT-INF/lib/jackson-core-2.13.1.jar", jackson.GetProperty(archiveEntryProperty))
+	guava := findModule(t, modules, "guava")
+	assert.Equal(t, "31.0.1-jre", guava.Version)
+
+	// the same jar shipped in two layers is listed once, the whiteout file is skipped
+	commons := findModule(t, modules, "org.apache.commons.commons-io")
+	assert.Equal(t, "2.11.0", commons.Version)
+	assert.Equal(t, "9d4e/usr/share/java/commons-io-2.11.0.jar", commons.GetProperty(imagePathProperty))
+	assert.Len(t, commons.CheckSum.Value, 40)
+}
+
+func TestListCompressedWarModules(t *testing.T) {
+	// the stored libraries are read in place, the compressed ones are inflated: both yield the same modules
+	stored, err := zip.OpenReader(filepath.Join("testdata", "archive", "webapp-1.0.0.war"))
+	assert.NoError(t, err)
+	defer stored.Close()
+
+	archive := filepath.Join(t.TempDir(), "webapp-1.0.0.war")
+	f, err := os.Create(archive)
+	assert.NoError(t, err)
+	writer := zip.NewWriter(f)
+	for _, entry := range stored.File {
+		content, err := readZipEntry(entry)
+		assert.NoError(t, err)
+		w, err := writer.CreateHeader(&zip.FileHeader{Name: entry.Name, Method: zip.Deflate})
+		assert.NoError(t, err)
+		_, err = w.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, f.Close())
+
+	expected, err := New().ListArchiveModules(filepath.Join("testdata", "archive", "webapp-1.0.0.war"))
+	assert.NoError(t, err)
+	modules, err := New().ListArchiveModules(archive)
+	assert.NoError(t, err)
+	assert.Len(t, modules, len(expected))
+	for _, mod := range expected[1:] {
+		assert.Equal(t, mod.CheckSum.Value, findModule(t, modules, mod.Name).CheckSum.Value, mod.Name)
+		assert.Equal(t, mod.PackageURL, findModule(t, modules, mod.Name).PackageURL, mod.Name)
+	}
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	defaultArtifactType = "jar"
+	defaultScope        = "compile"
+	providedScope       = "provided"
+	testJarType         = "test-jar"
+	testsClassifier     = "tests"
+	pluginArtifactType  = "maven-plugin"
+	classifierProperty  = "classifier"
+	typeProperty        = "type"
+	coordinateProperty  = "mavenCoordinate"
+)
+
+// artifactTypeExtensions maps the dependency types and packagings whose file extension differs from the type itself
+var artifactTypeExtensions = map[string]string{
+	testJarType:        "jar",
+	pluginArtifactType: "jar",
+	"bundle":           "jar",
+	"ejb":              "jar",
+	"ejb-client":       "jar",
+	"java-source":      "jar",
+	"javadoc":          "jar",
+}
+
+// artifactTypePurposes classifies the artifact types that are not libraries
+var artifactTypePurposes = map[string]string{
+	pluginArtifactType: models.PurposeOther,
+}
+
+// artifact identifies a file of the Maven repository
+type artifact struct {
+	groupID    string
+	artifactID string
+	version    string
+	classifier string
+	extension  string
+}
+
+// newArtifact resolves the artifact file of a dependency from its type and classifier.
+// A test-jar without an explicit classifier is the `tests` classified jar of its coordinates.
+func newArtifact(dep gopom.Dependency, version string, options Options) artifact {
+	artifactType := strings.TrimSpace(dep.Type)
+	if artifactType == "" {
+		artifactType = defaultArtifactType
+	}
+	classifier := strings.TrimSpace(dep.Classifier)
+	if artifactType == testJarType && classifier == "" {
+		classifier = testsClassifier
+	}
+
+	return artifact{
+		groupID:    strings.TrimSpace(dep.GroupID),
+		artifactID: strings.TrimSpace(dep.ArtifactID),
+		version:    version,
+		classifier: classifier,
+		extension:  options.artifactExtension(artifactType),
+	}
+}
+
+// fileName returns the repository file name, `<artifactId>-<version>[-<classifier>].<extension>`
+func (a artifact) fileName() string {
+	name := a.artifactID + "-" + a.version
+	if a.classifier != "" {
+		name += "-" + a.classifier
+	}
+	return name + "." + a.extension
+}
+
+// layout returns the path segments of the artifact file in a repository using the standard Maven layout,
+// `<group>/<path>/<artifactId>/<version>/<file name>`
+func (a artifact) layout() []string {
+	return append(strings.Split(a.groupID, "."), a.artifactID, a.version, a.fileName())
+}
+
+// localPath returns the artifact location in a local repository
+func (a artifact) localPath(repository string) string {
+	return filepath.Join(append([]string{repository}, a.layout()...)...)
+}
+
+// remoteURL returns the artifact location in a remote repository, e.g. MavenCentralUrl
+func (a artifact) remoteURL(repository string) string {
+	return strings.TrimSuffix(repository, "/") + "/" + strings.Join(a.layout(), "/")
+}
+
+// artifactModuleName is the module name of an artifact, classified artifacts being distinct from the main one
+func artifactModuleName(artifactID, classifier string) string {
+	name := strings.Replace(strings.TrimSpace(artifactID), " ", "-", -1)
+	if classifier != "" {
+		name += "-" + classifier
+	}
+	return name
+}
+
+// defaultLocalRepository returns the default local repository, `~/.m2/repository`
+func defaultLocalRepository() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// pluginDependency describes a build plugin as a dependency on its maven-plugin artifact
+func pluginDependency(plugin gopom.Plugin) gopom.Dependency {
+	return gopom.Dependency{
+		GroupID:    plugin.GroupID,
+		ArtifactID: plugin.ArtifactID,
+		Version:    plugin.Version,
+		Type:       pluginArtifactType,
+	}
+}
+
+// parseDependencyListEntry parses a `group:artifact:type[:classifier]:version[:scope]` entry of mvn dependency:list.
+// The columns vary across Maven versions and options: the scope may be missing and columns may follow it, e.g. the
+// artifact file with -DoutputAbsoluteArtifactFilename. The scope is told by its value, the version preceding it and
+// a classifier preceding the version
+func parseDependencyListEntry(entry string) (gopom.Dependency, bool) {
+	fields := strings.Split(strings.TrimSpace(entry), ":")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	if len(fields) < 4 {
+		return gopom.Dependency{}, false
+	}
+
+	dep := gopom.Dependency{GroupID: fields[0], ArtifactID: fields[1], Type: fields[2]}
+	scope := -1
+	for i := 4; i < len(fields) && i <= 5; i++ {
+		if _, ok := mavenScopes[fields[i]]; ok {
+			scope = i
+			break
+		}
+	}
+	switch {
+	case scope >= 0:
+		dep.Scope = fields[scope]
+		dep.Version = fields[scope-1]
+		if scope == 5 {
+			dep.Classifier = fields[3]
+		}
+	case len(fields) == 4:
+		dep.Version = fields[3]
+	default:
+		dep.Classifier, dep.Version = fields[3], fields[4]
+	}
+	return dep, true
+}
+
+// declaredCoordinate is the `group:artifact:type[:classifier]:version` coordinate of a dependency as declared, its
+// artifactId, type and classifier being left as written
+func declaredCoordinate(dep gopom.Dependency, groupID, version string) string {
+	artifactType := dep.Type
+	if strings.TrimSpace(artifactType) == "" {
+		artifactType = defaultArtifactType
+	}
+	fields := []string{groupID, dep.ArtifactID, artifactType}
+	if strings.TrimSpace(dep.Classifier) != "" {
+		fields = append(fields, dep.Classifier)
+	}
+	return strings.Join(append(fields, version), ":")
+}
+
+// recordCoordinate records, when configured, the coordinate a module was resolved from
+func recordCoordinate(mod *models.Module, coordinate string, options Options) {
+	if options.RecordCoordinates {
+		mod.SetProperty(coordinateProperty, coordinate)
+	}
+}
+
+// dependencyModuleName is the module name a dependency is registered under
+func dependencyModuleName(dep gopom.Dependency) string {
+	return artifactModuleName(dep.ArtifactID, newArtifact(dep, dep.Version, Options{}).classifier)
+}
+
+// projectArtifact is the artifact built by the project, as given by its packaging
+func projectArtifact(project gopom.Project, version string, options Options) artifact {
+	dep := gopom.Dependency{
+		GroupID:    resolveCoordinate(project, project.GroupID),
+		ArtifactID: projectArtifactID(project),
+		Type:       strings.TrimSpace(project.Packaging),
+	}
+	if strings.TrimSpace(project.GroupID) == "" {
+		dep.GroupID = resolveCoordinate(project, project.Parent.GroupID)
+	}
+	return newArtifact(dep, version, options)
+}
+
+// parentArtifact is the pom artifact of the parent of a project, if it has one
+func parentArtifact(project gopom.Project) (artifact, bool) {
+	parent := project.Parent
+	if strings.TrimSpace(parent.ArtifactID) == "" {
+		return artifact{}, false
+	}
+	return artifact{
+		groupID:    strings.TrimSpace(parent.GroupID),
+		artifactID: strings.TrimSpace(parent.ArtifactID),
+		version:    strings.TrimSpace(resolveProperties(parent.Version, project)),
+		extension:  pomArtifactType,
+	}, true
+}
+
+// projectArtifactID returns the artifactId of the project, the one of its parent when the pom omits its own
+func projectArtifactID(project gopom.Project) string {
+	if artifactID := strings.TrimSpace(project.ArtifactID); artifactID != "" {
+		return artifactID
+	}
+	return strings.TrimSpace(project.Parent.ArtifactID)
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestArtifactFileName(t *testing.T) {
+	assert.Equal(t, "core-1.0.0.jar", newArtifact(gopom.Dependency{ArtifactID: "core"}, "1.0.0", Options{}).fileName())
+	assert.Equal(t, "core-1.0.0-tests.jar", newArtifact(gopom.Dependency{ArtifactID: "core", Type: testJarType}, "1.0.0", Options{}).fileName())
+	assert.Equal(t, "core-1.0.0-linux-x86_64.jar", newArtifact(gopom.Dependency{ArtifactID: "core", Classifier: "linux-x86_64"}, "1.0.0", Options{}).fileName())
+}
+
+func TestDownloadLocationOfClassifiedWar(t *testing.T) {
+	dep := gopom.Dependency{GroupID: "com.example.web", ArtifactID: "storefront", Version: "2.4.1", Type: "war", Classifier: "jakarta"}
+	mod := createModule(dep, gopom.Project{}, provenanceDependencies, Options{LocalRepository: t.TempDir()})
+	assert.Equal(t, "https://repo.maven.apache.org/maven2/com/example/web/storefront/2.4.1/storefront-2.4.1-jakarta.war", mod.PackageDownloadLocation)
+
+	file := newArtifact(dep, dep.Version, Options{})
+	assert.Equal(t, filepath.Join("repository", "com", "example", "web", "storefront", "2.4.1", "storefront-2.4.1-jakarta.war"), file.localPath("repository"))
+	assert.Equal(t, "https://repo.example.com/releases/com/example/web/storefront/2.4.1/storefront-2.4.1-jakarta.war", file.remoteURL("https://repo.example.com/releases"))
+}
+
+func TestParseDependencyListEntry(t *testing.T) {
+	dep, ok := parseDependencyListEntry("   com.example:core:test-jar:tests:1.0.0:test")
+	assert.True(t, ok)
+	assert.Equal(t, "core-tests", dependencyModuleName(dep))
+	assert.Equal(t, "1.0.0", dep.Version)
+
+	dep, ok = parseDependencyListEntry("   com.example:core:jar:1.0.0:compile")
+	assert.True(t, ok)
+	assert.Equal(t, "core", dependencyModuleName(dep))
+	assert.Equal(t, "compile", dep.Scope)
+
+	_, ok = parseDependencyListEntry("The following files have been resolved:")
+	assert.False(t, ok)
+}
+
+func TestParseDependencyListEntryColumns(t *testing.T) {
+	for entry, expected := range map[string]gopom.Dependency{
+		"org.slf4j:slf4j-api:jar:1.7.30": {
+			GroupID: "org.slf4j", ArtifactID: "slf4j-api", Type: "jar", Version: "1.7.30",
+		},
+		"org.slf4j:slf4j-api:jar:1.7.30:runtime": {
+			GroupID: "org.slf4j", ArtifactID: "slf4j-api", Type: "jar", Version: "1.7.30", Scope: "runtime",
+		},
+		"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.65.Final": {
+			GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Type: "jar", Classifier: "linux-x86_64", Version: "4.1.65.Final",
+		},
+		"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.65.Final:test": {
+			GroupID: "io.netty", ArtifactID: "netty-transport-native-epoll", Type: "jar", Classifier: "linux-x86_64", Version: "4.1.65.Final", Scope: "test",
+		},
+		// the artifact file of -DoutputAbsoluteArtifactFilename follows the scope, with the colon of its drive
+		`org.slf4j:slf4j-api:jar:1.7.30:compile:C:\Users\ci\.m2\repository\slf4j-api-1.7.30.jar`: {
+			GroupID: "org.slf4j", ArtifactID: "slf4j-api", Type: "jar", Version: "1.7.30", Scope: "compile",
+		},
+	} {
+		dep, ok := parseDependencyListEntry(entry)
+		assert.True(t, ok, entry)
+		assert.Equal(t, expected, dep, entry)
+	}
+
+	_, ok := parseDependencyListEntry("org.slf4j:slf4j-api:jar")
+	assert.False(t, ok)
+}
+
+func TestTestJarNextToMainJar(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/testjar")
+	assert.NoError(t, err)
+
+	repository := filepath.Join("testdata", "repository")
+	modules := convertDeclaredModules(project, Options{LocalRepository: repository})
+	assert.Len(t, modules, 3)
+
+	main := findModule(t, modules, "core")
+	tests := findModule(t, modules, "core-tests")
+	mainSum, err := readFileCheckSum(filepath.Join(repository, "com", "example", "core", "1.0.0", "core-1.0.0.jar"))
+	assert.NoError(t, err)
+	testsSum, err := readFileCheckSum(filepath.Join(repository, "com", "example", "core", "1.0.0", "core-1.0.0-tests.jar"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, mainSum, main.CheckSum.Value)
+	assert.Equal(t, testsSum, tests.CheckSum.Value)
+	assert.NotEqual(t, main.CheckSum.Value, tests.CheckSum.Value)
+	assert.Equal(t, testsClassifier, tests.GetProperty(classifierProperty))
+	assert.Equal(t, testJarType, tests.GetProperty(typeProperty))
+
+	listed := mergeDependencyList(project, []string{
+		"   com.example:core:jar:1.0.0:compile",
+		"   com.example:core:test-jar:tests:1.0.0:test",
+		"",
+		"BUILD SUCCESS",
+	}, &models.Module{Modules: map[string]*models.Module{}}, Options{})
+	assert.Empty(t, listed)
+}
+
+func TestMavenPluginPackaging(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/mavenplugin")
+	assert.NoError(t, err)
+
+	repository := filepath.Join("testdata", "repository")
+	modules := convertDeclaredModules(project, Options{LocalRepository: repository})
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	checksum, err := readFileCheckSum(filepath.Join(repository, "com", "example", "example-maven-plugin", "1.0.0", "example-maven-plugin-1.0.0.jar"))
+	assert.NoError(t, err)
+	assert.Equal(t, checksum, root.CheckSum.Value)
+	assert.Equal(t, pluginArtifactType, root.GetProperty(typeProperty))
+	assert.Equal(t, models.PurposeOther, root.Purpose)
+
+	api := findModule(t, modules, "maven-plugin-api")
+	assert.Empty(t, api.Purpose)
+}
+
+func TestPackagingExtensions(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/packaging")
+	assert.NoError(t, err)
+
+	repository := filepath.Join("testdata", "repository")
+	options := Options{
+		LocalRepository:    repository,
+		ArtifactExtensions: map[string]string{"nar": ".nar.zip"},
+	}
+	modules := convertDeclaredModules(project, options)
+
+	bundleSum, err := readFileCheckSum(filepath.Join(repository, "com", "example", "osgi-bundle", "1.0.0", "osgi-bundle-1.0.0.jar"))
+	assert.NoError(t, err)
+	assert.Equal(t, bundleSum, findModule(t, modules, "osgi-bundle").CheckSum.Value)
+
+	narSum, err := readFileCheckSum(filepath.Join(repository, "com", "example", "native-lib", "1.0.0", "native-lib-1.0.0.nar.zip"))
+	assert.NoError(t, err)
+	assert.Equal(t, narSum, findModule(t, modules, "native-lib").CheckSum.Value)
+
+	assert.Equal(t, "nar", Options{}.artifactExtension("nar"))
+	assert.Equal(t, "jar", Options{}.artifactExtension("bundle"))
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	assemblyPluginArtifactID = "maven-assembly-plugin"
+	jarWithDependencies      = "jar-with-dependencies"
+	defaultBuildDirectory    = "target"
+	diagnosticFatJar         = "fat-jar"
+)
+
+// assemblyPom holds the descriptors the maven-assembly-plugin is configured with, globally or per execution,
+// whose configuration gopom leaves out
+type assemblyPom struct {
+	Plugins []struct {
+		ArtifactID     string   `xml:"artifactId"`
+		DescriptorRefs []string `xml:"configuration>descriptorRefs>descriptorRef"`
+		Executions     []struct {
+			DescriptorRefs []string `xml:"configuration>descriptorRefs>descriptorRef"`
+		} `xml:"executions>execution"`
+	} `xml:"build>plugins>plugin"`
+}
+
+// assemblesFatJar tells whether the maven-assembly-plugin of the project pom file builds a jar-with-dependencies.
+// Nothing is detected when the pom cannot be read
+func assemblesFatJar(pomPath string) bool {
+	data, err := ioutil.ReadFile(pomPath)
+	if err != nil {
+		return false
+	}
+
+	var pom assemblyPom
+	if err := unmarshalPom(data, &pom); err != nil {
+		return false
+	}
+	for _, plugin := range pom.Plugins {
+		if strings.TrimSpace(plugin.ArtifactID) != assemblyPluginArtifactID {
+			continue
+		}
+		descriptors := plugin.DescriptorRefs
+		for _, execution := range plugin.Executions {
+			descriptors = append(descriptors, execution.DescriptorRefs...)
+		}
+		for _, descriptor := range descriptors {
+			if strings.TrimSpace(descriptor) == jarWithDependencies {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reportFatJar warns that the artifact built from the project embeds its dependencies when the
+// maven-assembly-plugin assembles a jar-with-dependencies. With Options.ListFatJarContents, the coordinates packed
+// in the assembled jar, when it is built already, are listed in the diagnostic
+func reportFatJar(dir string, project gopom.Project, module string, options Options) {
+	if !assemblesFatJar(filepath.Join(dir, options.pomFile())) {
+		return
+	}
+
+	jar := fatJarPath(dir, project)
+	message := fmt.Sprintf("the %s builds %s, which embeds the dependencies of the project", assemblyPluginArtifactID, filepath.Base(jar))
+	if options.ListFatJarContents {
+		if embedded, err := readEmbeddedCoordinates(jar); err != nil {
+			message += fmt.Sprintf(", its contents are not listed: %v", err)
+		} else if len(embedded) > 0 {
+			message += ": " + strings.Join(embedded, ", ")
+		}
+	}
+	options.report(models.DiagnosticWarning, diagnosticFatJar, module, message)
+}
+
+// fatJarPath returns the jar-with-dependencies the assembly plugin builds in the build directory, named after the
+// final name of the project with the descriptor as suffix
+func fatJarPath(dir string, project gopom.Project) string {
+	buildDir := resolveProperty(project, strings.TrimSpace(project.Build.Directory))
+	if buildDir == "" || strings.Contains(buildDir, "${") {
+		buildDir = defaultBuildDirectory
+	}
+	if !filepath.IsAbs(buildDir) {
+		buildDir = filepath.Join(dir, buildDir)
+	}
+
+	finalName := resolveProperty(project, strings.TrimSpace(project.Build.FinalName))
+	if finalName == "" || strings.Contains(finalName, "${") {
+		file := projectArtifact(project, projectVersion(project), Options{})
+		finalName = file.artifactID + "-" + file.version
+	}
+	return filepath.Join(buildDir, finalName+"-"+jarWithDependencies+".jar")
+}
+
+// readEmbeddedCoordinates returns the sorted `groupId:artifactId:version` of the pom.properties packed in a jar, the
+// project its own included
+func readEmbeddedCoordinates(jar string) ([]string, error) {
+	reader, err := zip.OpenReader(jar)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var coordinates []string
+	for _, entry := range reader.File {
+		if !strings.HasPrefix(entry.Name, "META-INF/maven/") || !strings.HasSuffix(entry.Name, pomPropertiesSuffix) {
+			continue
+		}
+		content, err := readZipEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		properties := parseJavaProperties(content)
+		if properties["artifactId"] != "" {
+			coordinates = append(coordinates, properties["groupId"]+":"+properties["artifactId"]+":"+properties["version"])
+		}
+	}
+	sort.Strings(coordinates)
+	return coordinates, nil
+}
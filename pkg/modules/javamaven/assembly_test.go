@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestReportFatJarAssembly(t *testing.T) {
+	dir := filepath.Join("testdata", "assembly")
+	project, err := readAndLoadPomFile(dir)
+	assert.NoError(t, err)
+	assert.True(t, assemblesFatJar(filepath.Join(dir, "pom.xml")))
+	assert.False(t, assemblesFatJar(filepath.Join("testdata", "provenance", "pom.xml")))
+	assert.Equal(t, filepath.Join(dir, "target", "app-1.0.0-jar-with-dependencies.jar"), fatJarPath(dir, project))
+
+	options := Options{diagnostics: &models.Diagnostics{}}
+	reportFatJar(dir, project, "app", options)
+	diagnostics := options.diagnostics.List()
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, models.DiagnosticWarning, diagnostics[0].Severity)
+	assert.Equal(t, diagnosticFatJar, diagnostics[0].Code)
+	assert.Equal(t, "app", diagnostics[0].Module)
+	assert.Equal(t, "the maven-assembly-plugin builds app-1.0.0-jar-with-dependencies.jar, which embeds the dependencies of the project", diagnostics[0].Message)
+
+	// the coordinates packed in the assembled jar are listed on demand
+	options = Options{ListFatJarContents: true, diagnostics: &models.Diagnostics{}}
+	reportFatJar(dir, project, "app", options)
+	assert.Contains(t, options.diagnostics.List()[0].Message, ": com.example:app:1.0.0, commons-codec:commons-codec:1.15, org.slf4j:slf4j-api:1.7.36")
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"archive/zip"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
+)
+
+const (
+	bundleLicenseHeader   = "Bundle-License"
+	bundleLicenseExternal = "<<EXTERNAL>>"
+)
+
+// readBundleLicense reads the OSGi Bundle-License manifest header of a jar as an SPDX expression. The header lists
+// licenses as `name;link=url;description=text`, the name or else the link being normalized to an SPDX identifier.
+// Several licenses are all applicable. No license is returned unless every listed one is recognized
+func readBundleLicense(jarPath string) (string, bool) {
+	archive, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return "", false
+	}
+	defer archive.Close()
+
+	for _, entry := range archive.File {
+		if entry.Name != manifestEntry {
+			continue
+		}
+		content, err := readZipEntry(entry)
+		if err != nil {
+			return "", false
+		}
+		return parseBundleLicense(parseManifest(content)[bundleLicenseHeader])
+	}
+	return "", false
+}
+
+func parseBundleLicense(header string) (string, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == bundleLicenseExternal {
+		return "", false
+	}
+	// license names may hold commas, the header is first read as a single license
+	if license, ok := licenses.Normalize(header); ok {
+		return license, true
+	}
+
+	var ids []string
+	for _, entry := range splitUnquoted(header, ',') {
+		license, ok := normalizeBundleLicense(entry)
+		if !ok {
+			return "", false
+		}
+		ids = append(ids, license)
+	}
+	return strings.Join(ids, " AND "), true
+}
+
+// normalizeBundleLicense returns the SPDX identifier of a `name;attribute=value` license entry
+func normalizeBundleLicense(entry string) (string, bool) {
+	parts := splitUnquoted(entry, ';')
+	if id, ok := licenses.Normalize(strings.Trim(strings.TrimSpace(parts[0]), "\"")); ok {
+		return id, true
+	}
+	for _, attribute := range parts[1:] {
+		kv := strings.SplitN(attribute, "=", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == "link" {
+			return licenses.Normalize(strings.Trim(strings.TrimSpace(kv[1]), "\""))
+		}
+	}
+	return "", false
+}
+
+// splitUnquoted splits a manifest header on a separator found outside of double quotes
+func splitUnquoted(value string, separator rune) []string {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == separator && !quoted:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	return append(parts, current.String())
+}
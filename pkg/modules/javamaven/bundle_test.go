@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+)
+
+// writeBundleJar writes a jar holding only a manifest at its maven layout path under repository
+func writeBundleJar(t *testing.T, repository, artifactID, manifest string) {
+	writeJar(t, repository, artifactID, map[string][]byte{manifestEntry: []byte("Manifest-Version: 1.0\r\n" + manifest + "\r\n")})
+}
+
+func TestCreateModuleReadsBundleLicense(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	// the jars are written at test time, license detection of the package directory would find them otherwise
+	repository := t.TempDir()
+	writeBundleJar(t, repository, "mpl-bundle", `Bundle-License: "Mozilla Public License";link="https://www.mozilla.org/MPL/2.0/"`)
+	writeBundleJar(t, repository, "dual-bundle", "Bundle-License: \"Eclipse Public License 2.0\",https://opensource.o\r\n rg/licenses/MIT")
+
+	options := Options{
+		LocalRepository: repository,
+		LicenseProvider: fakeLicenseProvider{"org.example:mpl-bundle:1.0.0": "MIT"},
+	}
+	mod := createModule(gopom.Dependency{GroupID: "org.example", ArtifactID: "mpl-bundle", Version: "1.0.0"}, project, provenanceDependencies, options)
+	assert.Equal(t, "MPL-2.0", mod.LicenseConcluded)
+	assert.Equal(t, "MPL-2.0", mod.LicenseDeclared)
+
+	mod = createModule(gopom.Dependency{GroupID: "org.example", ArtifactID: "dual-bundle", Version: "1.0.0"}, project, provenanceDependencies, options)
+	assert.Equal(t, "EPL-2.0 AND MIT", mod.LicenseConcluded)
+}
+
+func TestParseBundleLicense(t *testing.T) {
+	tests := map[string]string{
+		"Eclipse Public License - v 1.0":            "EPL-1.0",
+		"http://www.gnu.org/licenses/lgpl-3.0.html": "LGPL-3.0-only",
+		"mit": "MIT",
+		`"Custom";link="https://spdx.org/licenses/BSD-2-Clause.html"`: "BSD-2-Clause",
+		`EPL-2.0;description="Eclipse Public License",MIT`:            "EPL-2.0 AND MIT",
+	}
+	for header, expected := range tests {
+		license, ok := parseBundleLicense(header)
+		assert.True(t, ok, header)
+		assert.Equal(t, expected, license, header)
+	}
+
+	for _, header := range []string{"", bundleLicenseExternal, "Proprietary", "Apache-2.0,Proprietary"} {
+		_, ok := parseBundleLicense(header)
+		assert.False(t, ok, header)
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"bufio"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// ChecksumProvider supplies artifact checksums from an external source, such as an enterprise
+// artifact database, before the plugin falls back to hashing locally.
+// The artifact file is given by its coordinates, the classifier being empty for the main artifact and the extension
+// being the one of its type, e.g. jar for a test-jar. It returns the known checksums keyed by algorithm, or false when
+// the artifact is unknown.
+type ChecksumProvider interface {
+	GetChecksums(groupID, artifactID, version, classifier, extension string) (map[models.HashAlgorithm]string, bool)
+}
+
+// providerAlgorithmPreference is the order in which provider checksums are picked
+var providerAlgorithmPreference = []models.HashAlgorithm{
+	models.HashAlgoSHA1,
+	models.HashAlgoSHA256,
+	models.HashAlgoSHA512,
+	models.HashAlgoMD5,
+}
+
+// checksumBufferSize bounds the memory used to hash a file, read through a buffer of this size whatever its size
+const checksumBufferSize = 64 * 1024
+
+// defaultChecksumAlgorithms are the checksums computed unless Options.ChecksumAlgorithms selects others
+var defaultChecksumAlgorithms = []models.HashAlgorithm{
+	models.HashAlgoSHA1,
+	models.HashAlgoSHA256,
+	models.HashAlgoSHA512,
+}
+
+// checksumHashes are the algorithms the artifact files can be hashed with locally, the other ones being known
+// from the ChecksumProvider only
+var checksumHashes = map[models.HashAlgorithm]func() hash.Hash{
+	models.HashAlgoSHA1:   sha1.New,
+	models.HashAlgoSHA224: sha256.New224,
+	models.HashAlgoSHA256: sha256.New,
+	models.HashAlgoSHA384: sha512.New384,
+	models.HashAlgoSHA512: sha512.New,
+	models.HashAlgoMD5:    md5.New,
+}
+
+// buildCheckSums returns the checksums of the artifact, in the order of the selected algorithms. They are asked to
+// the configured ChecksumProvider, its preferred checksum being kept when it knows none of the selected algorithms,
+// before hashing the artifact file found in the local repositories, or the offline mirror. An artifact file found
+// nowhere has no checksum
+func buildCheckSums(file artifact, options Options) []models.CheckSum {
+	algorithms := options.checksumAlgorithms()
+	if options.ChecksumProvider != nil {
+		if known, ok := options.ChecksumProvider.GetChecksums(file.groupID, file.artifactID, file.version, file.classifier, file.extension); ok {
+			if checksums := pickCheckSums(known, algorithms); len(checksums) > 0 {
+				return checksums
+			}
+			if checksums := pickCheckSums(known, providerAlgorithmPreference); len(checksums) > 0 {
+				return checksums[:1]
+			}
+		}
+	}
+
+	path, err := options.artifactPath(file)
+	if err != nil {
+		options.reportMissingFromMirror(file, err)
+	} else if checksums, err := readFileCheckSums(path, algorithms); err == nil {
+		return checksums
+	}
+	return nil
+}
+
+// pickCheckSums returns the known checksums of the algorithms, in their order
+func pickCheckSums(known map[models.HashAlgorithm]string, algorithms []models.HashAlgorithm) []models.CheckSum {
+	var checksums []models.CheckSum
+	for _, algorithm := range algorithms {
+		if value := known[algorithm]; value != "" {
+			checksums = append(checksums, models.CheckSum{Algorithm: algorithm, Value: value})
+		}
+	}
+	return checksums
+}
+
+// setCheckSums sets the first checksum as the module checksum and the other ones as its additional checksums
+func setCheckSums(mod *models.Module, checksums []models.CheckSum) {
+	if len(checksums) == 0 {
+		return
+	}
+	mod.CheckSum = &checksums[0]
+	mod.AdditionalCheckSums = checksums[1:]
+}
+
+// hashStream computes the SHA1 checksum of a stream, e.g. an archive entry, read through a bounded buffer
+func hashStream(r io.Reader) (string, error) {
+	h := sha1.New()
+	if _, err := io.Copy(h, bufio.NewReaderSize(r, checksumBufferSize)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readFileCheckSums computes the checksums of a file, streaming it once whatever the number of algorithms
+func readFileCheckSums(filePath string, algorithms []models.HashAlgorithm) ([]models.CheckSum, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var selected []models.HashAlgorithm
+	var hashes []hash.Hash
+	var writers []io.Writer
+	for _, algorithm := range algorithms {
+		if newHash, ok := checksumHashes[algorithm]; ok {
+			h := newHash()
+			selected = append(selected, algorithm)
+			hashes = append(hashes, h)
+			writers = append(writers, h)
+		}
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), bufio.NewReaderSize(f, checksumBufferSize)); err != nil {
+		return nil, err
+	}
+
+	checksums := make([]models.CheckSum, len(hashes))
+	for i, h := range hashes {
+		checksums[i] = models.CheckSum{Algorithm: selected[i], Value: hex.EncodeToString(h.Sum(nil))}
+	}
+	return checksums, nil
+}
@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type fakeChecksumProvider map[string]map[models.HashAlgorithm]string
+
+// GetChecksums looks the checksums up by `<groupId>:<artifactId>[:<extension>[:<classifier>]]:<version>`, the
+// extension of a jar being left out unless classified
+func (f fakeChecksumProvider) GetChecksums(groupID, artifactID, version, classifier, extension string) (map[models.HashAlgorithm]string, bool) {
+	coordinate := groupID + ":" + artifactID
+	if classifier != "" {
+		coordinate += ":" + extension + ":" + classifier
+	} else if extension != defaultArtifactType {
+		coordinate += ":" + extension
+	}
+	checksums, ok := f[coordinate+":"+version]
+	return checksums, ok
+}
+
+func TestBuildCheckSumFromProvider(t *testing.T) {
+	options := Options{
+		ChecksumProvider: fakeChecksumProvider{
+			"junit:junit:4.13.2": {
+				models.HashAlgoSHA256: "8e495b634469d64fb8acfa3495a065cbacc8a0fff55ce1e31007be4c16dc57d3",
+			},
+		},
+	}
+
+	checksums := buildCheckSums(artifact{groupID: "junit", artifactID: "junit", version: "4.13.2", extension: defaultArtifactType}, options)
+	assert.Equal(t, []models.CheckSum{{
+		Algorithm: models.HashAlgoSHA256,
+		Value:     "8e495b634469d64fb8acfa3495a065cbacc8a0fff55ce1e31007be4c16dc57d3",
+	}}, checksums)
+
+	checksums = buildCheckSums(artifact{groupID: "org.hamcrest", artifactID: "hamcrest-core", version: "1.3", extension: defaultArtifactType}, options)
+	assert.Empty(t, checksums, "an artifact file found nowhere has no checksum")
+
+	// the provider checksum is kept when it knows none of the selected algorithms
+	options.ChecksumAlgorithms = []models.HashAlgorithm{models.HashAlgoSHA512}
+	checksums = buildCheckSums(artifact{groupID: "junit", artifactID: "junit", version: "4.13.2", extension: defaultArtifactType}, options)
+	assert.Len(t, checksums, 1)
+	assert.Equal(t, models.HashAlgoSHA256, checksums[0].Algorithm)
+}
+
+func TestCreateModuleUsesChecksumProvider(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	options := Options{
+		ChecksumProvider: fakeChecksumProvider{
+			"junit:junit:4.13.2": {models.HashAlgoSHA1: "2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57"},
+		},
+	}
+	mod := createModule(gopom.Dependency{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2"}, project, provenanceDependencies, options)
+	assert.Equal(t, "2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57", mod.CheckSum.Value)
+}
+
+func TestCreateModuleAsksChecksumOfArtifactFile(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	options := Options{
+		ChecksumProvider: fakeChecksumProvider{
+			"com.example:core:1.0.0":             {models.HashAlgoSHA1: "8f3e6e1a3dbd1f6c2b4b1f7d4f0f8e7a5f4b3c2d"},
+			"com.example:core:jar:tests:1.0.0":   {models.HashAlgoSHA1: "1b6a1c5e0f2d3c4b5a69788796a5b4c3d2e1f0a9"},
+			"com.example:core:pom:1.0.0":         {models.HashAlgoSHA1: "0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d"},
+			"com.example:native:jar:linux:1.0.0": {models.HashAlgoSHA1: "5e4d3c2b1a09f8e7d6c5b4a39281706f5e4d3c2b"},
+		},
+	}
+	core := func(dep gopom.Dependency) string {
+		dep.GroupID, dep.Version = "com.example", "1.0.0"
+		return createModule(dep, project, provenanceDependencies, options).CheckSum.Value
+	}
+	assert.Equal(t, "8f3e6e1a3dbd1f6c2b4b1f7d4f0f8e7a5f4b3c2d", core(gopom.Dependency{ArtifactID: "core"}))
+	assert.Equal(t, "1b6a1c5e0f2d3c4b5a69788796a5b4c3d2e1f0a9", core(gopom.Dependency{ArtifactID: "core", Type: testJarType}))
+	assert.Equal(t, "0c9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d", core(gopom.Dependency{ArtifactID: "core", Type: "pom"}))
+	assert.Equal(t, "5e4d3c2b1a09f8e7d6c5b4a39281706f5e4d3c2b", core(gopom.Dependency{ArtifactID: "native", Classifier: "linux"}))
+}
+
+func TestCreateModuleSkipsChecksumScopes(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	asked := map[string]bool{}
+	options := Options{
+		ChecksumProvider: checksumProviderFunc(func(groupID, artifactID, version, classifier, extension string) (map[models.HashAlgorithm]string, bool) {
+			asked[artifactID] = true
+			return map[models.HashAlgorithm]string{models.HashAlgoSHA1: "2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57"}, true
+		}),
+		SkipChecksumScopes: []string{"test", "Provided"},
+	}
+	junit := createModule(gopom.Dependency{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2", Scope: "test"}, project, provenanceDependencies, options)
+	assert.Nil(t, junit.CheckSum)
+	servlet := createModule(gopom.Dependency{GroupID: "javax.servlet", ArtifactID: "javax.servlet-api", Version: "4.0.1", Scope: "provided"}, project, provenanceDependencies, options)
+	assert.Nil(t, servlet.CheckSum)
+	assert.Empty(t, asked)
+
+	guava := createModule(gopom.Dependency{GroupID: "com.google.guava", ArtifactID: "guava", Version: "30.1-jre"}, project, provenanceDependencies, options)
+	assert.Equal(t, "2b8f6fa8b1398e7b73a2b4346ffb33a2ee6d7d57", guava.CheckSum.Value)
+	postgresql := createModule(gopom.Dependency{GroupID: "org.postgresql", ArtifactID: "postgresql", Version: "42.2.20", Scope: "runtime"}, project, provenanceDependencies, options)
+	assert.NotNil(t, postgresql.CheckSum)
+	assert.Equal(t, map[string]bool{"guava": true, "postgresql": true}, asked)
+}
+
+type checksumProviderFunc func(groupID, artifactID, version, classifier, extension string) (map[models.HashAlgorithm]string, bool)
+
+func (f checksumProviderFunc) GetChecksums(groupID, artifactID, version, classifier, extension string) (map[models.HashAlgorithm]string, bool) {
+	return f(groupID, artifactID, version, classifier, extension)
+}
+
+func TestBuildCheckSumFromOfflineMirror(t *testing.T) {
+	options := Options{OfflineMirror: "testdata/mirror", LocalRepository: t.TempDir(), diagnostics: &models.Diagnostics{}}
+
+	checksums := buildCheckSums(artifact{groupID: "org.slf4j", artifactID: "slf4j-api", version: "1.7.36", extension: defaultArtifactType}, options)
+	assert.Equal(t, "5ad6419d5c80605a42fd63387a42202036e37896", checksums[0].Value)
+	assert.Empty(t, options.diagnostics.List())
+
+	buildCheckSums(artifact{groupID: "junit", artifactID: "junit", version: "4.13.2", extension: defaultArtifactType}, options)
+	diagnostics := options.diagnostics.List()
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, models.DiagnosticError, diagnostics[0].Severity)
+	assert.Equal(t, diagnosticMissingFromMirror, diagnostics[0].Code)
+	assert.Equal(t, "junit", diagnostics[0].Module)
+	assert.Contains(t, diagnostics[0].Message, "junit:junit:4.13.2 jar is not at testdata/mirror/junit/junit/4.13.2/junit-4.13.2.jar")
+}
+
+func TestBuildCheckSumsOfSelectedAlgorithms(t *testing.T) {
+	slf4j := artifact{groupID: "org.slf4j", artifactID: "slf4j-api", version: "1.7.36", extension: defaultArtifactType}
+	sha1 := models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "5ad6419d5c80605a42fd63387a42202036e37896"}
+	sha256 := models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "0d80367a1768ceaf1220f7caabafef1670a393cdb2efc165d02a8c9cdec4726c"}
+	sha512 := models.CheckSum{
+		Algorithm: models.HashAlgoSHA512,
+		Value:     "6b8ee65b2dd9fd851bee908a2b8d37739cf672737bfa1dce38d1a8e558acc26c4aa09557da78fbc3a6d786459f49bec67a149417ba3a8efc97ed9a52aae851a6",
+	}
+
+	assert.Equal(t, []models.CheckSum{sha1, sha256, sha512}, buildCheckSums(slf4j, Options{LocalRepository: "testdata/mirror"}))
+	assert.Equal(t, []models.CheckSum{sha1}, buildCheckSums(slf4j, Options{
+		LocalRepository:    "testdata/mirror",
+		ChecksumAlgorithms: []models.HashAlgorithm{models.HashAlgoSHA1},
+	}))
+
+	var mod models.Module
+	setCheckSums(&mod, buildCheckSums(slf4j, Options{
+		LocalRepository:    "testdata/mirror",
+		ChecksumAlgorithms: []models.HashAlgorithm{models.HashAlgoSHA256, models.HashAlgoSHA1},
+	}))
+	assert.Equal(t, sha256, *mod.CheckSum)
+	assert.Equal(t, []models.CheckSum{sha1}, mod.AdditionalCheckSums)
+}
+
+// readFileCheckSum computes the SHA1 checksum of a file
+func readFileCheckSum(filePath string) (string, error) {
+	checksums, err := readFileCheckSums(filePath, []models.HashAlgorithm{models.HashAlgoSHA1})
+	if err != nil {
+		return "", err
+	}
+	return checksums[0].Value, nil
+}
+
+// largeFile creates a sparse file of the size, a large artifact without the disk usage
+func largeFile(dir string, size int64) (string, error) {
+	file := filepath.Join(dir, "large.jar")
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return file, f.Truncate(size)
+}
+
+func TestReadFileCheckSumsBoundedMemory(t *testing.T) {
+	const size = 32 << 20
+	file, err := largeFile(t.TempDir(), size)
+	assert.NoError(t, err)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	checksums, err := readFileCheckSums(file, defaultChecksumAlgorithms)
+	runtime.ReadMemStats(&after)
+	assert.NoError(t, err)
+	assert.Len(t, checksums, 3)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(size/32))
+}
+
+// BenchmarkReadFileCheckSums hashes a large file with the default algorithms. The memory allocated per operation is
+// bounded by the read buffer, it does not grow with the size of the file
+func BenchmarkReadFileCheckSums(b *testing.B) {
+	const size = 64 << 20
+	file, err := largeFile(b.TempDir(), size)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := readFileCheckSums(file, defaultChecksumAlgorithms); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
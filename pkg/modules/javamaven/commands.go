@@ -3,11 +3,21 @@
 package javamaven
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
 	"strings"
+	"time"
 )
 
 type command string
 
+// DefaultMavenTimeout is the time a mvn invocation may run unless Options.MavenTimeout is set
+const DefaultMavenTimeout = 5 * time.Minute
+
 var (
 	VersionCmd command = "mvn -v"
 )
@@ -17,3 +27,117 @@ func (c command) Parse() []string {
 	cmd := strings.TrimSpace(string(c))
 	return strings.Fields(cmd)
 }
+
+// mavenValueFlags are the mvn flags whose value may be given as the next argument
+var mavenValueFlags = map[string]bool{
+	"-P": true, "--activate-profiles": true,
+	"-s": true, "--settings": true,
+	"-gs": true, "--global-settings": true,
+	"-t": true, "--toolchains": true,
+	"-T": true, "--threads": true,
+	"-pl": true, "--projects": true,
+	"-D": true, "--define": true,
+}
+
+// conflictingMavenArgs are the extra arguments changing the project or the output the plugin reads. The `-D`
+// ones also match the property assignments, e.g. `-DoutputFile=deps.txt`
+var conflictingMavenArgs = map[string]string{
+	"-f":             "selects another pom.xml",
+	"--file":         "selects another pom.xml",
+	"-h":             "replaces the goals",
+	"--help":         "replaces the goals",
+	"-v":             "replaces the goals",
+	"--version":      "replaces the goals",
+	"-q":             "hides the dependency:list output",
+	"--quiet":        "hides the dependency:list output",
+	"-l":             "redirects the dependency:list output",
+	"--log-file":     "redirects the dependency:list output",
+	"-DoutputFile":   "redirects the dependency:tree output",
+	"-DoutputType":   "changes the dependency:tree format",
+	"-DappendOutput": "changes the dependency:tree output",
+}
+
+// offlineMissingArtifact matches the artifact Maven reports as missing from the local repository in offline mode
+var offlineMissingArtifact = regexp.MustCompile(`the artifact (\S+) has not been downloaded from it before`)
+
+// mavenArgs returns the arguments of a mvn invocation, offline when configured, followed by the configured extra
+// arguments, failing when an extra argument is a goal or conflicts with the arguments the plugin relies on
+func (o Options) mavenArgs(args ...string) ([]string, error) {
+	for i, arg := range o.MavenArgs {
+		if !strings.HasPrefix(arg, "-") {
+			if i > 0 && mavenValueFlags[o.MavenArgs[i-1]] {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %s is not a flag", errConflictingMavenArg, arg)
+		}
+		name := strings.SplitN(arg, "=", 2)[0]
+		if reason, ok := conflictingMavenArgs[name]; ok {
+			return nil, fmt.Errorf("%w: %s %s", errConflictingMavenArg, arg, reason)
+		}
+	}
+	if o.Offline {
+		args = append([]string{"-o"}, args...)
+	}
+	return append(args, o.MavenArgs...), nil
+}
+
+// runMaven runs mvn in a directory with the given arguments, killing it once the context of the plugin is done or
+// the timeout elapses. The output is collected by run, e.g. (*exec.Cmd).Output, and a killed invocation fails with
+// an error naming the command and how long it ran
+func (o Options) runMaven(workingDir string, args []string, run func(*exec.Cmd) ([]byte, error)) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(o.context(), o.mavenTimeout())
+	defer cancel()
+
+	command := exec.CommandContext(ctx, "mvn", args...)
+	command.Dir = workingDir
+	started := time.Now()
+	output, err := run(command)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		invocation := strings.Join(append([]string{"mvn"}, args...), " ")
+		elapsed := time.Since(started).Round(time.Millisecond)
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			return output, fmt.Errorf("%w: %s was killed after running for %s", errMavenTimeout, invocation, elapsed)
+		}
+		return output, fmt.Errorf("%s was killed after running for %s: %w", invocation, elapsed, ctxErr)
+	}
+	return output, err
+}
+
+// offlineResolutionError returns the error of an offline mvn run that failed because the local repository misses
+// artifacts of the project, naming the first one, and nil for the other failures
+func (o Options) offlineResolutionError(output []byte) error {
+	if !o.Offline || !bytes.Contains(output, []byte("offline mode")) {
+		return nil
+	}
+	missing := "artifacts of the project"
+	if match := offlineMissingArtifact.FindSubmatch(output); match != nil {
+		missing = string(match[1])
+	}
+	return fmt.Errorf("%w: %s is not in the local repository, run a build of the project online first, e.g. mvn dependency:go-offline",
+		errIncompleteLocalRepository, missing)
+}
+
+// dependencyListArgs returns the arguments of mvn dependency:list, along with the scopes Maven selects, failing when
+// they are not dependency scopes
+func (o Options) dependencyListArgs() ([]string, error) {
+	args := append([]string{"dependency:list"}, o.pomArgs()...)
+	for _, filter := range []struct{ property, scope string }{
+		{"includeScope", o.IncludeScope},
+		{"excludeScope", o.ExcludeScope},
+	} {
+		scope := strings.TrimSpace(filter.scope)
+		if scope == "" {
+			continue
+		}
+		if _, ok := mavenScopes[scope]; !ok {
+			return nil, fmt.Errorf("%w: %s=%s", errUnsupportedScope, filter.property, scope)
+		}
+		args = append(args, "-D"+filter.property+"="+scope)
+	}
+	for _, scope := range o.ExcludeScopes {
+		if _, ok := mavenScopes[strings.ToLower(strings.TrimSpace(scope))]; !ok {
+			return nil, fmt.Errorf("%w: excluded scope %s", errUnsupportedScope, scope)
+		}
+	}
+	return o.mavenArgs(args...)
+}
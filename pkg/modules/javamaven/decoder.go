@@ -4,92 +4,124 @@ package javamaven
 
 import (
 	"bufio"
-	"encoding/xml"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
-	"log"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/vifraa/gopom"
 
-	"github.com/spdx/spdx-sbom-generator/pkg/helper"
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
 )
 
 // RepositoryUrl is the repository url
 var RepositoryUrl string = "https://mvnrepository.com/artifact/"
 
-// captures os.Stdout data and writes buffers
-func stdOutCapture() func() (string, error) {
-	readFromPipe, writeToPipe, err := os.Pipe()
-	if err != nil {
-		panic(err)
-	}
+// MavenCentralUrl is the repository the dependencies are downloaded from
+var MavenCentralUrl = "https://repo.maven.apache.org/maven2/"
 
-	done := make(chan error, 1)
+// defaultPomFile is the pom file mvn reads in the project directory unless given another one with `-f`
+const defaultPomFile = "pom.xml"
 
-	save := os.Stdout
-	os.Stdout = writeToPipe
+const (
+	diagnosticPartialTree       = "partial-dependency-tree"
+	diagnosticPartialList       = "partial-dependency-list"
+	diagnosticUnreadableModules = "unreadable-modules"
+	requestedVersionsProperty   = "requestedVersions"
+)
 
-	var buffer strings.Builder
+// provenance records where in the build a module was discovered
+const (
+	provenanceProperty             = "provenance"
+	provenanceDependencies         = "dependencies"
+	provenanceDependencyManagement = "dependencyManagement"
+	provenancePlugins              = "plugins"
+	provenancePluginManagement     = "pluginManagement"
+	provenanceExtensions           = "extensions"
+	provenanceDependencyList       = "dependency:list"
+	provenanceDependencyTree       = "dependency:tree"
+)
 
-	go func() {
-		_, err := io.Copy(&buffer, readFromPipe)
-		readFromPipe.Close()
-		done <- err
-	}()
+// provenanceSourceInfo describes to SBOM readers how the modules of each provenance were discovered
+var provenanceSourceInfo = map[string]string{
+	provenanceDependencies:         "declared in pom.xml dependencies",
+	provenanceDependencyManagement: "declared in pom.xml dependencyManagement",
+	provenancePlugins:              "declared in pom.xml build plugins",
+	provenancePluginManagement:     "declared in pom.xml build pluginManagement",
+	provenanceExtensions:           "declared in .mvn/extensions.xml",
+	provenanceDependencyList:       "resolved via mvn dependency:list",
+	provenanceDependencyTree:       "resolved via mvn dependency:tree",
+}
+
+const projectSourceInfo = "project described by pom.xml"
 
-	return func() (string, error) {
-		os.Stdout = save
-		writeToPipe.Close()
-		err := <-done
-		return buffer.String(), err
-	}
-}
-
-func getDependencyList() ([]string, error) {
-	done := stdOutCapture()
-	var err error
-
-	cmd1 := exec.Command("mvn", "-o", "dependency:list")
-	cmd2 := exec.Command("grep", ":.*:.*:.*")
-	cmd3 := exec.Command("cut", "-d]", "-f2-")
-	cmd4 := exec.Command("sort", "-u")
-	cmd2.Stdin, err = cmd1.StdoutPipe()
-	cmd3.Stdin, err = cmd2.StdoutPipe()
-	cmd4.Stdin, err = cmd3.StdoutPipe()
-	cmd4.Stdout = os.Stdout
-	err = cmd4.Start()
-	err = cmd3.Start()
-	err = cmd2.Start()
-	err = cmd1.Run()
-	err = cmd2.Wait()
-	err = cmd3.Wait()
-	err = cmd4.Wait()
-
-	capturedOutput, err := done()
+// getDependencyList runs `mvn dependency:list` in the project directory and returns the unique dependency lines.
+// The output is collected from the command pipes, the process stdout is left untouched. The listing is best
+// effort, a failing mvn run yields no additional dependencies
+func getDependencyList(workingDir string, options Options) ([]string, error) {
+	args, err := options.withMavenConfig(workingDir).dependencyListArgs()
 	if err != nil {
-		fmt.Println(err)
 		return nil, err
 	}
 
-	s := strings.Split(capturedOutput, "\n")
-	return s, err
+	output, err := options.runMaven(workingDir, args, (*exec.Cmd).Output)
+	if err != nil {
+		// a failing build still lists the dependencies it resolved, the others are resolved from pom.xml
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, err
+		}
+		if err := options.offlineResolutionError(output); err != nil {
+			return nil, err
+		}
+		options.report(models.DiagnosticWarning, diagnosticPartialList, "",
+			fmt.Sprintf("mvn dependency:list failed (%v), the dependencies it did not list are resolved from pom.xml", err))
+	}
+
+	return parseDependencyListOutput(output), nil
 }
 
-func updateLicenseInformationToModule(mod *models.Module) {
-	licensePkg, err := helper.GetLicenses(".")
-	if err == nil {
-		mod.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
-		mod.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
-		mod.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
-		mod.CommentsLicense = licensePkg.Comments
+// parseDependencyListOutput extracts the `group:artifact:type[:classifier]:version[:scope]` entries from the
+// output of mvn dependency:list, sorted and without duplicates: the lines whose first token, once stripped of their
+// `[INFO]` log level, has at least three colons. The annotations recent plugins append to the entry, e.g.
+// ` -- module org.slf4j [auto]` or ` (optional)`, are left out. Log messages such as
+// `Finished at: 2021-06-10T10:00:00` are not
+func parseDependencyListOutput(output []byte) []string {
+	seen := map[string]struct{}{}
+	var entries []string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			if i := strings.Index(line, "]"); i >= 0 {
+				line = line[i+1:]
+			}
+		}
+		tokens := strings.Fields(line)
+		if len(tokens) == 0 || strings.Count(tokens[0], ":") < 3 {
+			continue
+		}
+		if len(tokens) > 1 && tokens[1] != "--" && !strings.HasPrefix(tokens[1], "(") {
+			continue
+		}
+		entry := tokens[0]
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		seen[entry] = struct{}{}
+		entries = append(entries, entry)
 	}
+	sort.Strings(entries)
+	return entries
 }
 
 // Update package supplier information
@@ -97,7 +129,7 @@ func updatePackageSuppier(project gopom.Project, mod *models.Module, developers
 	// By Default set name as project name
 	if mod.Root {
 		if len(project.Name) > 0 {
-			mod.Supplier.Name = project.Name
+			mod.Supplier.Name = resolveProperties(project.Name, project)
 		} else if len(project.GroupID) > 0 {
 			mod.Supplier.Name = project.GroupID
 		} else if len(project.ArtifactID) > 0 {
@@ -107,11 +139,11 @@ func updatePackageSuppier(project gopom.Project, mod *models.Module, developers
 		for _, developer := range developers {
 			if len(developer.Name) > 0 && len(developer.Email) > 0 {
 				mod.Supplier.Type = models.Person
-				mod.Supplier.Name = developer.Name
-				mod.Supplier.Email = developer.Email
+				mod.Supplier.Name = resolveProperties(developer.Name, project)
+				mod.Supplier.Email = resolveProperties(developer.Email, project)
 			} else if len(developer.Email) == 0 && len(developer.Name) > 0 {
 				mod.Supplier.Type = models.Person
-				mod.Supplier.Name = developer.Name
+				mod.Supplier.Name = resolveProperties(developer.Name, project)
 			}
 		}
 	} else {
@@ -120,74 +152,78 @@ func updatePackageSuppier(project gopom.Project, mod *models.Module, developers
 }
 
 // Update package download location
-func updatePackageDownloadLocation(groupID string, project gopom.Project, mod *models.Module, distManagement gopom.DistributionManagement) {
-	if len(distManagement.DownloadURL) > 0 && (strings.HasPrefix(distManagement.DownloadURL, "http") ||
-		strings.HasPrefix(distManagement.DownloadURL, "https")) {
-		mod.PackageDownloadLocation = distManagement.DownloadURL
+// updatePackageDownloadLocation sets where the module is downloaded from, the artifact file in Maven Central for the
+// dependencies, with the extension of their type and their classifier
+func updatePackageDownloadLocation(file artifact, project gopom.Project, mod *models.Module, distManagement gopom.DistributionManagement) {
+	downloadURL := resolveProperties(distManagement.DownloadURL, project)
+	if len(downloadURL) > 0 && (strings.HasPrefix(downloadURL, "http") ||
+		strings.HasPrefix(downloadURL, "https")) {
+		mod.PackageDownloadLocation = downloadURL
 	} else {
 		if mod.Root {
 			if len(project.URL) > 0 {
-				mod.PackageDownloadLocation = project.URL
+				mod.PackageDownloadLocation = resolveProperties(project.URL, project)
 			} else if len(project.GroupID) > 0 {
 				mod.PackageDownloadLocation = RepositoryUrl + project.GroupID
 			} else {
-				mod.PackageDownloadLocation = RepositoryUrl + project.ArtifactID
+				mod.PackageDownloadLocation = RepositoryUrl + projectArtifactID(project)
 			}
 		} else {
-			mod.PackageDownloadLocation = RepositoryUrl + groupID + "/" + mod.Name + "/" + mod.Version
+			mod.PackageDownloadLocation = file.remoteURL(MavenCentralUrl)
 		}
 	}
 }
 
-func convertProjectLevelPackageToModule(project gopom.Project) models.Module {
+func convertProjectLevelPackageToModule(project gopom.Project, options Options) models.Module {
 	// package to module
 	var modName string
 	if len(project.Name) == 0 {
-		modName = strings.Replace(strings.TrimSpace(project.ArtifactID), " ", "-", -1)
+		modName = strings.Replace(projectArtifactID(project), " ", "-", -1)
 	} else {
-		modName = strings.TrimSpace(project.Name)
-		if strings.HasPrefix(modName, "$") {
-			name := strings.TrimLeft(strings.TrimRight(modName, "}"), "${")
-			if strings.HasPrefix(name, "project.artifactId") {
-				modName = project.ArtifactID
-			}
-		}
+		modName = strings.TrimSpace(resolveProperty(project, strings.TrimSpace(project.Name)))
 		modName = strings.Replace(modName, " ", "-", -1)
 	}
 
-	var modVersion string
-	if len(project.Version) > 0 {
-		modVersion = project.Version
-	} else if len(project.Parent.Version) > 0 {
-		modVersion = project.Parent.Version
-	}
-	if strings.HasPrefix(modVersion, "$") {
-		version := strings.TrimLeft(strings.TrimRight(modVersion, "}"), "${")
-		modVersion = project.Properties.Entries[version]
-	}
+	modVersion := projectVersion(project)
 
 	var mod models.Module
 	mod.Name = modName
 	mod.Version = modVersion
 	mod.Modules = map[string]*models.Module{}
-	mod.CheckSum = &models.CheckSum{
-		Algorithm: models.HashAlgoSHA1,
-		Value:     readCheckSum(modName),
-	}
+	file := projectArtifact(project, modVersion, options)
+	setCheckSums(&mod, buildCheckSums(file, options))
 	mod.Root = true
+	mod.Group = file.groupID
+	mod.SourceInfo = projectSourceInfo
+	if project.Packaging == pluginArtifactType {
+		mod.SetProperty(typeProperty, pluginArtifactType)
+	}
+	mod.Purpose = artifactTypePurposes[project.Packaging]
+	mod.PackageURL = buildPurl(file, project.Packaging)
 	updatePackageSuppier(project, &mod, project.Developers)
-	updatePackageDownloadLocation(project.GroupID, project, &mod, project.DistributionManagement)
-	updateLicenseInformationToModule(&mod)
+	updatePackageDownloadLocation(file, project, &mod, project.DistributionManagement)
+	updateLicenseInformationToModule(&mod, file, func() []gopom.License { return project.Licenses }, options)
 	if len(project.URL) > 0 {
-		mod.PackageURL = project.URL
+		mod.PackageHomePage = resolveProperties(project.URL, project)
 	}
 
 	return mod
 }
 
+// projectVersion returns the version of the project, inherited from its parent when not given
+func projectVersion(project gopom.Project) string {
+	var modVersion string
+	if len(project.Version) > 0 {
+		modVersion = project.Version
+	} else if len(project.Parent.Version) > 0 {
+		modVersion = project.Parent.Version
+	}
+	return resolvePropertyVersion(modVersion, project)
+}
+
 func findInDependency(slice []gopom.Dependency, val string) bool {
 	for _, item := range slice {
-		if item.ArtifactID == val {
+		if dependencyModuleName(item) == val {
 			return true
 		}
 	}
@@ -203,37 +239,81 @@ func findInPlugins(slice []gopom.Plugin, val string) bool {
 	return false
 }
 
-func createModule(groupID string, name string, version string, project gopom.Project) models.Module {
+func createModule(dep gopom.Dependency, project gopom.Project, provenance string, options Options) models.Module {
 	var mod models.Module
-	modVersion := version
-	if strings.HasPrefix(version, "$") {
-		version1 := strings.TrimLeft(strings.TrimRight(version, "}"), "${")
-		modVersion = project.Properties.Entries[version1]
+	declared := dep
+	dep.GroupID = resolveCoordinate(project, dep.GroupID)
+	modVersion := resolvePropertyVersion(dep.Version, project)
+	if modVersion == "" {
+		modVersion = resolveManagedVersion(dep.GroupID, dep.ArtifactID, project, options)
 	}
+	recordCoordinate(&mod, declaredCoordinate(declared, dep.GroupID, modVersion), options)
 
-	name = path.Base(name)
+	groupID := dep.GroupID
+	name := path.Base(dep.ArtifactID)
 	name = strings.TrimSpace(name)
-	mod.Name = strings.Replace(name, " ", "-", -1)
-	mod.Version = modVersion
+	mod.Version = normalizeVersion(modVersion)
+	if mod.Version != modVersion {
+		mod.SetProperty(originalVersionProperty, modVersion)
+	}
+	// the repository lays the artifact out at its declared version, the normalized one identifies the package
+	file := newArtifact(dep, strings.TrimSpace(modVersion), options)
+	file.artifactID = name
+	mod.Name = artifactModuleName(name, file.classifier)
+	mod.Group = groupID
+	mod.Scope = strings.TrimSpace(dep.Scope)
 	mod.Modules = map[string]*models.Module{}
-	mod.CheckSum = &models.CheckSum{
-		Algorithm: models.HashAlgoSHA1,
-		Value:     readCheckSum(name),
+	if !options.skipsChecksum(dep.Scope) {
+		setCheckSums(&mod, buildCheckSums(file, options))
+	}
+	mod.SetProperty(provenanceProperty, provenance)
+	mod.SourceInfo = provenanceSourceInfo[provenance]
+	if file.classifier != "" {
+		mod.SetProperty(classifierProperty, file.classifier)
+		if platform, ok := platformClassifier(file.classifier); ok {
+			mod.SetProperty(platformProperty, platform)
+		}
+	}
+	if dep.Type != "" && dep.Type != defaultArtifactType {
+		mod.SetProperty(typeProperty, dep.Type)
 	}
+	mod.Purpose = artifactTypePurposes[dep.Type]
+	identified := file
+	identified.version = mod.Version
+	mod.PackageURL = buildPurl(identified, dep.Type)
 	updatePackageSuppier(project, &mod, project.Developers)
-	updatePackageDownloadLocation(groupID, project, &mod, project.DistributionManagement)
-	updateLicenseInformationToModule(&mod)
+	updatePackageDownloadLocation(file, project, &mod, project.DistributionManagement)
+	updateLicenseInformationToModule(&mod, file, func() []gopom.License { return readArtifactLicenses(file, options) }, options)
+	jarPath, _ := options.artifactPath(file)
+	recordModuleSystemNames(&mod, jarPath)
 	return mod
 }
 
+// readAndLoadPomFile loads the pom.xml of a project directory, see readProjectPom
 func readAndLoadPomFile(fpath string) (gopom.Project, error) {
+	return readProjectPom(fpath, Options{})
+}
+
+// readProjectPom loads the configured pom file of a project directory, its properties overridden by
+// .mvn/maven.config
+func readProjectPom(dir string, options Options) (gopom.Project, error) {
+	project, err := readPomFile(filepath.Join(dir, options.pomFile()))
+	if err == nil {
+		readMavenConfig(dir).apply(&project)
+	}
+	return project, err
+}
+
+// readPomFile loads the project described by a pom file
+func readPomFile(filePath string) (gopom.Project, error) {
 	var project gopom.Project
 
-	filePath := fpath + "/pom.xml"
 	pomFile, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return project, fmt.Errorf("%w: %s", ErrPomNotFound, filePath)
+	}
 	if err != nil {
-		fmt.Println(err)
-		return project, err
+		return project, fmt.Errorf("failed to open %s: %w", filePath, err)
 	}
 
 	defer func() {
@@ -245,21 +325,21 @@ func readAndLoadPomFile(fpath string) (gopom.Project, error) {
 	// read our opened xmlFile as a byte array.
 	pomData, err := ioutil.ReadAll(pomFile)
 	if err != nil {
-		return project, err
+		return project, fmt.Errorf("failed to read %s: %w", filePath, err)
 	}
 
 	// Load project from string
-	if err := xml.Unmarshal(pomData, &project); err != nil {
-		fmt.Printf("unable to unmarshal pom file. Reason: %v", err)
-		return project, err
+	if err := unmarshalPom(pomData, &project); err != nil {
+		return project, fmt.Errorf("failed to parse %s: %w", filePath, err)
 	}
 
 	return project, nil
 }
 
-func getModule(modules []models.Module, name string) (models.Module, error) {
+// getModule finds a module by name, of the given group unless either groupId is unknown
+func getModule(modules []models.Module, groupID, name string) (models.Module, error) {
 	for _, module := range modules {
-		if module.Name == name {
+		if module.Name == name && (groupID == "" || module.Group == "" || module.Group == groupID) {
 			return module, nil
 		}
 	}
@@ -267,36 +347,40 @@ func getModule(modules []models.Module, name string) (models.Module, error) {
 }
 
 // If parent pom.xml has modules information in it, go to individual modules pom.xml
-func convertPkgModulesToModule(existingModules []models.Module, fpath string, moduleName string, parentPom gopom.Project) ([]models.Module, error) {
+func convertPkgModulesToModule(existingModules []models.Module, fpath string, moduleName string, parentPom gopom.Project, options Options) ([]models.Module, error) {
 	var modules []models.Module
-	filePath := fpath + "/" + moduleName
-	project, err := readAndLoadPomFile(filePath)
+	filePath, err := options.projectPath(fpath + "/" + moduleName)
 	if err != nil {
-		return []models.Module{}, err
+		return nil, err
+	}
+	project, err := readProjectPom(filePath, options)
+	if err != nil {
+		return nil, err
 	}
 
-	parentMod := convertProjectLevelPackageToModule(project)
+	parentMod := convertProjectLevelPackageToModule(project, options)
 	parentMod.Root = false
+	reportFatJar(filePath, project, parentMod.Name, options)
 	modules = append(modules, parentMod)
 
 	// Include dependecy from module pom.xml if it is not existing in ParentPom
 	for _, element := range project.Dependencies {
-		name := strings.Replace(strings.TrimSpace(element.ArtifactID), " ", "-", -1)
+		name := dependencyModuleName(element)
 		found1 := false
 		found := findInDependency(parentPom.Dependencies, name)
 		if !found {
 			found1 = findInDependency(parentPom.DependencyManagement.Dependencies, name)
 			if !found1 {
-				mod := createModule(element.GroupID, name, element.Version, project)
+				mod := createModule(element, project, provenanceDependencies, options)
 				modules = append(modules, mod)
-				parentMod.Modules[mod.Name] = &mod
+				parentMod.Modules[dependencyKey(mod)] = &mod
 			}
 		}
 
 		if found || found1 {
-			module, err := getModule(existingModules, name)
+			module, err := getModule(existingModules, resolveCoordinate(project, element.GroupID), name)
 			if err == nil {
-				parentMod.Modules[name] = &module
+				parentMod.Modules[dependencyKey(module)] = &module
 			}
 		}
 	}
@@ -309,147 +393,482 @@ func convertPkgModulesToModule(existingModules []models.Module, fpath string, mo
 		if !found {
 			found1 = findInPlugins(parentPom.Build.PluginManagement.Plugins, name)
 			if !found1 {
-				mod := createModule(element.GroupID, name, element.Version, project)
+				mod := createModule(pluginDependency(element), project, provenancePlugins, options)
 				modules = append(modules, mod)
-				parentMod.Modules[mod.Name] = &mod
+				parentMod.Modules[dependencyKey(mod)] = &mod
 			}
 		}
 
 		if found || found1 {
-			module, err := getModule(existingModules, name)
+			module, err := getModule(existingModules, resolveCoordinate(project, element.GroupID), name)
 			if err == nil {
-				parentMod.Modules[name] = &module
+				parentMod.Modules[dependencyKey(module)] = &module
 			}
 		}
 	}
 	return modules, nil
 }
 
-func convertPOMReaderToModules(fpath string, lookForDepenent bool) ([]models.Module, error) {
-	modules := make([]models.Module, 0)
-	project, err := readAndLoadPomFile(fpath)
+func convertPOMReaderToModules(fpath string, lookForDepenent bool, options Options) ([]models.Module, error) {
+	fpath, err := options.projectPath(fpath)
+	if err != nil {
+		return nil, err
+	}
+	project, err := readProjectPom(fpath, options)
 	if err != nil {
-		return []models.Module{}, err
+		return nil, err
+	}
+	if lookForDepenent {
+		options = options.withReactor(fpath, project)
+	}
+	modules := appendExtensionModules(convertDeclaredModules(project, options), fpath, project, options)
+	parentMod := modules[0]
+	reportFatJar(fpath, project, parentMod.Name, options)
+	scopes := map[string]string{}
+	declaredScopes(scopes, project)
+
+	dependencyList, err := getDependencyList(fpath, options)
+	if err != nil {
+		return modules, fmt.Errorf("failed to list the dependencies of %s: %w", fpath, err)
+	}
+
+	// Add additional dependency from mvn dependency list to pom.xml dependency list
+	modules = deduplicateModules(append(modules, mergeDependencyList(project, dependencyList, &parentMod, options)...))
+
+	if lookForDepenent {
+		// iterate over Modules
+		var unreadable []string
+		for _, module := range project.Modules {
+			additionalModules, err := convertPkgModulesToModule(modules, fpath, module, project, options)
+			if errors.Is(err, errPathOutsideRoot) {
+				return nil, err
+			}
+			if err != nil {
+				// continue reading the other modules, the unreadable ones are reported at the end
+				log.Debugf("skipping module %s: %v", module, err)
+				unreadable = append(unreadable, strings.TrimSpace(module))
+				continue
+			}
+			modules = append(modules, additionalModules...)
+			if _, submodule, err := readModulePom(fpath, module, options); err == nil {
+				declaredScopes(scopes, submodule)
+			}
+		}
+		modules = deduplicateModules(modules)
+		qualifySharedSubmoduleNames(modules)
+		reportUnreadableModules(unreadable, parentMod.Name, options)
+	}
+
+	if options.filtersScopes() {
+		modules = excludeUnselectedScopes(modules, scopes, dependencyList, options)
+	}
+	modules = separateProvidedModules(modules, scopes, dependencyList, options)
+
+	if !options.IncludeManagedOnly {
+		modules = excludeManagedOnlyModules(modules, project, dependencyList)
+	}
+	resolveVersionsFromDependencyList(modules, dependencyList, project, options)
+	modules, err = handleUnresolvedVersions(modules, options)
+	if err != nil {
+		return nil, err
+	}
+	if lookForDepenent {
+		modules = selectSubject(modules, fpath, project, options)
+	}
+	return modules, nil
+}
+
+// reportUnreadableModules warns about the modules of the aggregator whose pom could not be read, their modules and
+// dependencies being missing from the SBOM
+func reportUnreadableModules(unreadable []string, aggregator string, options Options) {
+	if len(unreadable) == 0 {
+		return
+	}
+	options.report(models.DiagnosticWarning, diagnosticUnreadableModules, aggregator,
+		fmt.Sprintf("%d of the modules could not be read and are missing from the SBOM: %s", len(unreadable), strings.Join(unreadable, ", ")))
+}
+
+// deduplicateModules keeps a single module per group:artifact:version, the same coordinate being declared e.g. in
+// dependencyManagement and in dependencies, used by several submodules or listed by mvn. The most complete record
+// wins, see moduleCompleteness, the first one on a tie, and takes the dependencies of the records it replaces. The
+// Modules maps are pointed at the winning record, so that every module requires the same one
+func deduplicateModules(modules []models.Module) []models.Module {
+	index := map[string]int{}
+	deduplicated := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		key := moduleCoordinateKey(module)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(deduplicated)
+			deduplicated = append(deduplicated, module)
+			continue
+		}
+		kept := &deduplicated[i]
+		if moduleCompleteness(module) > moduleCompleteness(*kept) {
+			*kept, module = module, *kept
+		}
+		if kept.Modules == nil {
+			kept.Modules = map[string]*models.Module{}
+		}
+		for name, dependency := range module.Modules {
+			if _, ok := kept.Modules[name]; !ok {
+				kept.Modules[name] = dependency
+			}
+		}
+	}
+
+	canonical := make(map[string]*models.Module, len(deduplicated))
+	for i := range deduplicated {
+		module := deduplicated[i]
+		canonical[moduleCoordinateKey(module)] = &module
+	}
+	for i := range deduplicated {
+		for name, dependency := range deduplicated[i].Modules {
+			if dependency == nil {
+				continue
+			}
+			if module, ok := canonical[moduleCoordinateKey(*dependency)]; ok {
+				deduplicated[i].Modules[name] = module
+			}
+		}
+	}
+	return deduplicated
+}
+
+// moduleCoordinateKey returns the group:artifact:version of a module, its name carrying the classifier
+func moduleCoordinateKey(module models.Module) string {
+	return module.Group + ":" + module.Name + ":" + module.Version
+}
+
+// moduleCompleteness ranks the records of a module: the root and the reactor modules are kept over a dependency on
+// them, then a record with a checksum or a license wins over one without
+func moduleCompleteness(module models.Module) int {
+	completeness := 0
+	if module.Root {
+		completeness += 8
+	}
+	if module.SourceInfo == projectSourceInfo {
+		completeness += 4
+	}
+	if module.CheckSum != nil {
+		completeness += 2
+	}
+	if module.LicenseDeclared != "" || module.LicenseConcluded != "" {
+		completeness++
+	}
+	return completeness
+}
+
+// qualifySharedSubmoduleNames prefixes with their groupId the names of the submodules sharing their name with a
+// module of another group, e.g. `com.example.api.core` and `com.example.impl.core`, so distinct submodules of a
+// reactor are not conflated
+func qualifySharedSubmoduleNames(modules []models.Module) {
+	groups := map[string]map[string]bool{}
+	for _, module := range modules {
+		if groups[module.Name] == nil {
+			groups[module.Name] = map[string]bool{}
+		}
+		groups[module.Name][module.Group] = true
+	}
+	for i := range modules {
+		module := &modules[i]
+		if module.Root || module.SourceInfo != projectSourceInfo || module.Group == "" || len(groups[module.Name]) < 2 {
+			continue
+		}
+		module.Name = module.Group + "." + module.Name
+	}
+}
+
+// declaredScopes records the scope each dependency of the project is declared in, or managed in when the
+// declaration has none. The first declaration of a dependency wins
+func declaredScopes(scopes map[string]string, project gopom.Project) {
+	for _, dep := range project.Dependencies {
+		name := dependencyModuleName(dep)
+		if _, ok := scopes[name]; ok {
+			continue
+		}
+		scope := strings.TrimSpace(dep.Scope)
+		for _, managed := range project.DependencyManagement.Dependencies {
+			if scope == "" && dependencyModuleName(managed) == name && resolveCoordinate(project, managed.GroupID) == resolveCoordinate(project, dep.GroupID) {
+				scope = strings.TrimSpace(managed.Scope)
+			}
+		}
+		scopes[name] = scope
+	}
+}
+
+// excludeUnselectedScopes drops the dependencies whose scope is not selected by the include and exclude scopes.
+// Maven having selected the listed dependencies, the scope it resolved them in wins over the declared one and the
+// dependencies it left out are dropped. The declared scopes are only relied on when mvn dependency:list listed nothing
+func excludeUnselectedScopes(modules []models.Module, declared map[string]string, dependencyList []string, options Options) []models.Module {
+	listed := listedScopes(dependencyList)
+	selected := func(name string) bool {
+		if len(listed) == 0 {
+			return options.selectsScope(declared[name])
+		}
+		scope, ok := listed[name]
+		return ok && options.selectsScope(scope)
 	}
-	parentMod := convertProjectLevelPackageToModule(project)
+
+	filtered := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		provenance := module.GetProperty(provenanceProperty)
+		dependency := provenance == provenanceDependencies || provenance == provenanceDependencyList
+		if module.Root || !dependency || selected(module.Name) {
+			filtered = append(filtered, module)
+			continue
+		}
+		for i := range modules {
+			delete(modules[i].Modules, dependencyKey(module))
+		}
+	}
+	return filtered
+}
+
+// listedScopes returns the scope each dependency listed by mvn dependency:list was resolved in
+func listedScopes(dependencyList []string) map[string]string {
+	listed := map[string]string{}
+	for _, item := range dependencyList {
+		if dep, ok := parseDependencyListEntry(item); ok {
+			listed[dependencyModuleName(dep)] = dep.Scope
+		}
+	}
+	return listed
+}
+
+// separateProvidedModules marks the dependencies in the provided scope, as resolved by Maven or else declared, as
+// provided by the container at runtime when the scope is selected, i.e. in container scans or when included.
+// Source scans drop them otherwise
+func separateProvidedModules(modules []models.Module, declared map[string]string, dependencyList []string, options Options) []models.Module {
+	listed := listedScopes(dependencyList)
+	provided := func(module models.Module) bool {
+		provenance := module.GetProperty(provenanceProperty)
+		if module.Root || (provenance != provenanceDependencies && provenance != provenanceDependencyList) {
+			return false
+		}
+		scope, ok := listed[module.Name]
+		if !ok {
+			scope = declared[module.Name]
+		}
+		return strings.TrimSpace(scope) == providedScope
+	}
+
+	filtered := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		if !provided(module) {
+			filtered = append(filtered, module)
+			continue
+		}
+		for i := range modules {
+			dep, ok := modules[i].Modules[dependencyKey(module)]
+			if !ok {
+				continue
+			}
+			if options.selectsScope(providedScope) {
+				linked := *dep
+				markProvided(&linked)
+				modules[i].Modules[dependencyKey(module)] = &linked
+			} else {
+				delete(modules[i].Modules, dependencyKey(module))
+			}
+		}
+		if options.selectsScope(providedScope) {
+			markProvided(&module)
+			filtered = append(filtered, module)
+		}
+	}
+	return filtered
+}
+
+// markProvided sets the provided scope of a module present at runtime in the container rather than bundled
+func markProvided(mod *models.Module) {
+	mod.Scope = providedScope
+	mod.SourceInfo = strings.TrimPrefix(mod.SourceInfo+", provided by the container at runtime", ", ")
+}
+
+// excludeManagedOnlyModules drops dependencyManagement entries which are not declared as a dependency,
+// resolved by mvn dependency list or used by a submodule, since they only constrain versions
+func excludeManagedOnlyModules(modules []models.Module, project gopom.Project, dependencyList []string) []models.Module {
+	used := map[string]bool{}
+	for _, dep := range project.Dependencies {
+		used[treeKey(resolveCoordinate(project, dep.GroupID), dependencyModuleName(dep))] = true
+	}
+	for _, item := range dependencyList {
+		if dep, ok := parseDependencyListEntry(item); ok {
+			used[treeKey(dep.GroupID, dependencyModuleName(dep))] = true
+		}
+	}
+	for _, module := range modules {
+		if module.Root {
+			continue
+		}
+		for key := range module.Modules {
+			used[key] = true
+		}
+	}
+
+	filtered := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		if module.GetProperty(provenanceProperty) == provenanceDependencyManagement && !used[dependencyKey(module)] {
+			for i := range modules {
+				if modules[i].Root {
+					delete(modules[i].Modules, dependencyKey(module))
+				}
+			}
+			continue
+		}
+		filtered = append(filtered, module)
+	}
+	return filtered
+}
+
+// convertDeclaredModules builds the root module followed by the modules declared statically in pom.xml
+func convertDeclaredModules(project gopom.Project, options Options) []models.Module {
+	modules := make([]models.Module, 0)
+	parentMod := convertProjectLevelPackageToModule(project, options)
 	parentMod.Root = true
 	modules = append(modules, parentMod)
 
 	// iterate over dependencyManagement
 	for _, dependencyManagement := range project.DependencyManagement.Dependencies {
-		mod := createModule(dependencyManagement.GroupID, dependencyManagement.ArtifactID, dependencyManagement.Version, project)
+		mod := createModule(dependencyManagement, project, provenanceDependencyManagement, options)
 		modules = append(modules, mod)
-		parentMod.Modules[mod.Name] = &mod
+		parentMod.Modules[dependencyKey(mod)] = &mod
 	}
 
 	// iterate over dependencies
 	for _, dep := range project.Dependencies {
-		mod := createModule(dep.GroupID, dep.ArtifactID, dep.Version, project)
+		mod := createModule(dep, project, provenanceDependencies, options)
 		modules = append(modules, mod)
-		parentMod.Modules[mod.Name] = &mod
+		parentMod.Modules[dependencyKey(mod)] = &mod
 	}
 
 	// iterate over Plugins
 	for _, plugin := range project.Build.Plugins {
 		// If plugin has groupId, skip here. Plugin details will be available at PluginManagement
 		if len(plugin.GroupID) == 0 {
-			mod := createModule(plugin.GroupID, plugin.ArtifactID, plugin.Version, project)
+			mod := createModule(pluginDependency(plugin), project, provenancePlugins, options)
 			modules = append(modules, mod)
-			parentMod.Modules[mod.Name] = &mod
+			parentMod.Modules[dependencyKey(mod)] = &mod
 		}
 	}
 
 	// iterate over PluginManagement
 	for _, plugin := range project.Build.PluginManagement.Plugins {
-		mod := createModule(plugin.GroupID, plugin.ArtifactID, plugin.Version, project)
+		mod := createModule(pluginDependency(plugin), project, provenancePluginManagement, options)
 		modules = append(modules, mod)
-		parentMod.Modules[mod.Name] = &mod
-	}
-
-	dependencyList, err := getDependencyList()
-	if err != nil {
-		fmt.Println("error in getting mvn dependency list and parsing it")
-		return modules, err
+		parentMod.Modules[dependencyKey(mod)] = &mod
 	}
+	return modules
+}
 
-	// Add additional dependency from mvn dependency list to pom.xml dependency list
-	var i int
-	for i < len(dependencyList)-2 { // skip 1 empty line and Finished statement line
-		// If any errors captured in mvn dependency, ignore that
-		if strings.Contains(dependencyList[i], "Invalid module name") {
-			i++
+// mergeDependencyList creates modules for the mvn dependency list entries not already declared in pom.xml
+func mergeDependencyList(project gopom.Project, dependencyList []string, parentMod *models.Module, options Options) []models.Module {
+	var modules []models.Module
+	for _, entry := range dependencyList {
+		dependencyItem, ok := parseDependencyListEntry(entry)
+		if !ok {
 			continue
 		}
-		dependencyItem := strings.Split(dependencyList[i], ":")[1]
+		name := dependencyModuleName(dependencyItem)
 
-		found := false
-		// iterate over dependencies
-		for _, dep := range project.Dependencies {
-			if dep.ArtifactID == dependencyItem {
-				found = true
-				break
-			}
-		}
+		// iterate over dependencies and dependencyManagement
+		found := findInDependency(project.Dependencies, name) ||
+			findInDependency(project.DependencyManagement.Dependencies, name)
 
 		if !found {
-			for _, dependencyManagement := range project.DependencyManagement.Dependencies {
-				if dependencyManagement.ArtifactID == dependencyItem {
-					found = true
-					break
-				}
-			}
-		}
-
-		if !found {
-			groupID := strings.Split(dependencyList[i], ":")[0]
-			version := strings.Split(dependencyList[i], ":")[3]
-			mod := createModule(strings.TrimSpace(groupID), dependencyItem, version, project)
+			mod := createModule(dependencyItem, project, provenanceDependencyList, options)
+			recordCoordinate(&mod, strings.TrimSpace(entry), options)
 			modules = append(modules, mod)
-			parentMod.Modules[mod.Name] = &mod
+			parentMod.Modules[dependencyKey(mod)] = &mod
 		}
-		i++
 	}
+	return modules
+}
 
-	if lookForDepenent {
-		// iterate over Modules
-		for _, module := range project.Modules {
-			additionalModules, err := convertPkgModulesToModule(modules, fpath, module, project)
-			if err != nil {
-				// continue reading other module pom.xml file
-				continue
-			}
-			modules = append(modules, additionalModules...)
+// mergeDependencyTree creates modules for the resolved nodes of the dependency tree missing from the modules, e.g.
+// when mvn dependency:list left them out for their scope. They are linked along the tree edges like the other modules
+func mergeDependencyTree(project gopom.Project, modules []models.Module, tree dependencyTree, options Options) []models.Module {
+	known := treeModuleIndex(modules)
+	keys := make([]string, 0, len(tree.nodes))
+	for key := range tree.nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var merged []models.Module
+	for _, key := range keys {
+		if _, ok := known[key]; ok || !options.selectsScope(tree.nodes[key].Scope) {
+			continue
 		}
+		mod := createModule(tree.nodes[key], project, provenanceDependencyTree, options)
+		if strings.TrimSpace(tree.nodes[key].Scope) == providedScope {
+			markProvided(&mod)
+		}
+		merged = append(merged, mod)
 	}
-	return modules, nil
+	return merged
+}
+
+// dependencyTree is the transitive dependency graph read from mvn dependency:tree, keyed by treeKey
+type dependencyTree struct {
+	// edges lists the dependencies of each module
+	edges map[string][]string
+	// scopes is the scope each dependency was resolved in
+	scopes map[string]string
+	// versions lists the versions each dependency is requested at along the paths of the tree
+	versions map[string][]string
+	// nodes are the coordinates each dependency was resolved as
+	nodes map[string]gopom.Dependency
 }
 
-func getTransitiveDependencyList(workingDir string) (map[string][]string, error) {
-	path := filepath.Join(os.TempDir(), "JavaMavenTDTreeOutput.txt")
-	os.Remove(path)
+func newDependencyTree() dependencyTree {
+	return dependencyTree{
+		edges:    map[string][]string{},
+		scopes:   map[string]string{},
+		versions: map[string][]string{},
+		nodes:    map[string]gopom.Dependency{},
+	}
+}
 
-	command := exec.Command("mvn", "dependency:tree", "-DoutputType=dot", "-DappendOutput=true", "-DoutputFile="+path)
-	command.Dir = workingDir
-	out, err := command.CombinedOutput()
+// getTransitiveDependencyList runs mvn dependency:tree, verbose when the requested versions are recorded
+// so that the dependencies omitted for conflict are listed along with the requested version
+func getTransitiveDependencyList(workingDir string, options Options) (dependencyTree, error) {
+	// a file of its own, so that concurrent generations do not append to the same tree
+	output, err := ioutil.TempFile("", "spdx-maven-tree-*.dot")
 	if err != nil {
-		log.Print(string(out))
-		return nil, err
+		return dependencyTree{}, fmt.Errorf("failed to create the dependency tree file: %w", err)
 	}
+	path := output.Name()
+	output.Close()
+	defer os.Remove(path)
 
-	tdList, err := readAndgetTransitiveDependencyList(path)
+	args := append([]string{"dependency:tree", "-DoutputType=dot", "-DappendOutput=true", "-DoutputFile=" + path}, options.pomArgs()...)
+	if options.RecordRequestedVersions {
+		args = append(args, "-Dverbose")
+	}
+	args, err = options.withMavenConfig(workingDir).mavenArgs(args...)
 	if err != nil {
-		return nil, err
+		return dependencyTree{}, err
 	}
-	return tdList, nil
+	out, err := options.runMaven(workingDir, args, (*exec.Cmd).CombinedOutput)
+	if err != nil {
+		log.Debug(string(out))
+		if err := options.offlineResolutionError(out); err != nil {
+			return dependencyTree{}, err
+		}
+		return dependencyTree{}, fmt.Errorf("mvn dependency:tree failed: %w", err)
+	}
+
+	return readAndgetTransitiveDependencyList(path)
 }
 
-func readAndgetTransitiveDependencyList(path string) (map[string][]string, error) {
+func readAndgetTransitiveDependencyList(path string) (dependencyTree, error) {
 
 	file, err := os.Open(path)
 
 	if err != nil {
-		log.Println(err)
-		return nil, err
+		return dependencyTree{}, fmt.Errorf("failed to open the dependency tree: %w", err)
 	}
 
 	scanner := bufio.NewScanner(file)
@@ -461,10 +880,13 @@ func readAndgetTransitiveDependencyList(path string) (map[string][]string, error
 		text = append(text, scanner.Text())
 	}
 	file.Close()
+	if len(text) == 0 {
+		return dependencyTree{}, fmt.Errorf("the dependency tree %s is empty", path)
+	}
 
-	tdList := map[string][]string{}
-	handlePkgs(text, tdList)
-	return tdList, nil
+	tree := newDependencyTree()
+	handlePkgs(text, tree)
+	return tree, nil
 }
 
 func doesDependencyExists(tdList map[string][]string, lData string, val string) bool {
@@ -476,19 +898,40 @@ func doesDependencyExists(tdList map[string][]string, lData string, val string)
 	return false
 }
 
-func handlePkgs(text []string, tdList map[string][]string) {
+// versionAnnotation matches the verbose tree annotations naming another version of the dependency,
+// `(omitted for conflict with 1.3)` naming the resolved one and `(version managed from 1.1)` the requested one
+var versionAnnotation = regexp.MustCompile(`\((?:omitted for conflict with|version managed from) ([^\s;)]+)`)
+
+// handlePkgs reads the edges of the dot formatted dependency tree along with the scope each dependency was resolved in
+// and the versions it is requested at. Verbose tree annotations are not part of the node coordinates
+func handlePkgs(text []string, tree dependencyTree) {
+	tdList, scopes := tree.edges, tree.scopes
 	i := 0
 	var pkgName string
 	isEmptyMainPkg := false
 
 	for i < len(text) {
 		if strings.Contains(text[i], "{") {
-			pkgName = strings.Split(text[i], ":")[1]
+			root := strings.TrimPrefix(strings.TrimSpace(text[i]), "digraph")
+			pkgName, _, _ = parseTreeNode(strings.TrimSuffix(strings.TrimSpace(root), "{"))
 		} else if strings.Contains(text[i], "->") {
 			lhsData := strings.Split(text[i], "->")[0]
 			rhsData := strings.Split(text[i], "->")[1]
-			lData := strings.Split(lhsData, ":")[1]
-			rData := strings.Split(rhsData, ":")[1]
+			lData, _, _ := parseTreeNode(lhsData)
+			rData, dep, annotation := parseTreeNode(rhsData)
+			if dep.ArtifactID != "" {
+				// an omitted node is not the one the dependency was resolved as
+				if !strings.Contains(annotation, "omitted") {
+					if dep.Scope != "" {
+						scopes[rData] = dep.Scope
+					}
+					tree.nodes[rData] = dep
+				}
+				tree.addVersion(rData, dep.Version)
+			}
+			if match := versionAnnotation.FindStringSubmatch(annotation); match != nil {
+				tree.addVersion(rData, match[1])
+			}
 
 			// If package name is same, add right hand side dependency
 			if !isEmptyMainPkg && lData == pkgName {
@@ -505,38 +948,144 @@ func handlePkgs(text []string, tdList map[string][]string) {
 	}
 }
 
-func buildDependenciesGraph(modules []models.Module, tdList map[string][]string) {
-	moduleMap := map[string]models.Module{}
-	moduleIndex := map[string]int{}
+// parseTreeNode reads a node of the dot formatted dependency tree, `"<groupId>:<artifactId>:<type>[:<classifier>]:<version>[:<scope>]"`
+// followed by the verbose annotations, if any. The node is named like the module it resolves to, so that the
+// classified artifacts, e.g. the `sources` or `tests` jars, are nodes distinct from the main artifact, and keyed
+// along with its groupId, so that the artifacts sharing an artifactId across groups are distinct nodes
+func parseTreeNode(text string) (string, gopom.Dependency, string) {
+	node := strings.Trim(text, " \t\";")
+	annotation := ""
+	if idx := strings.Index(node, " ("); idx >= 0 {
+		node, annotation = node[:idx], node[idx:]
+	}
+	if dep, ok := parseDependencyListEntry(node); ok {
+		return treeKey(dep.GroupID, dependencyModuleName(dep)), dep, annotation
+	}
+	if fields := strings.Split(node, ":"); len(fields) > 1 {
+		return treeKey(fields[0], fields[1]), gopom.Dependency{}, annotation
+	}
+	return node, gopom.Dependency{}, annotation
+}
+
+// treeKey is the key of a dependency in the dependency tree, `<groupId>:<module name>`
+func treeKey(groupID, name string) string {
+	return strings.TrimSpace(groupID) + ":" + name
+}
+
+// dependencyKey is the key a module is linked to its dependents with, its tree key, as artifacts of distinct groups
+// may share a name
+func dependencyKey(module models.Module) string {
+	return treeKey(module.Group, module.Name)
+}
+
+// moduleTreeKeys returns the tree key of a module, followed, for a submodule named after its groupId by
+// qualifySharedSubmoduleNames, by the key of its unqualified name, the one of its tree node
+func moduleTreeKeys(module models.Module) []string {
+	keys := []string{treeKey(module.Group, module.Name)}
+	qualifier := module.Group + "."
+	if module.SourceInfo == projectSourceInfo && module.Group != "" && strings.HasPrefix(module.Name, qualifier) {
+		keys = append(keys, treeKey(module.Group, strings.TrimPrefix(module.Name, qualifier)))
+	}
+	return keys
+}
+
+// treeModuleIndex indexes the modules by their tree keys, a module keyed by its name winning over a qualified one
+func treeModuleIndex(modules []models.Module) map[string]int {
+	index := map[string]int{}
+	for i, module := range modules {
+		for _, key := range moduleTreeKeys(module)[1:] {
+			index[key] = i
+		}
+	}
+	for i, module := range modules {
+		index[moduleTreeKeys(module)[0]] = i
+	}
+	return index
+}
+
+// addVersion records a version a dependency is requested at, once
+func (t dependencyTree) addVersion(name, version string) {
+	if version == "" {
+		return
+	}
+	for _, v := range t.versions[name] {
+		if v == version {
+			return
+		}
+	}
+	t.versions[name] = append(t.versions[name], version)
+}
 
-	for idx, module := range modules {
-		moduleMap[module.Name] = module
-		moduleIndex[module.Name] = idx
+// linkDependencies builds the dependency graph from the transitive tree. When the tree could not be
+// obtained the dependencies are attached to the root module, unless a strict dependency tree is required, the
+// local repository of an offline build is incomplete or mvn was killed, the generation being cancelled or hung
+func linkDependencies(modules []models.Module, tree dependencyTree, treeErr error, options Options) error {
+	if treeErr == nil {
+		if options.RecordRequestedVersions {
+			recordRequestedVersions(modules, tree)
+		}
+		buildDependenciesGraph(modules, tree)
+		return nil
+	}
+	if options.StrictDependencyTree || errors.Is(treeErr, errIncompleteLocalRepository) || errors.Is(treeErr, errMavenTimeout) ||
+		errors.Is(treeErr, context.Canceled) {
+		return treeErr
+	}
+
+	options.report(models.DiagnosticWarning, diagnosticPartialTree, "",
+		fmt.Sprintf("mvn dependency:tree unavailable (%v), transitive relationships are missing and dependencies are attached to the root", treeErr))
+	for i := range modules {
+		if !modules[i].Root {
+			continue
+		}
+		for j := range modules {
+			if modules[j].Root {
+				continue
+			}
+			if _, ok := modules[i].Modules[dependencyKey(modules[j])]; !ok {
+				dep := modules[j]
+				modules[i].Modules[dependencyKey(dep)] = &dep
+			}
+		}
+	}
+	return nil
+}
+
+// buildDependenciesGraph links the modules along the edges of the dependency tree, the linked copies carry the
+// scope the dependency was resolved in
+func buildDependenciesGraph(modules []models.Module, tree dependencyTree) {
+	tdList, scopes := tree.edges, tree.scopes
+	moduleIndex := treeModuleIndex(modules)
+	moduleMap := map[string]models.Module{}
+	for key, idx := range moduleIndex {
+		moduleMap[key] = modules[idx]
 	}
 
 	for i := range tdList {
 		for j := range tdList[i] {
 
 			if len(tdList[i][j]) > 0 {
-				moduleName := i
-				if _, ok := moduleMap[moduleName]; !ok {
+				moduleKey := i
+				if _, ok := moduleMap[moduleKey]; !ok {
 					continue
 				}
 
-				depName := tdList[i][j]
-				depModule, ok := moduleMap[depName]
+				depKey := tdList[i][j]
+				depModule, ok := moduleMap[depKey]
 				if !ok {
 					continue
 				}
 
-				modules[moduleIndex[moduleName]].Modules[depName] = &models.Module{
+				modules[moduleIndex[moduleKey]].Modules[dependencyKey(depModule)] = &models.Module{
 					Name:                    depModule.Name,
+					Group:                   depModule.Group,
 					Version:                 depModule.Version,
 					Path:                    depModule.Path,
 					LocalPath:               depModule.LocalPath,
 					Supplier:                depModule.Supplier,
 					PackageURL:              depModule.PackageURL,
 					CheckSum:                depModule.CheckSum,
+					AdditionalCheckSums:     depModule.AdditionalCheckSums,
 					PackageHomePage:         depModule.PackageHomePage,
 					PackageDownloadLocation: depModule.PackageDownloadLocation,
 					LicenseConcluded:        depModule.LicenseConcluded,
@@ -545,9 +1094,37 @@ func buildDependenciesGraph(modules []models.Module, tdList map[string][]string)
 					OtherLicense:            depModule.OtherLicense,
 					Copyright:               depModule.Copyright,
 					PackageComment:          depModule.PackageComment,
+					SourceInfo:              depModule.SourceInfo,
+					Properties:              depModule.Properties,
+					Scope:                   scopes[depKey],
+					Purpose:                 depModule.Purpose,
 					Root:                    depModule.Root,
 				}
 			}
 		}
 	}
 }
+
+// recordRequestedVersions records on each module the versions other than the resolved one it is requested at
+// along the paths of the tree, e.g. `1.1, 1.2` for a dependency resolved at 1.3
+func recordRequestedVersions(modules []models.Module, tree dependencyTree) {
+	for i := range modules {
+		var versions []string
+		for _, key := range moduleTreeKeys(modules[i]) {
+			if versions = tree.versions[key]; len(versions) > 0 {
+				break
+			}
+		}
+		var alternates []string
+		for _, version := range versions {
+			if version != modules[i].Version {
+				alternates = append(alternates, version)
+			}
+		}
+		if len(alternates) == 0 {
+			continue
+		}
+		sort.Strings(alternates)
+		modules[i].SetProperty(requestedVersionsProperty, strings.Join(alternates, ", "))
+	}
+}
@@ -0,0 +1,769 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func findModule(t *testing.T, modules []models.Module, name string) models.Module {
+	for _, mod := range modules {
+		if mod.Name == name {
+			return mod
+		}
+	}
+	t.Fatalf("module %s not found", name)
+	return models.Module{}
+}
+
+func TestModuleProvenance(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	modules := convertDeclaredModules(project, Options{})
+	root := modules[0]
+	dependencyList := []string{
+		"   com.google.guava:guava:jar:30.1-jre:compile",
+		"   org.hamcrest:hamcrest-core:jar:1.3:test",
+		"",
+		"Finished",
+	}
+	modules = append(modules, mergeDependencyList(project, dependencyList, &root, Options{})...)
+
+	assert.Equal(t, "", root.GetProperty(provenanceProperty))
+	assert.Equal(t, provenanceDependencyManagement, findModule(t, modules, "guava").GetProperty(provenanceProperty))
+	assert.Equal(t, provenanceDependencies, findModule(t, modules, "junit").GetProperty(provenanceProperty))
+	assert.Equal(t, provenancePlugins, findModule(t, modules, "maven-compiler-plugin").GetProperty(provenanceProperty))
+	assert.Equal(t, provenancePluginManagement, findModule(t, modules, "maven-surefire-plugin").GetProperty(provenanceProperty))
+	assert.Equal(t, provenanceDependencyList, findModule(t, modules, "hamcrest-core").GetProperty(provenanceProperty))
+	assert.Equal(t, 6, len(modules))
+}
+
+func TestModuleSourceInfo(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	modules := convertDeclaredModules(project, Options{})
+	root := modules[0]
+	dependencyList := []string{"   org.hamcrest:hamcrest-core:jar:1.3:test", "", "Finished"}
+	modules = append(modules, mergeDependencyList(project, dependencyList, &root, Options{})...)
+
+	assert.Equal(t, "project described by pom.xml", root.SourceInfo)
+	assert.Equal(t, "declared in pom.xml dependencyManagement", findModule(t, modules, "guava").SourceInfo)
+	assert.Equal(t, "declared in pom.xml dependencies", findModule(t, modules, "junit").SourceInfo)
+	assert.Equal(t, "declared in pom.xml build plugins", findModule(t, modules, "maven-compiler-plugin").SourceInfo)
+	assert.Equal(t, "declared in pom.xml build pluginManagement", findModule(t, modules, "maven-surefire-plugin").SourceInfo)
+	assert.Equal(t, "resolved via mvn dependency:list", findModule(t, modules, "hamcrest-core").SourceInfo)
+
+	// the source info is kept on the modules linked by the dependency tree
+	tree := newDependencyTree()
+	tree.edges["junit:junit"] = []string{"org.hamcrest:hamcrest-core"}
+	buildDependenciesGraph(modules, tree)
+	assert.Equal(t, "resolved via mvn dependency:list", findModule(t, modules, "junit").Modules["org.hamcrest:hamcrest-core"].SourceInfo)
+}
+
+func TestExcludeManagedOnlyModules(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	modules := excludeManagedOnlyModules(convertDeclaredModules(project, Options{}), project, []string{})
+	for _, mod := range modules {
+		assert.NotEqual(t, "guava", mod.Name)
+	}
+	assert.NotContains(t, modules[0].Modules, "com.google.guava:guava")
+	assert.Contains(t, modules[0].Modules, "junit:junit")
+
+	resolved := []string{"   com.google.guava:guava:jar:30.1-jre:compile"}
+	modules = excludeManagedOnlyModules(convertDeclaredModules(project, Options{}), project, resolved)
+	assert.Equal(t, "guava", findModule(t, modules, "guava").Name)
+	assert.Contains(t, modules[0].Modules, "com.google.guava:guava")
+}
+
+func TestLinkDependenciesWithoutTree(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	options := Options{diagnostics: &models.Diagnostics{}}
+	modules := convertDeclaredModules(project, options)
+	root := modules[0]
+	dependencyList := []string{"   org.hamcrest:hamcrest-core:jar:1.3:test", "", "Finished"}
+	modules = append(modules, mergeDependencyList(project, dependencyList, &root, options)...)
+	delete(modules[0].Modules, "org.hamcrest:hamcrest-core")
+
+	errTree := errors.New("maven-dependency-plugin not available")
+	assert.NoError(t, linkDependencies(modules, dependencyTree{}, errTree, options))
+	assert.Equal(t, len(modules)-1, len(modules[0].Modules))
+	assert.Contains(t, modules[0].Modules, "org.hamcrest:hamcrest-core")
+
+	diagnostics := options.diagnostics.List()
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, diagnosticPartialTree, diagnostics[0].Code)
+
+	options.StrictDependencyTree = true
+	assert.Equal(t, errTree, linkDependencies(modules, dependencyTree{}, errTree, options))
+}
+
+func TestDeclaredAndEffectiveViews(t *testing.T) {
+	m := New()
+	declared, err := m.ListDeclaredModules("testdata/provenance")
+	assert.NoError(t, err)
+
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+	effective := convertDeclaredModules(project, Options{})
+	root := effective[0]
+	dependencyList := []string{"   org.hamcrest:hamcrest-core:jar:1.3:test", "", "Finished"}
+	effective = append(effective, mergeDependencyList(project, dependencyList, &root, Options{})...)
+
+	names := func(modules []models.Module) map[string]bool {
+		result := map[string]bool{}
+		for _, mod := range modules {
+			result[mod.Name] = true
+		}
+		return result
+	}
+	assert.False(t, names(declared)["hamcrest-core"])
+	assert.True(t, names(effective)["hamcrest-core"])
+	assert.Equal(t, len(declared)+1, len(effective))
+}
+
+// treeGroups are the groupIds of the modules of the dependency tree fixtures
+var treeGroups = map[string]string{
+	"app":           "com.example",
+	"core":          "com.example",
+	"core-sources":  "com.example",
+	"core-tests":    "com.example",
+	"slf4j-api":     "org.slf4j",
+	"postgresql":    "org.postgresql",
+	"junit":         "junit",
+	"hamcrest-core": "org.hamcrest",
+	"mockito-core":  "org.mockito",
+	"guava":         "com.google.guava",
+	"objenesis":     "org.objenesis",
+}
+
+// treeModules returns the modules of the dependency tree fixtures, app being the root
+func treeModules(names ...string) []models.Module {
+	var modules []models.Module
+	for _, name := range names {
+		modules = append(modules, models.Module{Name: name, Group: treeGroups[name], Root: name == "app", Modules: map[string]*models.Module{}})
+	}
+	return modules
+}
+
+func TestDependencyGraphScopes(t *testing.T) {
+	tree, err := readAndgetTransitiveDependencyList("testdata/tree/tree.dot")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"org.slf4j:slf4j-api":        "compile",
+		"org.postgresql:postgresql":  "runtime",
+		"junit:junit":                "test",
+		"org.hamcrest:hamcrest-core": "test",
+	}, tree.scopes)
+
+	modules := treeModules("app", "slf4j-api", "postgresql", "junit", "hamcrest-core")
+	buildDependenciesGraph(modules, tree)
+
+	app := findModule(t, modules, "app")
+	assert.Equal(t, "compile", app.Modules["org.slf4j:slf4j-api"].Scope)
+	assert.Equal(t, "runtime", app.Modules["org.postgresql:postgresql"].Scope)
+	assert.Equal(t, "test", findModule(t, modules, "junit").Modules["org.hamcrest:hamcrest-core"].Scope)
+}
+
+func TestDependencyGraphClassifiers(t *testing.T) {
+	tree, err := readAndgetTransitiveDependencyList("testdata/tree/classifier.dot")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"com.example:core", "com.example:core-sources", "com.example:core-tests"}, tree.edges["com.example:app"])
+	assert.Equal(t, []string{"org.slf4j:slf4j-api"}, tree.edges["com.example:core"])
+
+	modules := treeModules("app", "core", "core-sources", "core-tests", "slf4j-api")
+	buildDependenciesGraph(modules, tree)
+
+	app := findModule(t, modules, "app")
+	assert.Len(t, app.Modules, 3)
+	assert.Equal(t, "compile", app.Modules["com.example:core"].Scope)
+	assert.Equal(t, "compile", app.Modules["com.example:core-sources"].Scope)
+	assert.Equal(t, "test", app.Modules["com.example:core-tests"].Scope)
+	assert.Contains(t, findModule(t, modules, "core").Modules, "org.slf4j:slf4j-api")
+	assert.Empty(t, findModule(t, modules, "core-sources").Modules)
+}
+
+func TestDependencyGraphArtifactIDAcrossGroups(t *testing.T) {
+	tree, err := readAndgetTransitiveDependencyList("testdata/tree/groups.dot")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"commons-logging:commons-logging"}, tree.edges["com.example:logging-a"])
+	assert.Equal(t, []string{"org.example.shaded:commons-logging"}, tree.edges["com.example:logging-b"])
+	assert.Equal(t, "runtime", tree.scopes["org.example.shaded:commons-logging"])
+
+	modules := []models.Module{
+		{Name: "app", Group: "com.example", Root: true},
+		{Name: "logging-a", Group: "com.example"},
+		{Name: "logging-b", Group: "com.example"},
+		{Name: "commons-logging", Group: "commons-logging", Version: "1.2"},
+		{Name: "commons-logging", Group: "org.example.shaded", Version: "1.1"},
+		{Name: "shaded-core", Group: "org.example.shaded", Version: "1.0"},
+	}
+	for i := range modules {
+		modules[i].Modules = map[string]*models.Module{}
+	}
+	buildDependenciesGraph(modules, tree)
+
+	a := findModule(t, modules, "logging-a").Modules["commons-logging:commons-logging"]
+	assert.Equal(t, "commons-logging", a.Group)
+	assert.Equal(t, "1.2", a.Version)
+	assert.Equal(t, "compile", a.Scope)
+	b := findModule(t, modules, "logging-b").Modules["org.example.shaded:commons-logging"]
+	assert.Equal(t, "org.example.shaded", b.Group)
+	assert.Equal(t, "1.1", b.Version)
+	assert.Equal(t, "runtime", b.Scope)
+	assert.Empty(t, modules[3].Modules)
+	assert.Contains(t, modules[4].Modules, "org.example.shaded:shaded-core")
+}
+
+func TestDependenciesSharingArtifactID(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/sharedartifact")
+	assert.NoError(t, err)
+
+	modules := convertDeclaredModules(project, Options{LocalRepository: t.TempDir()})
+	assert.Len(t, modules[0].Modules, 2)
+	assert.Equal(t, "a", modules[0].Modules["a:util"].Group)
+	assert.Equal(t, "b", modules[0].Modules["b:util"].Group)
+
+	// the dependency tree links both as well
+	tree := newDependencyTree()
+	tree.edges["com.example:app"] = []string{"a:util", "b:util"}
+	for i := range modules {
+		modules[i].Modules = map[string]*models.Module{}
+	}
+	buildDependenciesGraph(modules, tree)
+	assert.Len(t, modules[0].Modules, 2)
+
+	// and they are written as distinct packages
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	document, err := format.New(format.Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    func() []models.Module { return modules },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, document.Render())
+	rendered, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(rendered), "Relationship: SPDXRef-Package-app DEPENDS_ON SPDXRef-Package-a-util-1.0")
+	assert.Contains(t, string(rendered), "Relationship: SPDXRef-Package-app DEPENDS_ON SPDXRef-Package-b-util-1.0")
+}
+
+func TestConcurrentDecodes(t *testing.T) {
+	defer stubMaven(t, "cat dependency-list.txt")()
+
+	projects := map[string][]string{
+		"alpha": {"alpha", "guava", "failureaccess", "checker-qual"},
+		"beta":  {"beta", "junit", "hamcrest-core"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for project, expected := range projects {
+			wg.Add(1)
+			go func(project string, expected []string) {
+				defer wg.Done()
+				modules, err := convertPOMReaderToModules(filepath.Join("testdata", "concurrent", project), true, Options{})
+				assert.NoError(t, err)
+
+				var names []string
+				for _, mod := range modules {
+					names = append(names, mod.Name)
+				}
+				assert.ElementsMatch(t, expected, names, "modules of %s", project)
+			}(project, expected)
+		}
+	}
+	wg.Wait()
+}
+
+func TestListInventorySkipsDependencyTree(t *testing.T) {
+	mvn := newFakeMaven(t, filepath.Join("testdata", "concurrent", "alpha"))
+
+	plugin := NewWithOptions(Options{
+		ChecksumProvider: fakeChecksumProvider{
+			"com.google.guava:failureaccess:1.0.1": {models.HashAlgoSHA1: "1dcf1de382a0bf95a3d8b0849546c88bac1292c9"},
+		},
+	})
+	modules, err := plugin.ListInventory(filepath.Join("testdata", "concurrent", "alpha"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dependency:list"}, mvn.goals(t))
+
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	assert.ElementsMatch(t, []string{"alpha", "guava", "failureaccess", "checker-qual"}, names)
+	assert.True(t, modules[0].Root)
+
+	failureaccess := findModule(t, modules, "failureaccess")
+	assert.Equal(t, "1.0.1", failureaccess.Version)
+	assert.Equal(t, "1dcf1de382a0bf95a3d8b0849546c88bac1292c9", failureaccess.CheckSum.Value)
+	assert.Equal(t, "3.5.0", findModule(t, modules, "checker-qual").Version)
+}
+
+func TestMergeDependencyTreeOnlyModules(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+	tree, err := readAndgetTransitiveDependencyList("testdata/tree/tree.dot")
+	assert.NoError(t, err)
+
+	// postgresql is left out of the dependency list
+	modules := treeModules("app", "slf4j-api", "junit", "hamcrest-core")
+	merged := mergeDependencyTree(project, modules, tree, Options{})
+	assert.Len(t, merged, 1)
+	modules = append(modules, merged...)
+	assert.NoError(t, linkDependencies(modules, tree, nil, Options{}))
+
+	postgresql := findModule(t, modules, "postgresql")
+	assert.Equal(t, "42.2.20", postgresql.Version)
+	assert.Equal(t, "org.postgresql", postgresql.Group)
+	assert.Equal(t, provenanceDependencyTree, postgresql.GetProperty(provenanceProperty))
+	assert.Equal(t, "resolved via mvn dependency:tree", postgresql.SourceInfo)
+	assert.Equal(t, "pkg:maven/org.postgresql/postgresql@42.2.20", postgresql.PackageURL)
+
+	app := findModule(t, modules, "app")
+	assert.Equal(t, "runtime", app.Modules["org.postgresql:postgresql"].Scope)
+	assert.Equal(t, "42.2.20", app.Modules["org.postgresql:postgresql"].Version)
+}
+
+func TestRecordRequestedVersions(t *testing.T) {
+	tree, err := readAndgetTransitiveDependencyList("testdata/tree/verbose.dot")
+	assert.NoError(t, err)
+	assert.Equal(t, "test", tree.scopes["org.hamcrest:hamcrest-core"])
+	assert.Equal(t, "compile", tree.scopes["com.google.guava:guava"])
+
+	versions := map[string]string{
+		"app":           "1.0.0",
+		"junit":         "4.13.2",
+		"mockito-core":  "3.11.2",
+		"guava":         "30.1-jre",
+		"hamcrest-core": "1.3",
+		"objenesis":     "3.2",
+	}
+	newModules := func() []models.Module {
+		modules := treeModules("app", "junit", "mockito-core", "guava", "hamcrest-core", "objenesis")
+		for i := range modules {
+			modules[i].Version = versions[modules[i].Name]
+		}
+		return modules
+	}
+
+	modules := newModules()
+	assert.NoError(t, linkDependencies(modules, tree, nil, Options{RecordRequestedVersions: true}))
+
+	hamcrest := findModule(t, modules, "hamcrest-core")
+	assert.Equal(t, "1.3", hamcrest.Version)
+	assert.Equal(t, "1.1", hamcrest.GetProperty(requestedVersionsProperty))
+	assert.Equal(t, "1.1", findModule(t, modules, "mockito-core").Modules["org.hamcrest:hamcrest-core"].GetProperty(requestedVersionsProperty))
+	assert.Equal(t, "29.0-jre", findModule(t, modules, "guava").GetProperty(requestedVersionsProperty))
+	assert.Empty(t, findModule(t, modules, "objenesis").GetProperty(requestedVersionsProperty))
+
+	modules = newModules()
+	assert.NoError(t, linkDependencies(modules, tree, nil, Options{}))
+	assert.Empty(t, findModule(t, modules, "hamcrest-core").GetProperty(requestedVersionsProperty))
+}
+
+func TestPreviewRunsNoSubprocess(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "mvn")
+	defer stubMaven(t, "touch "+marker)()
+
+	preview, err := New().Preview(filepath.Join("testdata", "preview"))
+	assert.NoError(t, err)
+	assert.NoFileExists(t, marker)
+
+	assert.Equal(t, "com.example:preview:2.1.0", preview.Root)
+	// preview, preview-core, guava, internal-tools, maven-compiler-plugin and slf4j-api
+	assert.Equal(t, 6, preview.PackageCount)
+	var steps []string
+	for _, step := range preview.Steps {
+		assert.True(t, step.Run, step.Name)
+		steps = append(steps, step.Name)
+	}
+	assert.Equal(t, []string{"mvn dependency:list", "mvn dependency:tree", "checksums"}, steps)
+
+	preview, err = NewWithOptions(Options{IgnoredGroupIDs: []string{"com.example.*"}, IncludeManagedOnly: true}).Preview(filepath.Join("testdata", "preview"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, preview.PackageCount)
+}
+
+func TestMavenArgsAreForwarded(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	defer stubMaven(t, `echo "$*" >> `+calls)()
+
+	plugin := NewWithOptions(Options{MavenArgs: []string{"-P", "ci", "-Dmaven.test.skip=true"}})
+	_, err := getDependencyList(".", plugin.options)
+	assert.NoError(t, err)
+	_, err = getTransitiveDependencyList(".", plugin.options)
+	assert.Error(t, err, "the stub writes no dependency tree")
+	_, err = plugin.GetVersion()
+	assert.NoError(t, err)
+
+	data, err := ioutil.ReadFile(calls)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "dependency:list -P ci -Dmaven.test.skip=true", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "dependency:tree "), lines[1])
+	assert.True(t, strings.HasSuffix(lines[1], " -P ci -Dmaven.test.skip=true"), lines[1])
+	assert.Equal(t, "-v -P ci -Dmaven.test.skip=true", lines[2])
+}
+
+func TestOfflineMavenInvocations(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	defer stubMaven(t, `echo "$*" >> `+calls+`
+echo "[ERROR] Failed to execute goal on project app: Could not resolve dependencies for project com.example:app:jar:1.0.0:" \
+	"Cannot access central (https://repo.maven.apache.org/maven2) in offline mode and the artifact" \
+	"org.slf4j:slf4j-api:jar:1.7.30 has not been downloaded from it before."
+exit 1`)()
+
+	options := Options{Offline: true, MavenArgs: []string{"-Pci"}}
+	_, err := getDependencyList(".", options)
+	assert.True(t, errors.Is(err, errIncompleteLocalRepository), "%v", err)
+	assert.Contains(t, err.Error(), "org.slf4j:slf4j-api:jar:1.7.30 is not in the local repository")
+	_, err = getTransitiveDependencyList(".", options)
+	assert.True(t, errors.Is(err, errIncompleteLocalRepository), "%v", err)
+	assert.Equal(t, err, linkDependencies(nil, dependencyTree{}, err, options), "the incomplete repository is not worked around")
+
+	_, err = getDependencyList(".", Options{})
+	assert.NoError(t, err, "an online listing is best effort")
+
+	data, err := ioutil.ReadFile(calls)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "-o dependency:list -Pci", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], "-o dependency:tree "), lines[1])
+	assert.Equal(t, "dependency:list", lines[2])
+}
+
+func TestMavenTimeout(t *testing.T) {
+	defer stubMaven(t, "exec sleep 10")()
+
+	options := Options{MavenTimeout: 100 * time.Millisecond}
+	_, err := getDependencyList(".", options)
+	assert.True(t, errors.Is(err, errMavenTimeout), "%v", err)
+	assert.Contains(t, err.Error(), "mvn dependency:list was killed after running for")
+	_, err = getTransitiveDependencyList(".", options)
+	assert.True(t, errors.Is(err, errMavenTimeout), "%v", err)
+	assert.Equal(t, err, linkDependencies(nil, dependencyTree{}, err, options), "a hung mvn is not worked around")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewWithOptions(Options{}).WithContext(ctx).GetVersion()
+	assert.True(t, errors.Is(err, context.Canceled), "%v", err)
+	assert.Contains(t, err.Error(), "mvn -v was killed")
+}
+
+func TestMavenConfig(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	defer stubMaven(t, `echo "$*" >> `+calls)()
+
+	dir := filepath.Join("testdata", "mavenconfig")
+	modules, err := New().ListDeclaredModules(dir)
+	assert.NoError(t, err)
+	guava := findModule(t, modules, "guava")
+	assert.Equal(t, "31.1-jre", guava.Version, "maven.config overrides the pom property")
+	assert.Equal(t, "pkg:maven/com.google.guava/guava@31.1-jre", guava.PackageURL)
+
+	extension := findModule(t, modules, "os-maven-plugin")
+	assert.Equal(t, "1.7.0", extension.Version)
+	assert.Equal(t, "declared in .mvn/extensions.xml", extension.SourceInfo)
+	assert.Contains(t, modules[0].Modules, "kr.motd.maven:os-maven-plugin")
+
+	_, err = getDependencyList(dir, Options{MavenArgs: []string{"-Dmaven.test.skip=true"}})
+	assert.NoError(t, err)
+	data, err := ioutil.ReadFile(calls)
+	assert.NoError(t, err)
+	assert.Equal(t, "dependency:list -Dguava.version=31.1-jre -Pci -Dmaven.test.skip=true", strings.TrimSpace(string(data)))
+}
+
+func TestConflictingMavenArgs(t *testing.T) {
+	for _, args := range [][]string{{"-DoutputFile=deps.txt"}, {"-q"}, {"--file", "other.xml"}, {"clean"}} {
+		_, err := Options{MavenArgs: args}.mavenArgs("-o", "dependency:list")
+		assert.True(t, errors.Is(err, errConflictingMavenArg), "%v", args)
+	}
+}
+
+func TestSubmodulesSharingArtifactID(t *testing.T) {
+	defer stubMaven(t, "true")()
+
+	modules, err := convertPOMReaderToModules(filepath.Join("testdata", "reactor"), true, Options{})
+	assert.NoError(t, err)
+
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	assert.ElementsMatch(t, []string{"reactor", "com.example.api.core", "slf4j-api", "com.example.impl.core", "guava"}, names)
+
+	api := findModule(t, modules, "com.example.api.core")
+	assert.Equal(t, "com.example.api", api.Group)
+	assert.Equal(t, "pkg:maven/com.example.api/core@1.0.0", api.PackageURL)
+	assert.Contains(t, api.Modules, "org.slf4j:slf4j-api")
+	assert.NotContains(t, api.Modules, "com.google.guava:guava")
+
+	impl := findModule(t, modules, "com.example.impl.core")
+	assert.Equal(t, "com.example.impl", impl.Group)
+	assert.Contains(t, impl.Modules, "com.google.guava:guava")
+	assert.NotContains(t, impl.Modules, "org.slf4j:slf4j-api")
+}
+
+func TestScopeFiltersAreForwarded(t *testing.T) {
+	calls := filepath.Join(t.TempDir(), "calls")
+	defer stubMaven(t, `echo "$*" >> `+calls)()
+
+	_, err := getDependencyList(".", Options{IncludeScope: "runtime", ExcludeScope: "provided", MavenArgs: []string{"-Pci"}})
+	assert.NoError(t, err)
+	data, err := ioutil.ReadFile(calls)
+	assert.NoError(t, err)
+	assert.Equal(t, "dependency:list -DincludeScope=runtime -DexcludeScope=provided -Pci", strings.TrimSpace(string(data)))
+
+	_, err = getDependencyList(".", Options{IncludeScope: "import"})
+	assert.True(t, errors.Is(err, errUnsupportedScope))
+}
+
+func TestExcludeUnselectedScopes(t *testing.T) {
+	project, err := readAndLoadPomFile(filepath.Join("testdata", "scopes"))
+	assert.NoError(t, err)
+	scopes := map[string]string{}
+	declaredScopes(scopes, project)
+	dependencies := func(modules []models.Module) []string {
+		var names []string
+		for _, module := range modules {
+			if provenance := module.GetProperty(provenanceProperty); provenance == provenanceDependencies || provenance == provenanceDependencyList {
+				names = append(names, module.Name)
+			}
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	// as listed by mvn dependency:list -DincludeScope=runtime, guava being reached at runtime scope from postgresql
+	options := Options{IncludeScope: "runtime"}
+	dependencyList := []string{
+		"   com.google.guava:failureaccess:jar:1.0.1:compile",
+		"   com.google.guava:guava:jar:30.1-jre:runtime",
+		"   org.postgresql:postgresql:jar:42.2.20:runtime",
+		"",
+		"",
+	}
+	modules := convertDeclaredModules(project, options)
+	modules = append(modules, mergeDependencyList(project, dependencyList, &modules[0], options)...)
+	modules = excludeUnselectedScopes(modules, scopes, dependencyList, options)
+	assert.Equal(t, []string{"failureaccess", "guava", "postgresql"}, dependencies(modules))
+	assert.Contains(t, modules[0].Modules, "org.postgresql:postgresql")
+	assert.NotContains(t, modules[0].Modules, "junit:junit")
+
+	// nothing listed, the declared and managed scopes are relied on
+	modules = excludeUnselectedScopes(convertDeclaredModules(project, options), scopes, nil, options)
+	assert.Equal(t, []string{"guava", "postgresql"}, dependencies(modules))
+
+	// as in the maven-dependency-plugin, excluding a scope excludes the scopes it selects, provided only itself
+	options = Options{ExcludeScope: "provided"}
+	modules = excludeUnselectedScopes(convertDeclaredModules(project, options), scopes, nil, options)
+	assert.Equal(t, []string{"commons-lang3", "guava", "junit", "postgresql"}, dependencies(modules))
+}
+
+func TestContainerScanIncludesProvidedDependencies(t *testing.T) {
+	defer stubMaven(t, "true")()
+	names := func(modules []models.Module) []string {
+		var names []string
+		for _, mod := range modules {
+			names = append(names, mod.Name)
+		}
+		return names
+	}
+
+	// the source scan leaves out what the container provides
+	modules, err := convertPOMReaderToModules(filepath.Join("testdata", "container"), true, Options{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"container", "guava"}, names(modules))
+	assert.NotContains(t, modules[0].Modules, "javax.servlet:javax.servlet-api")
+
+	modules, err = convertPOMReaderToModules(filepath.Join("testdata", "container"), true, Options{ContainerScan: true})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"container", "guava", "javax.servlet-api"}, names(modules))
+	servlet := findModule(t, modules, "javax.servlet-api")
+	assert.Equal(t, providedScope, servlet.Scope)
+	assert.Equal(t, "declared in pom.xml dependencies, provided by the container at runtime", servlet.SourceInfo)
+	assert.Equal(t, providedScope, modules[0].Modules["javax.servlet:javax.servlet-api"].Scope)
+	assert.Empty(t, findModule(t, modules, "guava").Scope)
+}
+
+func TestAggregatorSubject(t *testing.T) {
+	defer stubMaven(t, "true")()
+
+	dir := filepath.Join("testdata", "platform")
+	roots := func(modules []models.Module) []string {
+		var names []string
+		for _, mod := range modules {
+			if mod.Root {
+				names = append(names, mod.Name)
+			}
+		}
+		return names
+	}
+
+	for _, options := range []Options{{}, {Subject: SubjectAggregator, PrimaryModule: "app"}} {
+		modules, err := convertPOMReaderToModules(dir, true, options)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"monorepo"}, roots(modules))
+		assert.Equal(t, "monorepo", modules[0].Name)
+	}
+
+	modules, err := convertPOMReaderToModules(dir, true, Options{Subject: SubjectPrimaryModule, PrimaryModule: "app"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, roots(modules))
+	assert.Equal(t, "app", modules[0].Name)
+	assert.Equal(t, "pkg:maven/com.example/app@2.0.0", modules[0].PackageURL)
+	assert.False(t, findModule(t, modules, "monorepo").Root, "the aggregator is a component")
+
+	// the first module listed by the aggregator is the primary one by default
+	modules, err = convertPOMReaderToModules(dir, true, Options{Subject: SubjectPrimaryModule})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"platform"}, roots(modules))
+	assert.Equal(t, "platform", modules[0].Name)
+}
+
+func TestParseDependencyListOutput(t *testing.T) {
+	output := []byte(`[INFO] Scanning for projects...
+[INFO] --- maven-dependency-plugin:2.8:list (default-cli) @ app ---
+[INFO] The following files have been resolved:
+[INFO]    org.slf4j:slf4j-api:jar:1.7.30:compile
+[INFO]    junit:junit:jar:4.13.2:test
+[INFO]    org.slf4j:slf4j-api:jar:1.7.30:compile
+[INFO]    io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.65.Final:runtime
+[INFO] BUILD SUCCESS
+[INFO] Finished at: 2021-06-10T10:00:00+02:00
+`)
+	assert.Equal(t, []string{
+		"io.netty:netty-transport-native-epoll:jar:linux-x86_64:4.1.65.Final:runtime",
+		"junit:junit:jar:4.13.2:test",
+		"org.slf4j:slf4j-api:jar:1.7.30:compile",
+	}, parseDependencyListOutput(output))
+	assert.Empty(t, parseDependencyListOutput(nil))
+
+	// maven-dependency-plugin 3.2 appends the module name of the artifacts, and marks the optional ones
+	output = []byte(`[INFO] --- maven-dependency-plugin:3.2.0:list (default-cli) @ app ---
+[INFO]    org.slf4j:slf4j-api:jar:1.7.36:compile -- module org.slf4j [auto]
+[INFO]    com.google.code.findbugs:jsr305:jar:3.0.2:compile (optional)
+[WARNING] org.example:legacy:jar:1.0 is relocated
+org.postgresql:postgresql:jar:42.2.20:runtime
+`)
+	assert.Equal(t, []string{
+		"com.google.code.findbugs:jsr305:jar:3.0.2:compile",
+		"org.postgresql:postgresql:jar:42.2.20:runtime",
+		"org.slf4j:slf4j-api:jar:1.7.36:compile",
+	}, parseDependencyListOutput(output))
+}
+
+func TestDependencyListOfFailingBuild(t *testing.T) {
+	defer stubMaven(t, "cat dependency-list.txt; exit 1")()
+
+	options := Options{diagnostics: &models.Diagnostics{}}
+	dependencies, err := getDependencyList(filepath.Join("testdata", "concurrent", "alpha"), options)
+	assert.NoError(t, err)
+	assert.Len(t, dependencies, 3)
+	assert.Contains(t, dependencies, "com.google.guava:guava:jar:30.1-jre:compile")
+
+	// the failure is reported rather than only logged
+	diagnostics := options.diagnostics.List()
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, models.DiagnosticWarning, diagnostics[0].Severity)
+	assert.Equal(t, diagnosticPartialList, diagnostics[0].Code)
+	assert.Contains(t, diagnostics[0].Message, "exit status 1")
+}
+
+func TestReportUnreadableModules(t *testing.T) {
+	outputs := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(outputs, fakeDependencyList), []byte("[INFO] BUILD SUCCESS\n"), 0644))
+	newFakeMaven(t, outputs)
+
+	// the readable modules are listed, the other ones are summarized in a single diagnostic
+	options := Options{diagnostics: &models.Diagnostics{}}
+	modules, err := convertPOMReaderToModules(filepath.Join("testdata", "unreadable"), true, options)
+	assert.NoError(t, err)
+	findModule(t, modules, "app")
+	diagnostics := options.diagnostics.List()
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, diagnosticUnreadableModules, diagnostics[0].Code)
+	assert.Equal(t, "parent", diagnostics[0].Module)
+	assert.Equal(t, "2 of the modules could not be read and are missing from the SBOM: missing, broken", diagnostics[0].Message)
+
+	_, err = readPomFile(filepath.Join("testdata", "unreadable", "broken", "pom.xml"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse "+filepath.Join("testdata", "unreadable", "broken", "pom.xml"))
+	_, err = readPomFile(filepath.Join("testdata", "unreadable", "missing", "pom.xml"))
+	assert.True(t, errors.Is(err, ErrPomNotFound), err)
+}
+
+func TestRecordCoordinates(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+	options := Options{RecordCoordinates: true}
+
+	modules := convertDeclaredModules(project, options)
+	root := modules[0]
+	dependencyList := []string{"   org.example:tools/cli:jar:linux-x86_64:1.0.0:runtime"}
+	modules = append(modules, mergeDependencyList(project, dependencyList, &root, options)...)
+	cli := findModule(t, modules, "cli-linux-x86_64")
+	assert.Equal(t, "org.example:tools/cli:jar:linux-x86_64:1.0.0:runtime", cli.GetProperty(coordinateProperty))
+	assert.Equal(t, "junit:junit:jar:4.13.2", findModule(t, modules, "junit").GetProperty(coordinateProperty))
+
+	mod := createModule(gopom.Dependency{GroupID: "org.example", ArtifactID: "my lib", Type: "test-jar", Version: "2.0"}, project, provenanceDependencies, options)
+	assert.Equal(t, "my-lib-tests", mod.Name)
+	assert.Equal(t, "org.example:my lib:test-jar:2.0", mod.GetProperty(coordinateProperty))
+
+	mod = createModule(gopom.Dependency{GroupID: "org.example", ArtifactID: "my lib", Version: "2.0"}, project, provenanceDependencies, Options{})
+	assert.Empty(t, mod.GetProperty(coordinateProperty))
+}
+
+func TestDeduplicateModules(t *testing.T) {
+	defer stubMaven(t, "true")()
+
+	modules, err := convertPOMReaderToModules(filepath.Join("testdata", "duplicates"), true, Options{LocalRepository: t.TempDir()})
+	assert.NoError(t, err)
+	counts := map[string]int{}
+	for _, mod := range modules {
+		counts[moduleCoordinateKey(mod)]++
+	}
+	// managed and declared by the root, declared by both submodules
+	assert.Equal(t, 1, counts["org.slf4j:slf4j-api:1.7.36"])
+	assert.Equal(t, 1, counts["com.google.guava:guava:31.1-jre"])
+	assert.Same(t, findModule(t, modules, "api").Modules["com.google.guava:guava"], findModule(t, modules, "impl").Modules["com.google.guava:guava"])
+
+	checksum := models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: "abc"}
+	root := models.Module{Name: "app", Root: true, Modules: map[string]*models.Module{}}
+	bare := models.Module{Group: "org.example", Name: "lib", Version: "1.0", Modules: map[string]*models.Module{"dep": {Name: "dep"}}}
+	complete := models.Module{Group: "org.example", Name: "lib", Version: "1.0", CheckSum: &checksum, Modules: map[string]*models.Module{}}
+	other := models.Module{Group: "org.example", Name: "lib", Version: "2.0"}
+	root.Modules["lib"] = &bare
+	root.Modules["other"] = &other
+
+	deduplicated := deduplicateModules([]models.Module{root, bare, complete, other})
+	assert.Len(t, deduplicated, 3)
+	assert.Equal(t, &checksum, deduplicated[1].CheckSum, "the record with a checksum wins")
+	assert.Contains(t, deduplicated[1].Modules, "dep", "the dependencies of the dropped record are kept")
+	assert.Equal(t, &checksum, deduplicated[0].Modules["lib"].CheckSum, "the parent requires the kept record")
+	assert.Equal(t, "2.0", deduplicated[0].Modules["other"].Version)
+}
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// unmarshalPom decodes a pom.xml, or another Maven XML file, stripping a leading UTF-8 byte order mark and transcoding the
+// encodings the XML declaration may name besides UTF-8, e.g. `<?xml version="1.0" encoding="ISO-8859-1"?>`
+func unmarshalPom(data []byte, pom interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	decoder.CharsetReader = charsetReader
+	return decoder.Decode(pom)
+}
+
+// charsetReader returns a reader transcoding the named charset to UTF-8
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		return input, nil
+	case "iso-8859-1", "iso8859-1", "iso_8859-1", "latin1", "l1":
+		return &latin1Reader{input: bufio.NewReader(input)}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", errUnsupportedPomEncoding, charset)
+}
+
+// latin1Reader transcodes ISO-8859-1 to UTF-8, each byte being the code point of the same value
+type latin1Reader struct {
+	input   *bufio.Reader
+	pending []byte
+}
+
+func (r *latin1Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.pending) > 0 {
+			copied := copy(p[n:], r.pending)
+			r.pending = r.pending[copied:]
+			n += copied
+			continue
+		}
+
+		b, err := r.input.ReadByte()
+		if err != nil {
+			if n > 0 && err == io.EOF {
+				return n, nil
+			}
+			return n, err
+		}
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+		buf := make([]byte, utf8.UTFMax)
+		r.pending = buf[:utf8.EncodeRune(buf, rune(b))]
+	}
+	return n, nil
+}
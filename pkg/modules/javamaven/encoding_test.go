@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+)
+
+func TestReadBOMPrefixedPom(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/encoding/bom")
+	assert.NoError(t, err)
+	assert.Equal(t, "bom-prefixed", project.ArtifactID)
+	assert.Equal(t, "Café", project.Name)
+	assert.Len(t, project.Dependencies, 1)
+}
+
+func TestReadLatin1Pom(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/encoding/latin1")
+	assert.NoError(t, err)
+	assert.Equal(t, "latin1", project.ArtifactID)
+	assert.Equal(t, "Café", project.Name)
+	assert.Equal(t, "Müller GmbH", project.Organization.Name)
+	assert.Len(t, project.Dependencies, 1)
+}
+
+func TestUnsupportedPomEncoding(t *testing.T) {
+	var project gopom.Project
+	err := unmarshalPom([]byte(`<?xml version="1.0" encoding="EBCDIC"?><project/>`), &project)
+	assert.True(t, errors.Is(err, errUnsupportedPomEncoding))
+}
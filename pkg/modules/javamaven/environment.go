@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"bufio"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	mavenVersionPrefix = "Apache Maven "
+	javaVersionPrefix  = "Java version:"
+	osNamePrefix       = "OS name:"
+)
+
+// GetBuildEnvironment reports the Maven, JDK and OS versions given by `mvn -v`, followed by whether the build is
+// reproducible and the JDK toolchain it is pinned to. These are read from the project and reported even when mvn
+// cannot be run
+func (m *javamaven) GetBuildEnvironment(path string) ([]models.Property, error) {
+	path, err := m.options.projectPath(path)
+	if err != nil {
+		return nil, err
+	}
+	project := append(reproducibilityEnvironment(filepath.Join(path, m.options.pomFile())), m.options.toolchainEnvironment(path)...)
+	output, err := m.mavenVersion(path)
+	if err != nil {
+		return project, err
+	}
+
+	return append(parseBuildEnvironment(output), project...), nil
+}
+
+// parseBuildEnvironment reads the versions out of the `mvn -v` output, leaving out the local installation paths
+func parseBuildEnvironment(output string) []models.Property {
+	var properties []models.Property
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, mavenVersionPrefix):
+			fields := strings.Fields(strings.TrimPrefix(line, mavenVersionPrefix))
+			if len(fields) > 0 {
+				properties = append(properties, models.Property{Name: "Maven version", Value: fields[0]})
+			}
+		case strings.HasPrefix(line, javaVersionPrefix):
+			attributes := parseVersionAttributes(strings.TrimPrefix(line, javaVersionPrefix))
+			properties = appendAttribute(properties, "Java version", attributes[""])
+			properties = appendAttribute(properties, "Java vendor", attributes["vendor"])
+		case strings.HasPrefix(line, osNamePrefix):
+			attributes := parseVersionAttributes(strings.TrimPrefix(line, osNamePrefix))
+			properties = appendAttribute(properties, "OS name", attributes[""])
+			properties = appendAttribute(properties, "OS version", attributes["version"])
+			properties = appendAttribute(properties, "OS arch", attributes["arch"])
+		}
+	}
+	return properties
+}
+
+// parseVersionAttributes splits a `value, key: value, ...` line, the leading value is keyed by the empty string
+func parseVersionAttributes(line string) map[string]string {
+	attributes := map[string]string{}
+	for i, field := range strings.Split(line, ",") {
+		key, value := "", field
+		if i > 0 {
+			parts := strings.SplitN(field, ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			key, value = strings.TrimSpace(parts[0]), parts[1]
+		}
+		attributes[key] = strings.Trim(strings.TrimSpace(value), "\"")
+	}
+	return attributes
+}
+
+func appendAttribute(properties []models.Property, name, value string) []models.Property {
+	if value == "" {
+		return properties
+	}
+	return append(properties, models.Property{Name: name, Value: value})
+}
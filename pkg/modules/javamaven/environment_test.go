@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const mavenVersionOutput = `Apache Maven 3.8.1 (05c21c65bdfed0f71a2f2ada8b84da59348c4c5d)
+Maven home: /usr/share/maven
+Java version: 11.0.11, vendor: Ubuntu, runtime: /usr/lib/jvm/java-11-openjdk-amd64
+Default locale: en_US, platform encoding: UTF-8
+OS name: "linux", version: "5.4.0-74-generic", arch: "amd64", family: "unix"
+`
+
+// stubMaven puts an `mvn` shell script running the given commands first in PATH, the returned func restores PATH
+func stubMaven(t *testing.T, script string) func() {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub mvn is a shell script")
+	}
+
+	bin := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(bin, "mvn"), []byte("#!/bin/sh\n"+script+"\n"), 0755))
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+	return func() {
+		os.Setenv("PATH", path)
+	}
+}
+
+func TestGetBuildEnvironment(t *testing.T) {
+	defer stubMaven(t, "cat <<'EOF'\n"+mavenVersionOutput+"EOF")()
+
+	environment, err := New().GetBuildEnvironment(".")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Property{
+		{Name: "Maven version", Value: "3.8.1"},
+		{Name: "Java version", Value: "11.0.11"},
+		{Name: "Java vendor", Value: "Ubuntu"},
+		{Name: "OS name", Value: "linux"},
+		{Name: "OS version", Value: "5.4.0-74-generic"},
+		{Name: "OS arch", Value: "amd64"},
+	}, environment)
+}
+
+func TestGetBuildEnvironmentRecordsToolchain(t *testing.T) {
+	defer stubMaven(t, "cat <<'EOF'\n"+mavenVersionOutput+"EOF")()
+
+	plugin := NewWithOptions(Options{ToolchainsFile: filepath.Join("testdata", "toolchains", "toolchains.xml")})
+	environment, err := plugin.GetBuildEnvironment(filepath.Join("testdata", "toolchains"))
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Property{
+		{Name: "Toolchain JDK version", Value: "11.0.11"},
+		{Name: "Toolchain JDK vendor", Value: "temurin"},
+	}, environment[len(environment)-2:])
+
+	// without a toolchains file matching the requirement only mvn -v and the reproducibility are reported
+	plugin = NewWithOptions(Options{ToolchainsFile: filepath.Join("testdata", "toolchains", "missing.xml")})
+	environment, err = plugin.GetBuildEnvironment(filepath.Join("testdata", "toolchains"))
+	assert.NoError(t, err)
+	assert.Len(t, environment, 7)
+	assert.Equal(t, models.Property{Name: "Reproducible build", Value: "false"}, environment[6])
+}
+
+func TestGetBuildEnvironmentRecordsReproducibility(t *testing.T) {
+	defer stubMaven(t, "cat <<'EOF'\n"+mavenVersionOutput+"EOF")()
+
+	environment, err := New().GetBuildEnvironment(filepath.Join("testdata", "reproducible"))
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Property{
+		{Name: "Reproducible build", Value: "true"},
+		{Name: "Build output timestamp", Value: "2023-01-01T00:00:00Z"},
+		{Name: "Reproducible build plugin", Value: "io.github.zlika:reproducible-build-maven-plugin"},
+		{Name: "Reproducible build plugin", Value: "org.apache.maven.plugins:maven-artifact-plugin"},
+	}, environment[6:])
+}
+
+func TestToolchainVersionMatches(t *testing.T) {
+	assert.True(t, versionMatches("11.0.11", "[11,17)"))
+	assert.True(t, versionMatches("17", "[11,17]"))
+	assert.False(t, versionMatches("17", "[11,17)"))
+	assert.False(t, versionMatches("1.8", "[11,)"))
+	assert.True(t, versionMatches("1.8", "1.8"))
+	assert.False(t, versionMatches("1.8.0_292", "1.8"))
+}
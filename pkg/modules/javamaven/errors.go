@@ -10,3 +10,15 @@ type errType error
 
 var errFailedToConvertModules errType = errors.New("failed to convert modules")
 var moduleNotFound errType = errors.New("module not found")
+var errUnsupportedPomEncoding errType = errors.New("unsupported pom.xml encoding")
+var errConflictingMavenArg errType = errors.New("conflicting extra mvn argument")
+var errUnsupportedScope errType = errors.New("unsupported dependency scope")
+var errIncompleteLocalRepository errType = errors.New("offline build with an incomplete local repository")
+var errUnresolvedVersion errType = errors.New("unresolved versions")
+var errUnsupportedVersionPolicy errType = errors.New("unsupported unresolved version policy")
+var errPathOutsideRoot errType = errors.New("path outside the allowed root")
+var errMavenTimeout errType = errors.New("mvn timed out")
+var errMissingFromMirror errType = errors.New("artifact missing from the offline mirror")
+
+// ErrPomNotFound is returned when the project has no pom file, as opposed to a pom file that cannot be parsed
+var ErrPomNotFound errType = errors.New("pom file not found")
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Canned outputs served by the fake mvn, in the outputs directory of a fixture
+const (
+	fakeDependencyList = "dependency-list.txt"
+	fakeDependencyTree = "dependency-tree.dot"
+	fakeVersion        = "version.txt"
+)
+
+// fakeMavenScript answers the goals the decoder runs from the canned outputs: dependency:list prints the list,
+// dependency:tree appends the tree to its -DoutputFile and -v prints the version. A goal without a canned output
+// fails as mvn does when it cannot resolve the project, e.g. offline
+const fakeMavenScript = `echo "$*" >> '%[1]s'
+out=
+for arg in "$@"; do
+	case "$arg" in -DoutputFile=*) out="${arg#-DoutputFile=}" ;; esac
+done
+for arg in "$@"; do
+	case "$arg" in
+	dependency:list) exec cat '%[2]s/` + fakeDependencyList + `' ;;
+	dependency:tree) [ -f '%[2]s/` + fakeDependencyTree + `' ] || exit 1; cat '%[2]s/` + fakeDependencyTree + `' >> "$out"; exit ;;
+	-v) exec cat '%[2]s/` + fakeVersion + `' ;;
+	esac
+done`
+
+// fakeMaven is an mvn put first in PATH for the duration of a test, serving the canned outputs of a fixture so that
+// the decoder runs end to end, deterministically and without Maven
+type fakeMaven struct {
+	calls string
+}
+
+// newFakeMaven serves the canned outputs of a directory, PATH being restored when the test completes
+func newFakeMaven(t *testing.T, outputs string) *fakeMaven {
+	outputs, err := filepath.Abs(outputs)
+	assert.NoError(t, err)
+	calls := filepath.Join(t.TempDir(), "calls")
+	t.Cleanup(stubMaven(t, fmt.Sprintf(fakeMavenScript, calls, outputs)))
+	return &fakeMaven{calls: calls}
+}
+
+// invocations returns the arguments of the mvn invocations, in order
+func (f *fakeMaven) invocations(t *testing.T) []string {
+	data, err := ioutil.ReadFile(f.calls)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	assert.NoError(t, err)
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+// goals returns the goal of each mvn invocation, the first argument not being a flag
+func (f *fakeMaven) goals(t *testing.T) []string {
+	var goals []string
+	for _, invocation := range f.invocations(t) {
+		goal := ""
+		for _, arg := range strings.Fields(invocation) {
+			if !strings.HasPrefix(arg, "-") {
+				goal = arg
+				break
+			}
+		}
+		goals = append(goals, goal)
+	}
+	return goals
+}
+
+func TestFakeMavenListModulesWithDeps(t *testing.T) {
+	dir := filepath.Join("testdata", "fakemvn", "app")
+	mvn := newFakeMaven(t, filepath.Join(dir, "mvn"))
+
+	modules, err := New().ListModulesWithDeps(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dependency:list", "dependency:tree"}, mvn.goals(t))
+
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	assert.ElementsMatch(t, []string{"app", "slf4j-api", "postgresql", "junit", "hamcrest-core"}, names)
+
+	app := findModule(t, modules, "app")
+	assert.True(t, app.Root)
+	// the root also requires the dependencies resolved by dependency:list, transitive ones included
+	assert.ElementsMatch(t, []string{"slf4j-api", "postgresql", "junit", "hamcrest-core"}, moduleNames(app.Modules))
+	assert.Equal(t, "runtime", app.Modules["org.postgresql:postgresql"].Scope)
+	junit := findModule(t, modules, "junit")
+	assert.ElementsMatch(t, []string{"hamcrest-core"}, moduleNames(junit.Modules))
+	assert.Equal(t, "1.3", junit.Modules["org.hamcrest:hamcrest-core"].Version)
+}
+
+func TestFakeMavenWithoutDependencyTree(t *testing.T) {
+	outputs := t.TempDir()
+	fixture := filepath.Join("testdata", "fakemvn", "app")
+	data, err := ioutil.ReadFile(filepath.Join(fixture, "mvn", fakeDependencyList))
+	assert.NoError(t, err)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(outputs, fakeDependencyList), data, 0644))
+	newFakeMaven(t, outputs)
+
+	// the dependencies are attached to the root and the partial graph is reported
+	plugin := New()
+	modules, err := plugin.ListModulesWithDeps(fixture)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"slf4j-api", "postgresql", "junit", "hamcrest-core"}, moduleNames(findModule(t, modules, "app").Modules))
+	diagnostics := plugin.GetDiagnostics()
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, diagnosticPartialTree, diagnostics[0].Code)
+
+	_, err = NewWithOptions(Options{StrictDependencyTree: true}).ListModulesWithDeps(fixture)
+	assert.Error(t, err)
+}
+
+func TestFakeMavenConcurrentDependencyTrees(t *testing.T) {
+	mvn := newFakeMaven(t, filepath.Join("testdata", "fakemvn", "app", "mvn"))
+
+	var wg sync.WaitGroup
+	trees := make([]dependencyTree, 4)
+	errs := make([]error, len(trees))
+	for i := range trees {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			trees[i], errs[i] = getTransitiveDependencyList(".", Options{})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range trees {
+		assert.NoError(t, errs[i])
+		assert.ElementsMatch(t, []string{"org.slf4j:slf4j-api", "org.postgresql:postgresql", "junit:junit"}, trees[i].edges["com.example:app"], "each run reads its own tree")
+	}
+	files := map[string]bool{}
+	for _, invocation := range mvn.invocations(t) {
+		for _, arg := range strings.Fields(invocation) {
+			if strings.HasPrefix(arg, "-DoutputFile=") {
+				files[strings.TrimPrefix(arg, "-DoutputFile=")] = true
+			}
+		}
+	}
+	assert.Len(t, files, len(trees))
+	for file := range files {
+		_, err := os.Stat(file)
+		assert.True(t, os.IsNotExist(err), "%s is removed", file)
+	}
+}
+
+func TestFakeMavenVersion(t *testing.T) {
+	mvn := newFakeMaven(t, filepath.Join("testdata", "fakemvn", "app", "mvn"))
+
+	version, err := NewWithOptions(Options{MavenArgs: []string{"-Pci"}}).GetVersion()
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(version, "Apache Maven 3.8.1"), version)
+	assert.Equal(t, []string{"-v -Pci"}, mvn.invocations(t))
+}
+
+// moduleNames returns the names of the linked modules
+func moduleNames(modules map[string]*models.Module) []string {
+	names := make([]string, 0, len(modules))
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	return names
+}
+
+func TestFakeMavenCustomPomFile(t *testing.T) {
+	dir := filepath.Join("testdata", "pomfile")
+	mvn := newFakeMaven(t, filepath.Join("testdata", "fakemvn", "app", "mvn"))
+
+	modules, err := NewWithOptions(Options{PomFile: "build.xml"}).ListModulesWithDeps(dir)
+	assert.NoError(t, err)
+	assert.True(t, findModule(t, modules, "custom").Root)
+	assert.Equal(t, []string{"dependency:list", "dependency:tree"}, mvn.goals(t))
+	for _, invocation := range mvn.invocations(t) {
+		assert.Contains(t, invocation, "-f build.xml")
+	}
+
+	// without the pom file, the project is reported missing instead of being empty
+	_, err = New().ListModulesWithDeps(dir)
+	assert.True(t, errors.Is(err, ErrPomNotFound), err)
+}
+
+func TestFakeMavenExcludeScopes(t *testing.T) {
+	dir := filepath.Join("testdata", "fakemvn", "app")
+	mvn := newFakeMaven(t, filepath.Join(dir, "mvn"))
+
+	// the test dependencies are left out along with their transitive dependencies, and not filtered by mvn
+	modules, err := NewWithOptions(Options{ExcludeScopes: []string{"test"}}).ListModulesWithDeps(dir)
+	assert.NoError(t, err)
+	for _, invocation := range mvn.invocations(t) {
+		assert.NotContains(t, invocation, "Scope")
+	}
+
+	var names []string
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	assert.ElementsMatch(t, []string{"app", "slf4j-api", "postgresql"}, names)
+	assert.ElementsMatch(t, []string{"slf4j-api", "postgresql"}, moduleNames(findModule(t, modules, "app").Modules))
+	assert.Equal(t, "runtime", findModule(t, modules, "postgresql").Scope)
+
+	_, err = NewWithOptions(Options{ExcludeScopes: []string{"tests"}}).ListModulesWithDeps(dir)
+	assert.True(t, errors.Is(err, errUnsupportedScope), err)
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"path"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// isIgnoredGroup reports whether the groupId matches one of the configured ignore patterns
+func isIgnoredGroup(groupID string, patterns []string) bool {
+	if groupID == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if pattern == groupID {
+			return true
+		}
+		if matched, err := path.Match(pattern, groupID); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// excludeIgnoredGroups removes the modules of ignored groupIds, re-anchoring their dependencies
+// onto the modules that depended on them. The root module is always kept.
+func excludeIgnoredGroups(modules []models.Module, options Options) []models.Module {
+	if len(options.IgnoredGroupIDs) == 0 {
+		return modules
+	}
+
+	ignored := func(module models.Module) bool {
+		return !module.Root && isIgnoredGroup(module.Group, options.IgnoredGroupIDs)
+	}
+	moduleMap := map[string]models.Module{}
+	for _, module := range modules {
+		moduleMap[dependencyKey(module)] = module
+	}
+
+	var reanchor func(deps map[string]*models.Module, children map[string]*models.Module, visited map[string]bool)
+	reanchor = func(deps map[string]*models.Module, children map[string]*models.Module, visited map[string]bool) {
+		for key, child := range children {
+			if !ignored(*child) {
+				deps[key] = child
+				continue
+			}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+			if canonical, ok := moduleMap[key]; ok {
+				reanchor(deps, canonical.Modules, visited)
+			}
+		}
+	}
+
+	filtered := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		if ignored(module) {
+			continue
+		}
+		deps := map[string]*models.Module{}
+		reanchor(deps, module.Modules, map[string]bool{dependencyKey(module): true})
+		module.Modules = deps
+		filtered = append(filtered, module)
+	}
+	return filtered
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestExcludeIgnoredGroups(t *testing.T) {
+	guava := models.Module{Name: "guava", Group: "com.google.guava", Version: "30.1-jre", Modules: map[string]*models.Module{}}
+	core := models.Module{Name: "core", Group: "com.example.internal", Version: "1.0.0", Modules: map[string]*models.Module{"com.google.guava:guava": &guava}}
+	junit := models.Module{Name: "junit", Group: "junit", Version: "4.13.2", Modules: map[string]*models.Module{}}
+	root := models.Module{Name: "app", Group: "com.example", Version: "1.0.0", Root: true, Modules: map[string]*models.Module{"com.example.internal:core": &core, "junit:junit": &junit}}
+
+	modules := excludeIgnoredGroups([]models.Module{root, core, guava, junit}, Options{IgnoredGroupIDs: []string{"com.example.*"}})
+
+	names := []string{}
+	for _, mod := range modules {
+		names = append(names, mod.Name)
+	}
+	assert.Equal(t, []string{"app", "guava", "junit"}, names)
+	assert.NotContains(t, modules[0].Modules, "com.example.internal:core")
+	assert.Contains(t, modules[0].Modules, "com.google.guava:guava")
+	assert.Contains(t, modules[0].Modules, "junit:junit")
+}
+
+func TestIsIgnoredGroup(t *testing.T) {
+	patterns := []string{"com.example", "org.acme.*"}
+	assert.True(t, isIgnoredGroup("com.example", patterns))
+	assert.True(t, isIgnoredGroup("org.acme.tools", patterns))
+	assert.False(t, isIgnoredGroup("com.example.tools", patterns))
+	assert.False(t, isIgnoredGroup("org.apache", patterns))
+	assert.False(t, isIgnoredGroup("", patterns))
+}
@@ -3,9 +3,7 @@
 package javamaven
 
 import (
-	"crypto/sha1"
-	"encoding/hex"
-	"fmt"
+	"context"
 	"log"
 	"os/exec"
 	"path/filepath"
@@ -15,25 +13,40 @@ import (
 )
 
 type javamaven struct {
-	metadata   models.PluginMetadata
-	rootModule *models.Module
-	command    *helper.Cmd
+	metadata    models.PluginMetadata
+	rootModule  *models.Module
+	options     Options
+	diagnostics *models.Diagnostics
 }
 
 // New ...
 func New() *javamaven {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions ...
+func NewWithOptions(options Options) *javamaven {
 	return &javamaven{
 		metadata: models.PluginMetadata{
 			Name:     "Java Maven",
 			Slug:     "Java-Maven",
-			Manifest: []string{"pom.xml"},
+			Manifest: []string{options.pomFile()},
 			// TODO: instead of vendor folder what to mention for java project
 			// Currently checking for mvn executable path in PATH variable
 			ModulePath: []string{"."},
 		},
+		options: options,
 	}
 }
 
+// WithContext returns a copy of the plugin whose mvn invocations are bound to the context, a cancelled context
+// killing the running mvn and failing the generation
+func (m *javamaven) WithContext(ctx context.Context) *javamaven {
+	plugin := *m
+	plugin.options.ctx = ctx
+	return &plugin
+}
+
 // GetMetadata ...
 func (m *javamaven) GetMetadata() models.PluginMetadata {
 	return m.metadata
@@ -82,12 +95,22 @@ func (m *javamaven) HasModulesInstalled(path string) error {
 
 // GetVersion...
 func (m *javamaven) GetVersion() (string, error) {
-	err := m.buildCmd(VersionCmd, ".")
+	return m.mavenVersion(".")
+}
+
+// mavenVersion returns the output of `mvn -v` run in a directory
+func (m *javamaven) mavenVersion(dir string) (string, error) {
+	cmdArgs := VersionCmd.Parse()
+	args, err := m.options.mavenArgs(cmdArgs[1:]...)
 	if err != nil {
 		return "", err
 	}
 
-	return m.command.Output()
+	output, err := m.options.runMaven(dir, args, (*exec.Cmd).Output)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
 }
 
 // GetRootModule...
@@ -106,36 +129,91 @@ func (m *javamaven) GetRootModule(path string) (*models.Module, error) {
 
 // ListUsedModules...
 func (m *javamaven) ListUsedModules(path string) ([]models.Module, error) {
-	modules, err := convertPOMReaderToModules(path, true)
-
+	modules, err := m.listModules(path)
 	if err != nil {
-		log.Println(err)
 		return modules, err
 	}
 
-	return modules, nil
+	return excludeIgnoredGroups(modules, m.options), nil
 }
 
 // ListModulesWithDeps ...
 func (m *javamaven) ListModulesWithDeps(path string) ([]models.Module, error) {
-	modules, err := m.ListUsedModules(path)
+	path, err := m.options.projectPath(path)
+	if err != nil {
+		return nil, err
+	}
+	modules, err := m.listModules(path)
 	if err != nil {
 		return nil, err
 	}
 
-	tdList, err := getTransitiveDependencyList(path)
+	tree, err := getTransitiveDependencyList(path, m.options)
+	if err == nil {
+		if project, pomErr := readProjectPom(path, m.options); pomErr == nil {
+			modules = append(modules, mergeDependencyTree(project, modules, tree, m.runOptions())...)
+		}
+	}
+	if err = linkDependencies(modules, tree, err, m.runOptions()); err != nil {
+		log.Println("error in getting mvn transitive dependency tree and parsing it")
+		return nil, err
+	}
+
+	return excludeIgnoredGroups(modules, m.options), nil
+}
+
+// ListDeclaredModules returns the modules authored in pom.xml, read statically without running mvn
+func (m *javamaven) ListDeclaredModules(path string) ([]models.Module, error) {
+	path, err := m.options.projectPath(path)
+	if err != nil {
+		return nil, err
+	}
+	project, err := readProjectPom(path, m.options)
 	if err != nil {
-		fmt.Println("error in getting mvn transitive dependency tree and parsing it")
 		return nil, err
 	}
 
-	buildDependenciesGraph(modules, tdList)
+	options := m.options.withReactor(path, project)
+	modules := appendExtensionModules(convertDeclaredModules(project, options), path, project, options)
+	return excludeIgnoredGroups(modules, m.options), nil
+}
+
+// ListInventory returns the root module followed by the resolved dependencies, with versions and checksums resolved
+// as configured, but without running `mvn dependency:tree`. The modules are not linked to their transitive dependencies
+func (m *javamaven) ListInventory(path string) ([]models.Module, error) {
+	return m.ListUsedModules(path)
+}
+
+// listModules reads the project modules for a new run, before ignored groups are filtered out
+func (m *javamaven) listModules(path string) ([]models.Module, error) {
+	m.diagnostics = &models.Diagnostics{}
+	modules, err := convertPOMReaderToModules(path, true, m.runOptions())
+
+	if err != nil {
+		log.Println(err)
+		return modules, err
+	}
 
 	return modules, nil
 }
 
+// GetDiagnostics returns the diagnostics reported while listing the modules
+func (m *javamaven) GetDiagnostics() []models.Diagnostic {
+	if m.diagnostics == nil {
+		return nil
+	}
+	return m.diagnostics.List()
+}
+
+// runOptions returns the plugin options wired to the diagnostics of the current run
+func (m *javamaven) runOptions() Options {
+	options := m.options
+	options.diagnostics = m.diagnostics
+	return options
+}
+
 func (m *javamaven) getModule(path string) (models.Module, error) {
-	modules, err := convertPOMReaderToModules(path, false)
+	modules, err := convertPOMReaderToModules(path, false, m.options)
 
 	if err != nil {
 		log.Println(err)
@@ -148,23 +226,3 @@ func (m *javamaven) getModule(path string) (models.Module, error) {
 
 	return modules[0], nil
 }
-
-func (m *javamaven) buildCmd(cmd command, path string) error {
-	cmdArgs := cmd.Parse()
-
-	command := helper.NewCmd(helper.CmdOptions{
-		Name:      cmdArgs[0],
-		Args:      cmdArgs[1:],
-		Directory: path,
-	})
-
-	m.command = command
-
-	return command.Build()
-}
-
-func readCheckSum(content string) string {
-	h := sha1.New()
-	h.Write([]byte(content))
-	return hex.EncodeToString(h.Sum(nil))
-}
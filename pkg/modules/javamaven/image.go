@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	provenanceImage     = "image"
+	imagePathProperty   = "imagePath"
+	layerWhiteoutPrefix = ".wh."
+)
+
+// imageArchiveExtensions are the archives shipped in an image that are listed as modules
+var imageArchiveExtensions = map[string]bool{
+	".jar": true,
+	".war": true,
+	".ear": true,
+}
+
+// ListImageModules returns the modules of the Java archives found in a directory of extracted image layers: a root
+// module named after the directory followed by every jar, war or ear shipped in it, along with the libraries bundled
+// in the wars, ears and Spring Boot jars. Coordinates and checksums are read as in ListArchiveModules. An archive
+// found at several places, e.g. in several layers, is listed once. The whiteout files of the layers are skipped
+func (m *javamaven) ListImageModules(dir string) ([]models.Module, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	root := models.Module{
+		Name:       filepath.Base(abs),
+		Root:       true,
+		LocalPath:  abs,
+		SourceInfo: "extracted image layers " + filepath.Base(abs),
+		Modules:    map[string]*models.Module{},
+	}
+
+	var modules []models.Module
+	seen := map[string]bool{}
+	err = filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || strings.HasPrefix(info.Name(), layerWhiteoutPrefix) ||
+			!imageArchiveExtensions[strings.ToLower(filepath.Ext(file))] {
+			return nil
+		}
+		archive, closer, err := openArchiveFile(file)
+		if err != nil {
+			return err
+		}
+		defer closer.Close()
+		mod, err := readArchiveModule(archive)
+		if err != nil {
+			return err
+		}
+		if seen[mod.CheckSum.Value] {
+			return nil
+		}
+		seen[mod.CheckSum.Value] = true
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		mod.SetProperty(provenanceProperty, provenanceImage)
+		mod.SetProperty(imagePathProperty, rel)
+		mod.SourceInfo = "shipped in the image at " + rel
+
+		libraries, err := readArchiveLibraries(archive, &mod)
+		if err != nil {
+			return err
+		}
+		root.Modules[dependencyKey(mod)] = &mod
+		modules = append(modules, mod)
+		modules = append(modules, libraries...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return excludeIgnoredGroups(append([]models.Module{root}, modules...), m.options), nil
+}
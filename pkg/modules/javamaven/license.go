@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// LicenseUnknown is returned by a LicenseProvider that has no license for the coordinates
+const LicenseUnknown = "unknown"
+
+// LicenseProvider supplies artifact licenses from an external database keyed by coordinates, such as
+// ClearlyDefined data, for the artifacts no license could be detected for locally.
+// It returns an SPDX license expression, or LicenseUnknown.
+type LicenseProvider interface {
+	GetLicense(groupID, artifactID, version string) string
+}
+
+// lookupLicense asks the configured LicenseProvider for the artifact license
+func lookupLicense(file artifact, options Options) (string, bool) {
+	if options.LicenseProvider == nil {
+		return "", false
+	}
+
+	license := strings.TrimSpace(options.LicenseProvider.GetLicense(file.groupID, file.artifactID, file.version))
+	if license == "" || strings.EqualFold(license, LicenseUnknown) {
+		return "", false
+	}
+	return license, true
+}
+
+// updateLicenseInformationToModule concludes the license of a module from the license file found, else from the
+// Bundle-License of its jar. The licenses its pom declares, read lazily as the module pom may have to be read from
+// the artifact repository, are only declared. The LicenseProvider is consulted last
+func updateLicenseInformationToModule(mod *models.Module, file artifact, pomLicenses func() []gopom.License, options Options) {
+	noticeCopyright := helper.GetCopyright(helper.GetNotice("."))
+	licensePkg, err := helper.GetLicenses(".")
+	if err == nil {
+		mod.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
+		mod.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
+		mod.Copyright = helper.MergeCopyright(helper.GetCopyright(licensePkg.ExtractedText), noticeCopyright)
+		mod.CommentsLicense = licensePkg.Comments
+		return
+	}
+
+	mod.Copyright = noticeCopyright
+	jarPath, _ := options.artifactPath(file)
+	if license, ok := readBundleLicense(jarPath); ok {
+		mod.LicenseDeclared = license
+		mod.LicenseConcluded = license
+		return
+	}
+	if license, ok := normalizePomLicenses(pomLicenses()); ok {
+		mod.LicenseDeclared = license
+	}
+	if license, ok := lookupLicense(file, options); ok {
+		if mod.LicenseDeclared == "" {
+			mod.LicenseDeclared = license
+		}
+		mod.LicenseConcluded = license
+	}
+}
+
+// normalizePomLicenses returns the SPDX expression of the licenses of a pom, the name or else the url of each license
+// being normalized to an SPDX identifier. Several licenses are offered to choose from, as Maven assumes. No license
+// is returned unless every listed one is recognized
+func normalizePomLicenses(pomLicenses []gopom.License) (string, bool) {
+	var ids []string
+	for _, license := range pomLicenses {
+		id, ok := licenses.Normalize(strings.TrimSpace(license.Name))
+		if !ok {
+			id, ok = licenses.Normalize(strings.TrimSpace(license.URL))
+		}
+		if !ok {
+			return "", false
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return "", false
+	}
+	return strings.Join(ids, " OR "), true
+}
+
+// readArtifactLicenses returns the licenses declared by the pom of an artifact in the artifact repository, or else
+// inherited from its parents. A pom that cannot be read declares none
+func readArtifactLicenses(file artifact, options Options) []gopom.License {
+	pomFile := artifact{groupID: file.groupID, artifactID: file.artifactID, version: file.version, extension: pomArtifactType}
+	for seen := map[string]bool{}; !seen[pomFile.fileName()]; {
+		seen[pomFile.fileName()] = true
+		path, err := options.artifactPath(pomFile)
+		if err != nil {
+			return nil
+		}
+		project, err := readPomFile(path)
+		if err != nil {
+			return nil
+		}
+		parent, ok := parentArtifact(project)
+		if len(project.Licenses) > 0 || !ok {
+			return project.Licenses
+		}
+		pomFile = parent
+	}
+	return nil
+}
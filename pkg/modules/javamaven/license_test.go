@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/format"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type fakeLicenseProvider map[string]string
+
+func (f fakeLicenseProvider) GetLicense(groupID, artifactID, version string) string {
+	if license, ok := f[groupID+":"+artifactID+":"+version]; ok {
+		return license
+	}
+	return LicenseUnknown
+}
+
+func TestCreateModuleUsesLicenseProvider(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	options := Options{
+		LicenseProvider: fakeLicenseProvider{
+			"com.google.guava:guava:30.1-jre": "Apache-2.0",
+			"junit:junit:4.13.2":              "EPL-1.0 OR EPL-2.0",
+		},
+	}
+
+	mod := createModule(gopom.Dependency{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2"}, project, provenanceDependencies, options)
+	assert.Equal(t, "EPL-1.0 OR EPL-2.0", mod.LicenseConcluded)
+	assert.Equal(t, "EPL-1.0 OR EPL-2.0", mod.LicenseDeclared)
+
+	mod = createModule(gopom.Dependency{GroupID: "junit", ArtifactID: "junit", Version: "4.12"}, project, provenanceDependencies, options)
+	assert.Empty(t, mod.LicenseConcluded)
+
+	mod = createModule(gopom.Dependency{GroupID: "junit", ArtifactID: "junit", Version: "4.13.2"}, project, provenanceDependencies, Options{})
+	assert.Empty(t, mod.LicenseConcluded)
+}
+
+func TestPomDeclaredLicenses(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/pomlicenses")
+	assert.NoError(t, err)
+	options := Options{LocalRepository: "testdata/pomlicenses/repository"}
+
+	root := convertProjectLevelPackageToModule(project, options)
+	assert.Equal(t, "Apache-2.0", root.LicenseDeclared)
+	assert.Empty(t, root.LicenseConcluded)
+
+	declared := func(artifactID string, options Options) models.Module {
+		return createModule(gopom.Dependency{GroupID: "org.example", ArtifactID: artifactID, Version: "1.0.0"}, project, "", options)
+	}
+
+	mod := declared("declared", options)
+	assert.Equal(t, "MIT", mod.LicenseDeclared)
+	assert.Empty(t, mod.LicenseConcluded)
+
+	mod = declared("inherited", options)
+	assert.Equal(t, "Apache-2.0", mod.LicenseDeclared)
+
+	mod = declared("dual", options)
+	assert.Equal(t, "EPL-1.0 OR LGPL-2.1-only", mod.LicenseDeclared)
+
+	mod = declared("unknown", options)
+	assert.Empty(t, mod.LicenseDeclared)
+
+	mod = declared("missing", options)
+	assert.Empty(t, mod.LicenseDeclared)
+
+	options.LicenseProvider = fakeLicenseProvider{"org.example:declared:1.0.0": "MIT OR Apache-2.0"}
+	mod = declared("declared", options)
+	assert.Equal(t, "MIT", mod.LicenseDeclared)
+	assert.Equal(t, "MIT OR Apache-2.0", mod.LicenseConcluded)
+
+	// the declared licenses are written apart from the concluded ones
+	root.Modules[dependencyKey(mod)] = &mod
+	dual := declared("dual", Options{LocalRepository: options.LocalRepository})
+	filename := filepath.Join(t.TempDir(), "bom-Java-Maven.spdx")
+	document, err := format.New(format.Config{
+		Filename:     filename,
+		ToolVersion:  "test",
+		OutputFormat: models.OutputFormatSpdx,
+		GetSource:    func() []models.Module { return []models.Module{root, mod, dual} },
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, document.Render())
+	rendered, err := ioutil.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Contains(t, string(rendered), "PackageLicenseConcluded: NOASSERTION\nPackageLicenseDeclared: Apache-2.0\n")
+	assert.Contains(t, string(rendered), "PackageLicenseConcluded: MIT OR Apache-2.0\nPackageLicenseDeclared: MIT\n")
+	assert.Contains(t, string(rendered), "PackageLicenseConcluded: NOASSERTION\nPackageLicenseDeclared: EPL-1.0 OR LGPL-2.1-only\n")
+}
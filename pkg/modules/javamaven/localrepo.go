@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	repoLocalProperty = "maven.repo.local"
+	mavenOptsVariable = "MAVEN_OPTS"
+	settingsFile      = "settings.xml"
+)
+
+// settingsDescriptor is the part of a Maven settings.xml selecting the local repository
+type settingsDescriptor struct {
+	LocalRepository string `xml:"localRepository"`
+}
+
+// localRepositories returns the local repositories the artifact files are looked up in, in the order Maven selects
+// them: the configured LocalRepository, the `-Dmaven.repo.local` of the mvn arguments or of MAVEN_OPTS, the
+// <localRepository> of the user settings, `~/.m2/settings.xml` unless given with `-s`, then of the global settings
+// of the Maven installation, and `~/.m2/repository`
+func (o Options) localRepositories() []string {
+	var candidates []string
+	add := func(repository string) {
+		repository = strings.TrimSpace(repository)
+		if repository == "" {
+			return
+		}
+		for _, candidate := range candidates {
+			if candidate == repository {
+				return
+			}
+		}
+		candidates = append(candidates, repository)
+	}
+
+	add(o.LocalRepository)
+	add(repoLocalArg(o.MavenArgs))
+	add(repoLocalArg(strings.Fields(os.Getenv(mavenOptsVariable))))
+	add(readSettingsLocalRepository(o.userSettingsFile()))
+	add(readSettingsLocalRepository(o.globalSettingsFile()))
+	add(defaultLocalRepository())
+	return candidates
+}
+
+// repoLocalArg returns the local repository set by a `-Dmaven.repo.local=<path>` argument, the last one winning
+func repoLocalArg(args []string) string {
+	repository := ""
+	for i, arg := range args {
+		if (arg == "-D" || arg == "--define") && i+1 < len(args) {
+			arg = "-D" + args[i+1]
+		}
+		if value := strings.TrimPrefix(arg, "-D"+repoLocalProperty+"="); value != arg {
+			repository = strings.Trim(value, "\"'")
+		}
+	}
+	return repository
+}
+
+// userSettingsFile returns the user settings given to mvn with `-s`, else `~/.m2/settings.xml`
+func (o Options) userSettingsFile() string {
+	if settings := flagValue(o.MavenArgs, "-s", "--settings"); settings != "" {
+		return settings
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", settingsFile)
+}
+
+// globalSettingsFile returns the global settings given to mvn with `-gs`, else the ones of the Maven installation
+func (o Options) globalSettingsFile() string {
+	if settings := flagValue(o.MavenArgs, "-gs", "--global-settings"); settings != "" {
+		return settings
+	}
+	for _, variable := range []string{"MAVEN_HOME", "M2_HOME"} {
+		if home := os.Getenv(variable); home != "" {
+			return filepath.Join(home, "conf", settingsFile)
+		}
+	}
+	return ""
+}
+
+// flagValue returns the value of the last of the flags among the mvn arguments, given as the next argument or
+// joined with `=`
+func flagValue(args []string, flags ...string) string {
+	value := ""
+	for i, arg := range args {
+		for _, flag := range flags {
+			switch {
+			case arg == flag && i+1 < len(args):
+				value = args[i+1]
+			case strings.HasPrefix(arg, flag+"="):
+				value = strings.TrimPrefix(arg, flag+"=")
+			}
+		}
+	}
+	return value
+}
+
+// readSettingsLocalRepository returns the <localRepository> of a settings.xml, with its `${user.home}` and
+// `${env.NAME}` references expanded. A missing or unreadable file sets none
+func readSettingsLocalRepository(path string) string {
+	if path == "" {
+		return ""
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var settings settingsDescriptor
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		return ""
+	}
+	return os.Expand(strings.TrimSpace(settings.LocalRepository), func(name string) string {
+		if name == "user.home" {
+			home, _ := os.UserHomeDir()
+			return home
+		}
+		if strings.HasPrefix(name, "env.") {
+			return os.Getenv(strings.TrimPrefix(name, "env."))
+		}
+		return "${" + name + "}"
+	})
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+)
+
+// setenv sets an environment variable for the duration of a test
+func setenv(t *testing.T, name, value string) {
+	previous, ok := os.LookupEnv(name)
+	os.Setenv(name, value)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(name, previous)
+		} else {
+			os.Unsetenv(name)
+		}
+	})
+}
+
+func TestLocalRepositoryCandidates(t *testing.T) {
+	dir := t.TempDir()
+	settings := filepath.Join(dir, "settings.xml")
+	assert.NoError(t, ioutil.WriteFile(settings, []byte(`<settings>
+  <localRepository>${env.CI_CACHE}/repository</localRepository>
+</settings>`), 0644))
+	setenv(t, "CI_CACHE", "/cache")
+	setenv(t, mavenOptsVariable, "-Xmx1g -Dmaven.repo.local=/opts/repository")
+	setenv(t, "MAVEN_HOME", filepath.Join(dir, "missing"))
+
+	options := Options{MavenArgs: []string{"-s", settings, "-D", "maven.repo.local=/args/repository"}}
+	assert.Equal(t, []string{"/args/repository", "/opts/repository", "/cache/repository", defaultLocalRepository()}, options.localRepositories())
+	assert.Equal(t, "/args/repository", options.localRepository())
+
+	options.LocalRepository = "/configured"
+	assert.Equal(t, "/configured", options.localRepository())
+
+	options = Options{MavenArgs: []string{"--settings=" + settings}}
+	assert.Equal(t, []string{"/opts/repository", "/cache/repository", defaultLocalRepository()}, options.localRepositories())
+}
+
+func TestCheckSumsFromMavenOptsRepository(t *testing.T) {
+	setenv(t, mavenOptsVariable, "-Dmaven.repo.local=testdata/mirror")
+	setenv(t, "MAVEN_HOME", "")
+	setenv(t, "M2_HOME", "")
+
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	// the jar is looked up in each local repository, the other ones missing it
+	options := Options{LocalRepository: t.TempDir()}
+	mod := createModule(gopom.Dependency{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "1.7.36"}, project, provenanceDependencies, options)
+	assert.Equal(t, "5ad6419d5c80605a42fd63387a42202036e37896", mod.CheckSum.Value)
+
+	// a jar found in none of them has no checksum, rather than the one of a missing file
+	mod = createModule(gopom.Dependency{GroupID: "org.slf4j", ArtifactID: "slf4j-api", Version: "0.0.1"}, project, provenanceDependencies, options)
+	assert.Nil(t, mod.CheckSum)
+	assert.Empty(t, mod.AdditionalCheckSums)
+}
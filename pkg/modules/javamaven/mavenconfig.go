@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	mavenConfigDir  = ".mvn"
+	mavenConfigFile = "maven.config"
+	extensionsFile  = "extensions.xml"
+)
+
+// mavenConfig holds the arguments pinned by the .mvn/maven.config of a project
+type mavenConfig struct {
+	// properties are the `-Dname=value` user properties, overriding the ones of the poms as in Maven
+	properties map[string]string
+	// args are the property and profile arguments affecting the resolution, forwarded to the mvn invocations
+	args []string
+}
+
+// extensionsDescriptor is the .mvn/extensions.xml listing the core extensions loaded by Maven
+type extensionsDescriptor struct {
+	Extensions []struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"extension"`
+}
+
+// findMavenConfigDir returns the .mvn directory of the project, found as Maven does in the directory or the
+// closest parent having one, i.e. the root of the multi-module build
+func findMavenConfigDir(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		candidate := filepath.Join(dir, mavenConfigDir)
+		if info, err := os.Stat(candidate); err == nil && info.IsDir() {
+			return candidate, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// readMavenConfig reads the whitespace separated arguments of the .mvn/maven.config of a project. A missing file
+// pins nothing
+func readMavenConfig(dir string) mavenConfig {
+	config := mavenConfig{properties: map[string]string{}}
+	configDir, ok := findMavenConfigDir(dir)
+	if !ok {
+		return config
+	}
+	data, err := ioutil.ReadFile(filepath.Join(configDir, mavenConfigFile))
+	if err != nil {
+		return config
+	}
+
+	args := strings.Fields(string(data))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-D" || arg == "--define" || arg == "-P" || arg == "--activate-profiles":
+			if i+1 >= len(args) {
+				continue
+			}
+			if arg == "-D" || arg == "--define" {
+				config.define(args[i+1])
+			}
+			config.args = append(config.args, arg, args[i+1])
+			i++
+		case strings.HasPrefix(arg, "-D"):
+			config.define(strings.TrimPrefix(arg, "-D"))
+			config.args = append(config.args, arg)
+		case strings.HasPrefix(arg, "-P"):
+			config.args = append(config.args, arg)
+		}
+	}
+	return config
+}
+
+// define records a `name=value` property, a property without a value being true as in Maven
+func (c *mavenConfig) define(property string) {
+	parts := strings.SplitN(strings.Trim(property, "\"'"), "=", 2)
+	if parts[0] == "" {
+		return
+	}
+	if len(parts) == 1 {
+		c.properties[parts[0]] = "true"
+		return
+	}
+	c.properties[parts[0]] = parts[1]
+}
+
+// apply overrides the properties of a project with the ones of maven.config
+func (c mavenConfig) apply(project *gopom.Project) {
+	if len(c.properties) == 0 {
+		return
+	}
+	if project.Properties.Entries == nil {
+		project.Properties.Entries = map[string]string{}
+	}
+	for name, value := range c.properties {
+		project.Properties.Entries[name] = value
+	}
+}
+
+// withMavenConfig returns the options whose mvn arguments are preceded by the ones pinned by maven.config, for the
+// mvn versions not reading it
+func (o Options) withMavenConfig(dir string) Options {
+	if config := readMavenConfig(dir); len(config.args) > 0 {
+		o.MavenArgs = append(append([]string{}, config.args...), o.MavenArgs...)
+	}
+	return o
+}
+
+// appendExtensionModules appends the modules of the core extensions declared in the .mvn/extensions.xml of a
+// project, required by the root module like the build plugins
+func appendExtensionModules(modules []models.Module, dir string, project gopom.Project, options Options) []models.Module {
+	configDir, ok := findMavenConfigDir(dir)
+	if !ok || len(modules) == 0 {
+		return modules
+	}
+	data, err := ioutil.ReadFile(filepath.Join(configDir, extensionsFile))
+	if err != nil {
+		return modules
+	}
+	var descriptor extensionsDescriptor
+	if err := xml.Unmarshal(data, &descriptor); err != nil {
+		return modules
+	}
+
+	for _, extension := range descriptor.Extensions {
+		dep := gopom.Dependency{
+			GroupID:    strings.TrimSpace(extension.GroupID),
+			ArtifactID: strings.TrimSpace(extension.ArtifactID),
+			Version:    strings.TrimSpace(extension.Version),
+		}
+		mod := createModule(dep, project, provenanceExtensions, options)
+		modules = append(modules, mod)
+		modules[0].Modules[dependencyKey(mod)] = &mod
+	}
+	return modules
+}
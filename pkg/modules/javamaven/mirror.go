@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const diagnosticMissingFromMirror = "missing-from-mirror"
+
+// artifactRepository returns the repository the artifact files, BOMs included, are read from: the offline mirror
+// when configured, else the local repository
+func (o Options) artifactRepository() string {
+	if o.OfflineMirror != "" {
+		return o.OfflineMirror
+	}
+	return o.localRepository()
+}
+
+// artifactPath returns the path of an artifact file in the artifact repository, the first of the local
+// repositories holding it without an offline mirror. An offline mirror being expected to hold every artifact of the
+// build, a file absent from it is an error
+func (o Options) artifactPath(file artifact) (string, error) {
+	path := file.localPath(o.artifactRepository())
+	if o.OfflineMirror == "" {
+		for _, repository := range o.localRepositories() {
+			if candidate := file.localPath(repository); helper.Exists(candidate) {
+				return candidate, nil
+			}
+		}
+		return path, nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return path, fmt.Errorf("%w: %s:%s:%s %s is not at %s", errMissingFromMirror, file.groupID, file.artifactID, file.version, file.extension, path)
+	}
+	return path, nil
+}
+
+// reportMissingFromMirror reports, as an error diagnostic, an artifact the offline mirror does not hold. An artifact
+// looked up several times, e.g. a BOM, is reported once
+func (o Options) reportMissingFromMirror(file artifact, err error) {
+	if o.diagnostics == nil {
+		return
+	}
+	diagnostic := models.Diagnostic{
+		Severity: models.DiagnosticError,
+		Code:     diagnosticMissingFromMirror,
+		Module:   artifactModuleName(file.artifactID, file.classifier),
+		Message:  err.Error(),
+	}
+	for _, reported := range o.diagnostics.List() {
+		if reported == diagnostic {
+			return
+		}
+	}
+	o.diagnostics.Add(diagnostic)
+}
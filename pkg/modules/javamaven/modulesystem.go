@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	automaticModuleNameHeader   = "Automatic-Module-Name"
+	bundleSymbolicNameHeader    = "Bundle-SymbolicName"
+	moduleInfoEntry             = "module-info.class"
+	multiReleaseEntryPrefix     = "META-INF/versions/"
+	javaModuleProperty          = "javaModule"
+	automaticModuleNameProperty = "automaticModuleName"
+	bundleSymbolicNameProperty  = "bundleSymbolicName"
+)
+
+// recordModuleSystemNames records the Java module system identifiers of a jar as properties of its module: the
+// module declared by its module-info, at the root or in a versioned directory of a multi-release jar, the
+// Automatic-Module-Name of its manifest and the OSGi Bundle-SymbolicName. A jar may carry several, e.g. an OSGi
+// bundle that is also an automatic module
+func recordModuleSystemNames(mod *models.Module, jarPath string) {
+	archive, err := zip.OpenReader(jarPath)
+	if err != nil {
+		return
+	}
+	defer archive.Close()
+
+	var manifest map[string]string
+	var module string
+	for _, entry := range archive.File {
+		switch {
+		case entry.Name == manifestEntry:
+			if content, err := readZipEntry(entry); err == nil {
+				manifest = parseManifest(content)
+			}
+		case module == "" && isModuleInfoEntry(entry.Name):
+			if content, err := readZipEntry(entry); err == nil {
+				module, _ = readModuleInfoName(content)
+			}
+		}
+	}
+
+	if module != "" {
+		mod.SetProperty(javaModuleProperty, module)
+	}
+	if name := strings.TrimSpace(manifest[automaticModuleNameHeader]); name != "" {
+		mod.SetProperty(automaticModuleNameProperty, name)
+	}
+	// the symbolic name may be followed by directives, e.g. `org.example.core;singleton:=true`
+	if name := strings.TrimSpace(strings.SplitN(manifest[bundleSymbolicNameHeader], ";", 2)[0]); name != "" {
+		mod.SetProperty(bundleSymbolicNameProperty, name)
+	}
+}
+
+// isModuleInfoEntry tells whether a jar entry is the module-info of the jar, `module-info.class` or
+// `META-INF/versions/<version>/module-info.class`
+func isModuleInfoEntry(name string) bool {
+	if name == moduleInfoEntry {
+		return true
+	}
+	return strings.HasPrefix(name, multiReleaseEntryPrefix) && strings.HasSuffix(name, "/"+moduleInfoEntry)
+}
+
+// Constant pool tags of the class file format and the size of the entries that are skipped
+const (
+	constantUtf8   = 1
+	constantLong   = 5
+	constantDouble = 6
+	constantModule = 19
+)
+
+var constantSizes = map[byte]int{3: 4, 4: 4, 5: 8, 6: 8, 7: 2, 8: 2, 9: 4, 10: 4, 11: 4, 12: 4, 15: 3, 16: 2, 17: 4, 18: 4, 20: 2}
+
+// classReader reads the big-endian items of a class file, failing once past its end. The items read past the end
+// are zeroes
+type classReader struct {
+	data []byte
+	ok   bool
+}
+
+func (r *classReader) next(n int) []byte {
+	if !r.ok || n > len(r.data) {
+		r.ok = false
+		return make([]byte, 8)
+	}
+	item := r.data[:n]
+	r.data = r.data[n:]
+	return item
+}
+
+func (r *classReader) u2() int {
+	return int(binary.BigEndian.Uint16(r.next(2)))
+}
+
+func (r *classReader) u4() int {
+	return int(binary.BigEndian.Uint32(r.next(4)))
+}
+
+// skipMembers skips the fields or the methods of a class file along with their attributes
+func (r *classReader) skipMembers() {
+	for count := r.u2(); r.ok && count > 0; count-- {
+		r.next(6)
+		r.skipAttributes()
+	}
+}
+
+func (r *classReader) skipAttributes() {
+	for count := r.u2(); r.ok && count > 0; count-- {
+		r.next(2)
+		r.next(r.u4())
+	}
+}
+
+// readModuleInfoName reads the name of the module a compiled module-info declares, the constant its Module
+// attribute points to
+func readModuleInfoName(class []byte) (string, bool) {
+	r := &classReader{data: class, ok: true}
+	if r.u4() != 0xCAFEBABE {
+		return "", false
+	}
+	r.next(4)
+
+	texts := map[int]string{}
+	modules := map[int]int{}
+	count := r.u2()
+	for i := 1; r.ok && i < count; i++ {
+		tag := r.next(1)[0]
+		switch tag {
+		case constantUtf8:
+			texts[i] = string(r.next(r.u2()))
+		case constantModule:
+			modules[i] = r.u2()
+		default:
+			size, known := constantSizes[tag]
+			if !known {
+				return "", false
+			}
+			r.next(size)
+			// the 8-byte constants take two entries of the pool
+			if tag == constantLong || tag == constantDouble {
+				i++
+			}
+		}
+	}
+
+	// access flags, this and super classes, then the interfaces
+	r.next(6)
+	r.next(2 * r.u2())
+	r.skipMembers()
+	r.skipMembers()
+	for attributes := r.u2(); r.ok && attributes > 0; attributes-- {
+		name := texts[r.u2()]
+		info := r.next(r.u4())
+		if r.ok && name == "Module" && len(info) >= 2 {
+			module, ok := texts[modules[int(binary.BigEndian.Uint16(info))]]
+			return module, ok
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+)
+
+// writeJar writes a jar holding the given entries at its maven layout path under repository
+func writeJar(t *testing.T, repository, artifactID string, entries map[string][]byte) {
+	dir := filepath.Join(repository, "org", "example", artifactID, "1.0.0")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	out, err := os.Create(filepath.Join(dir, artifactID+"-1.0.0.jar"))
+	assert.NoError(t, err)
+	defer out.Close()
+
+	jar := zip.NewWriter(out)
+	for name, content := range entries {
+		entry, err := jar.Create(name)
+		assert.NoError(t, err)
+		_, err = entry.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, jar.Close())
+}
+
+// moduleInfoClass compiles by hand the module-info of a module without directives
+func moduleInfoClass(module string) []byte {
+	var class bytes.Buffer
+	write := func(values ...interface{}) {
+		for _, value := range values {
+			binary.Write(&class, binary.BigEndian, value)
+		}
+	}
+	utf8 := func(text string) {
+		write(uint8(constantUtf8), uint16(len(text)))
+		class.WriteString(text)
+	}
+
+	write(uint32(0xCAFEBABE), uint16(0), uint16(53), uint16(6))
+	utf8("module-info")
+	write(uint8(7), uint16(1))
+	utf8(module)
+	write(uint8(constantModule), uint16(3))
+	utf8("Module")
+	// module flag, this class, no super class, interfaces, fields nor methods
+	write(uint16(0x8000), uint16(2), uint16(0), uint16(0), uint16(0), uint16(0))
+	// the Module attribute: name, flags and version, without requires, exports, opens, uses nor provides
+	write(uint16(1), uint16(5), uint32(16), uint16(4), uint16(0), uint16(0))
+	write(uint16(0), uint16(0), uint16(0), uint16(0), uint16(0))
+	return class.Bytes()
+}
+
+func TestRecordModuleSystemNames(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	repository := t.TempDir()
+	writeJar(t, repository, "dual", map[string][]byte{
+		manifestEntry: []byte("Manifest-Version: 1.0\r\nAutomatic-Module-Name: org.example.dual\r\n" +
+			"Bundle-SymbolicName: org.example.dual.bundle;singleton:=true\r\n\r\n"),
+	})
+	writeJar(t, repository, "named", map[string][]byte{
+		manifestEntry:                            []byte("Manifest-Version: 1.0\r\nMulti-Release: true\r\n\r\n"),
+		"META-INF/versions/11/module-info.class": moduleInfoClass("org.example.named"),
+	})
+	options := Options{LocalRepository: repository}
+
+	dual := createModule(gopom.Dependency{GroupID: "org.example", ArtifactID: "dual", Version: "1.0.0"}, project, provenanceDependencies, options)
+	assert.Equal(t, "org.example.dual", dual.GetProperty(automaticModuleNameProperty))
+	assert.Equal(t, "org.example.dual.bundle", dual.GetProperty(bundleSymbolicNameProperty))
+	assert.Empty(t, dual.GetProperty(javaModuleProperty))
+
+	named := createModule(gopom.Dependency{GroupID: "org.example", ArtifactID: "named", Version: "1.0.0"}, project, provenanceDependencies, options)
+	assert.Equal(t, "org.example.named", named.GetProperty(javaModuleProperty))
+	assert.Empty(t, named.GetProperty(automaticModuleNameProperty))
+	assert.Empty(t, named.GetProperty(bundleSymbolicNameProperty))
+}
+
+func TestReadModuleInfoName(t *testing.T) {
+	class := moduleInfoClass("org.example.core")
+	module, ok := readModuleInfoName(class)
+	assert.True(t, ok)
+	assert.Equal(t, "org.example.core", module)
+
+	for _, corrupt := range [][]byte{nil, class[:4], class[:len(class)-20], []byte("PK\x03\x04 not a class file")} {
+		_, ok := readModuleInfoName(corrupt)
+		assert.False(t, ok)
+	}
+}
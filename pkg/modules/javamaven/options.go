@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Options tunes how the Maven plugin turns a project into modules
+type Options struct {
+	// IncludeManagedOnly keeps dependencyManagement entries that are neither declared
+	// dependencies nor resolved by mvn dependency:list. They are only version constraints
+	// and are excluded by default so the SBOM matches what is actually on the classpath.
+	IncludeManagedOnly bool
+
+	// StrictDependencyTree fails the generation when mvn dependency:tree is unavailable, instead of
+	// degrading to a flat graph where every dependency is attached directly to the root module
+	StrictDependencyTree bool
+
+	// RecordRequestedVersions records, as a property of each module, the versions other than the resolved one
+	// it is requested at along the paths of the verbose dependency tree
+	RecordRequestedVersions bool
+
+	// PomFile is the name of the project pom file read in the project directory and the directories of its modules,
+	// `pom.xml` by default. A custom one is passed to mvn with `-f`
+	PomFile string
+
+	// Offline runs every mvn invocation with `-o`, so that Maven resolves the project from the local repository
+	// alone. A local repository missing artifacts of the project fails the generation, a build is to be run first
+	Offline bool
+
+	// ResolvePaths resolves the directories of the project and its modules to canonical absolute paths, the `..`
+	// elements and symbolic links resolved, before the pom files are read and mvn is run
+	ResolvePaths bool
+
+	// AllowedRoot confines the project and its modules to a directory, e.g. the checkout of a repository, the
+	// directories resolving outside of it, through `..` elements or symbolic links, being rejected. It implies
+	// ResolvePaths
+	AllowedRoot string
+
+	// MavenTimeout bounds each mvn invocation, DefaultMavenTimeout by default, so that a hung Maven, e.g. prompting
+	// for credentials or stuck on a slow mirror, fails the generation instead of freezing it
+	MavenTimeout time.Duration
+
+	// MavenArgs are appended to every mvn invocation, e.g. `-Pci` or `-Dmaven.test.skip=true`. They must be flags
+	// that neither select another project nor change the output read from mvn
+	MavenArgs []string
+
+	// IgnoredGroupIDs lists groupId patterns (e.g. `com.example` or `com.example.*`) of first-party
+	// modules to omit from the SBOM. Their dependencies are re-attached to the module depending on them.
+	IgnoredGroupIDs []string
+
+	// RecordCoordinates records, as the `mavenCoordinate` property of each module, the coordinate it was resolved
+	// from, the entry of mvn dependency:list as Maven printed it or else the dependency as declared in the pom,
+	// so that the packages can be traced back to the build even when their name is sanitized
+	RecordCoordinates bool
+
+	// LocalRepository is the Maven local repository holding the artifact files. By default it is the one Maven is
+	// configured with, by `-Dmaven.repo.local` in MavenArgs or MAVEN_OPTS or by the settings.xml, else
+	// `~/.m2/repository`, the artifact files being looked up in each of them
+	LocalRepository string
+
+	// OfflineMirror is a directory mirroring a remote repository in the standard Maven layout, for air-gapped builds.
+	// When set, the imported BOMs and the artifact files hashed for the checksums are read from it instead of the
+	// local repository, the artifacts it does not hold being reported as errors
+	OfflineMirror string
+
+	// ToolchainsFile lists the toolchains available to the build, `~/.m2/toolchains.xml` by default. The JDK it
+	// offers to the maven-toolchains-plugin requirement of the project is recorded in the build environment
+	ToolchainsFile string
+
+	// ArtifactExtensions maps packaging types to the extension of their artifact file in the repository, e.g.
+	// `nbm` to `nbm`, in addition to the defaults such as `bundle` to `jar`. Unmapped types are their own extension
+	ArtifactExtensions map[string]string
+
+	// ListFatJarContents lists, in the diagnostic warning that the maven-assembly-plugin builds a
+	// jar-with-dependencies, the coordinates of the artifacts packed in the jar when it is built already
+	ListFatJarContents bool
+
+	// LicenseProvider is consulted for the artifacts no license is detected for locally
+	LicenseProvider LicenseProvider
+
+	// ChecksumProvider is consulted for artifact checksums before hashing locally
+	ChecksumProvider ChecksumProvider
+
+	// ChecksumAlgorithms selects the checksums of the modules, the first one being their main checksum. SHA1, SHA256
+	// and SHA512 are computed by default, `SHA1` alone keeping the cost of hashing down on constrained environments
+	ChecksumAlgorithms []models.HashAlgorithm
+
+	// SkipChecksumScopes lists the dependency scopes, e.g. `test` or `provided`, whose artifacts are not hashed.
+	// Their modules have no checksum. Dependencies without a scope are in the compile scope
+	SkipChecksumScopes []string
+
+	// UnresolvedVersions is the policy for the modules whose version is still unresolved once pom.xml,
+	// dependencyManagement and mvn dependency:list are read, e.g. a `${...}` placeholder of an undeclared property:
+	// UnresolvedVersionsOmit, the default, drops them with a diagnostic, UnresolvedVersionsError fails the generation
+	// and UnresolvedVersionsNoAssertion keeps them without version, which SPDX reads as no assertion
+	UnresolvedVersions string
+
+	// IncludeScope and ExcludeScope are forwarded to mvn dependency:list as `-DincludeScope` and `-DexcludeScope`,
+	// e.g. `runtime` or `test`, so Maven selects the dependencies of the scope. The dependencies declared in pom.xml
+	// are filtered alike, their scope being the one resolved by Maven when it lists them
+	IncludeScope string
+	ExcludeScope string
+
+	// ExcludeScopes lists the dependency scopes, e.g. `test` and `provided` for production deployments, whose
+	// dependencies are left out of the SBOM, the transitive ones included. Unlike ExcludeScope, each scope only
+	// excludes itself and the scopes are not forwarded to mvn
+	ExcludeScopes []string
+
+	// ContainerScan includes the provided dependencies, e.g. the servlet API, which are not bundled in the artifact
+	// but are present at runtime in the container it is deployed to. They are in the provided scope and described
+	// as provided by the container. Source scans exclude them unless selected by IncludeScope
+	ContainerScan bool
+
+	// Subject is the module the document of a pom aggregator describes, SubjectAggregator by default, or
+	// SubjectPrimaryModule for the submodule shipping the product, the aggregator and the other submodules being
+	// components then
+	Subject string
+
+	// PrimaryModule is the directory of the primary module as listed in the aggregator <modules>, the first one
+	// listed by default
+	PrimaryModule string
+
+	// ctx is the context the mvn invocations are bound to, see WithContext
+	ctx context.Context
+
+	// reactor holds the modules of the reactor of the project, see withReactor
+	reactor reactorIndex
+
+	// diagnostics collects the diagnostics reported during the current run
+	diagnostics *models.Diagnostics
+}
+
+// pomFile returns the configured pom file name or the default one
+func (o Options) pomFile() string {
+	if pomFile := strings.TrimSpace(o.PomFile); pomFile != "" {
+		return pomFile
+	}
+	return defaultPomFile
+}
+
+// mavenTimeout returns the configured timeout of the mvn invocations or the default one
+func (o Options) mavenTimeout() time.Duration {
+	if o.MavenTimeout > 0 {
+		return o.MavenTimeout
+	}
+	return DefaultMavenTimeout
+}
+
+// context returns the context of the mvn invocations, the background one unless the plugin was given one
+func (o Options) context() context.Context {
+	if o.ctx != nil {
+		return o.ctx
+	}
+	return context.Background()
+}
+
+// pomArgs selects the configured pom file of the mvn invocations, none being needed for the default one
+func (o Options) pomArgs() []string {
+	if o.pomFile() == defaultPomFile {
+		return nil
+	}
+	return []string{"-f", o.pomFile()}
+}
+
+// localRepository returns the local repository Maven uses, see localRepositories
+func (o Options) localRepository() string {
+	if candidates := o.localRepositories(); len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// checksumAlgorithms returns the configured checksum algorithms or the default ones
+func (o Options) checksumAlgorithms() []models.HashAlgorithm {
+	if len(o.ChecksumAlgorithms) > 0 {
+		return o.ChecksumAlgorithms
+	}
+	return defaultChecksumAlgorithms
+}
+
+// skipsChecksum reports whether the artifacts of a dependency scope are not hashed
+func (o Options) skipsChecksum(scope string) bool {
+	scope = strings.TrimSpace(scope)
+	if scope == "" {
+		scope = defaultScope
+	}
+	for _, skipped := range o.SkipChecksumScopes {
+		if strings.EqualFold(strings.TrimSpace(skipped), scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// mavenScopes are the scopes an -DincludeScope or -DexcludeScope value selects, as the maven-dependency-plugin
+// does: runtime selects the compile and runtime scopes, compile the compile, provided and system ones
+var mavenScopes = map[string][]string{
+	"compile":  {"compile", "provided", "system"},
+	"runtime":  {"compile", "runtime"},
+	"test":     {"compile", "provided", "runtime", "system", "test"},
+	"provided": {"provided"},
+	"system":   {"system"},
+}
+
+// filtersScopes reports whether dependencies are selected by their scope
+func (o Options) filtersScopes() bool {
+	return strings.TrimSpace(o.IncludeScope) != "" || strings.TrimSpace(o.ExcludeScope) != "" || len(o.ExcludeScopes) > 0
+}
+
+// selectsScope reports whether the dependencies of a scope are kept by the include and exclude scopes, the provided
+// ones only in container scans unless included, and not excluded by ExcludeScopes
+func (o Options) selectsScope(scope string) bool {
+	scope = strings.TrimSpace(scope)
+	if scope == "" {
+		scope = defaultScope
+	}
+	in := func(filter string) bool {
+		for _, selected := range mavenScopes[strings.TrimSpace(filter)] {
+			if selected == scope {
+				return true
+			}
+		}
+		return false
+	}
+	if strings.TrimSpace(o.IncludeScope) != "" && !in(o.IncludeScope) {
+		return false
+	}
+	if strings.TrimSpace(o.IncludeScope) == "" && scope == providedScope && !o.ContainerScan {
+		return false
+	}
+	for _, excluded := range o.ExcludeScopes {
+		if strings.EqualFold(strings.TrimSpace(excluded), scope) {
+			return false
+		}
+	}
+	return strings.TrimSpace(o.ExcludeScope) == "" || !in(o.ExcludeScope)
+}
+
+// artifactExtension returns the repository file extension of an artifact type, configured ones first
+func (o Options) artifactExtension(artifactType string) string {
+	if extension, ok := o.ArtifactExtensions[artifactType]; ok {
+		return strings.TrimPrefix(extension, ".")
+	}
+	if extension, ok := artifactTypeExtensions[artifactType]; ok {
+		return extension
+	}
+	return artifactType
+}
+
+// report records a diagnostic for the current run, if diagnostics are being collected
+func (o Options) report(severity models.DiagnosticSeverity, code, module, message string) {
+	if o.diagnostics == nil {
+		return
+	}
+	o.diagnostics.Add(models.Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Module:   module,
+		Message:  message,
+	})
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+// projectPath returns the directory of a project or one of its modules as given, or its canonical absolute path,
+// the `..` elements and symbolic links resolved, with Options.ResolvePaths or an allowed root. A canonical path
+// outside the allowed root is rejected
+func (o Options) projectPath(path string) (string, error) {
+	if !o.ResolvePaths && o.AllowedRoot == "" {
+		return path, nil
+	}
+
+	resolved, err := canonicalPath(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if o.AllowedRoot == "" {
+		return resolved, nil
+	}
+
+	root, err := canonicalPath(o.AllowedRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the allowed root %s: %w", o.AllowedRoot, err)
+	}
+	if !isWithin(root, resolved) {
+		return "", fmt.Errorf("%w: %s resolves to %s, outside of %s", errPathOutsideRoot, path, resolved, root)
+	}
+	return resolved, nil
+}
+
+// readModulePom reads the pom of a module of an aggregator, returning its directory. The directory goes through
+// projectPath, so that a module listed as `../elsewhere` or linked to another directory cannot escape the allowed root
+func readModulePom(dir, module string, options Options) (string, gopom.Project, error) {
+	moduleDir, err := options.projectPath(filepath.Join(dir, strings.TrimSpace(module)))
+	if err != nil {
+		return "", gopom.Project{}, err
+	}
+	project, err := readProjectPom(moduleDir, options)
+	return moduleDir, project, err
+}
+
+// canonicalPath returns the absolute path of an existing file, the symbolic links resolved
+func canonicalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// isWithin tells whether a path is the root directory or one of its descendants, both being canonical
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymlinkedProjectDirectory(t *testing.T) {
+	newFakeMaven(t, filepath.Join("testdata", "fakemvn", "app", "mvn"))
+	target, err := filepath.Abs(filepath.Join("testdata", "fakemvn", "app"))
+	assert.NoError(t, err)
+	target, err = filepath.EvalSymlinks(target)
+	assert.NoError(t, err)
+	link := filepath.Join(t.TempDir(), "app")
+	assert.NoError(t, os.Symlink(target, link))
+
+	path, err := Options{ResolvePaths: true}.projectPath(link)
+	assert.NoError(t, err)
+	assert.Equal(t, target, path)
+	path, err = Options{ResolvePaths: true}.projectPath(filepath.Join("testdata", "fakemvn", "app", "..", "app"))
+	assert.NoError(t, err)
+	assert.Equal(t, target, path)
+	path, err = Options{}.projectPath(link)
+	assert.NoError(t, err)
+	assert.Equal(t, link, path, "the path is kept as given by default")
+
+	// the link resolves inside the allowed root, wherever it is
+	modules, err := NewWithOptions(Options{AllowedRoot: "testdata"}).ListModulesWithDeps(link)
+	assert.NoError(t, err)
+	assert.Contains(t, findModule(t, modules, "app").Modules, "org.slf4j:slf4j-api")
+
+	_, err = NewWithOptions(Options{AllowedRoot: filepath.Dir(link)}).ListModulesWithDeps(link)
+	assert.True(t, errors.Is(err, errPathOutsideRoot), "%v", err)
+	_, err = NewWithOptions(Options{AllowedRoot: target}).ListDeclaredModules(filepath.Join(target, ".."))
+	assert.True(t, errors.Is(err, errPathOutsideRoot), "%v", err)
+}
+
+func TestModuleOutsideAllowedRoot(t *testing.T) {
+	defer stubMaven(t, "true")()
+	writePom := func(dir, content string) {
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, defaultPomFile), []byte(content), 0644))
+	}
+	aggregator := func(module string) string {
+		return `<project><groupId>com.example</groupId><artifactId>root</artifactId><version>1.0.0</version>` +
+			`<packaging>pom</packaging><modules><module>` + module + `</module></modules></project>`
+	}
+
+	base := t.TempDir()
+	root := filepath.Join(base, "project", "root")
+	outside := filepath.Join(base, "elsewhere")
+	writePom(outside, `<project><groupId>com.example</groupId><artifactId>outside</artifactId><version>1.0.0</version></project>`)
+	writePom(root, aggregator("../../elsewhere"))
+	options := Options{AllowedRoot: filepath.Join(base, "project")}
+
+	_, err := NewWithOptions(options).ListModulesWithDeps(root)
+	assert.True(t, errors.Is(err, errPathOutsideRoot), "%v", err)
+	_, err = NewWithOptions(options).Preview(root)
+	assert.True(t, errors.Is(err, errPathOutsideRoot), "%v", err)
+
+	// a module linked to a directory outside of the root escapes it as well
+	linked := filepath.Join(base, "project", "linked")
+	writePom(linked, aggregator("module"))
+	assert.NoError(t, os.Symlink(outside, filepath.Join(linked, "module")))
+	_, err = NewWithOptions(options).ListModulesWithDeps(linked)
+	assert.True(t, errors.Is(err, errPathOutsideRoot), "%v", err)
+	_, err = NewWithOptions(options).Preview(linked)
+	assert.True(t, errors.Is(err, errPathOutsideRoot), "%v", err)
+
+	// read from within the root, the module is listed
+	modules, err := NewWithOptions(Options{AllowedRoot: base}).ListModulesWithDeps(linked)
+	assert.NoError(t, err)
+	findModule(t, modules, "outside")
+}
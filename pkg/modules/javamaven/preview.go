@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Preview reads the pom file and the pom files of its modules statically to report the root coordinate, the number of
+// declared packages and the expensive steps a generation would run, without running mvn or hashing files
+func (m *javamaven) Preview(path string) (models.Preview, error) {
+	path, err := m.options.projectPath(path)
+	if err != nil {
+		return models.Preview{}, err
+	}
+	project, err := readProjectPom(path, m.options)
+	if err != nil {
+		return models.Preview{}, err
+	}
+
+	count, err := previewPackageCount(path, project, m.options)
+	if err != nil {
+		return models.Preview{}, err
+	}
+	root := projectArtifact(project, projectVersion(project), m.options)
+	return models.Preview{
+		Root:         strings.Join([]string{root.groupID, root.artifactID, root.version}, ":"),
+		PackageCount: count,
+		Steps:        previewSteps(m.options),
+	}, nil
+}
+
+// previewPackageCount counts the root module, the modules of the project and the unique artifacts they declare.
+// The dependencyManagement entries only constrain versions, they are counted when managed-only modules are included.
+// A module outside of the allowed root fails the count
+func previewPackageCount(path string, project gopom.Project, options Options) (int, error) {
+	count := 1
+	declared := map[string]bool{}
+	declare := func(project gopom.Project, dep gopom.Dependency) {
+		if !isIgnoredGroup(resolveCoordinate(project, dep.GroupID), options.IgnoredGroupIDs) {
+			declared[dependencyModuleName(dep)] = true
+		}
+	}
+	declareProject := func(project gopom.Project) {
+		for _, dep := range project.Dependencies {
+			declare(project, dep)
+		}
+		for _, plugin := range project.Build.Plugins {
+			declare(project, pluginDependency(plugin))
+		}
+		for _, plugin := range project.Build.PluginManagement.Plugins {
+			declare(project, pluginDependency(plugin))
+		}
+	}
+
+	declareProject(project)
+	for _, module := range project.Modules {
+		_, submodule, err := readModulePom(path, module, options)
+		if errors.Is(err, errPathOutsideRoot) {
+			return 0, err
+		}
+		if err != nil {
+			continue
+		}
+		if !isIgnoredGroup(projectArtifact(submodule, "", options).groupID, options.IgnoredGroupIDs) {
+			count++
+		}
+		declareProject(submodule)
+	}
+	if options.IncludeManagedOnly {
+		for _, dep := range project.DependencyManagement.Dependencies {
+			declare(project, dep)
+		}
+	}
+	return count + len(declared), nil
+}
+
+// previewSteps lists the mvn runs and the artifact hashing of a generation with the given options
+func previewSteps(options Options) []models.PreviewStep {
+	list := "mvn dependency:list"
+	if args, err := options.dependencyListArgs(); err == nil {
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "-DincludeScope=") || strings.HasPrefix(arg, "-DexcludeScope=") {
+				list += " " + arg
+			}
+		}
+	}
+	tree := "mvn dependency:tree"
+	if options.RecordRequestedVersions {
+		tree += " -Dverbose"
+	}
+	checksums := fmt.Sprintf("hashes the artifact files of %s", options.artifactRepository())
+	if options.ChecksumProvider != nil {
+		checksums = fmt.Sprintf("asks the checksum provider, hashing the artifact files of %s it does not know", options.artifactRepository())
+	}
+
+	return []models.PreviewStep{
+		{Name: list, Run: true, Detail: "resolves the versions of the declared and transitive dependencies"},
+		{Name: tree, Run: true, Detail: "links the modules to their transitive dependencies"},
+		{Name: "checksums", Run: true, Detail: checksums},
+	}
+}
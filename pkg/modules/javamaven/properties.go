@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+var propertyPlaceholder = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// resolveProperties substitutes the `${...}` placeholders of a pom.xml value with the project properties and
+// coordinates, see resolveProperty
+func resolveProperties(value string, project gopom.Project) string {
+	return resolveProperty(project, value)
+}
+
+// resolveProperty expands the `${...}` placeholders of a pom.xml value until none is left, the properties
+// referencing other properties being expanded in turn, e.g. `${foo.version}` declared as `${bar.version}` itself
+// declared as `${project.version}`. Placeholders that cannot be resolved, or that reference themselves through
+// other properties, are left as is
+func resolveProperty(project gopom.Project, raw string) string {
+	return expandProperties(raw, project, map[string]bool{})
+}
+
+// resolveCoordinate expands the placeholders of a groupId, e.g. `${project.groupId}` for a sibling module or a
+// property shared by the artifacts of a family. A coordinate left with placeholders is empty, so that no package
+// url is built from it rather than a bogus one
+func resolveCoordinate(project gopom.Project, raw string) string {
+	resolved := strings.TrimSpace(resolveProperty(project, strings.TrimSpace(raw)))
+	if strings.Contains(resolved, "${") {
+		return ""
+	}
+	return resolved
+}
+
+// expandProperties expands the placeholders of a value, the properties being expanded tracked so that a cycle
+// ends the expansion
+func expandProperties(value string, project gopom.Project, expanding map[string]bool) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+
+	return propertyPlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := strings.TrimSpace(propertyPlaceholder.FindStringSubmatch(placeholder)[1])
+		resolved, ok := lookupProperty(name, project)
+		if !ok || expanding[name] {
+			return placeholder
+		}
+		expanding[name] = true
+		resolved = expandProperties(resolved, project, expanding)
+		delete(expanding, name)
+		return resolved
+	})
+}
+
+// lookupProperty returns the value of a property, either declared in the project or one of its coordinates
+func lookupProperty(name string, project gopom.Project) (string, bool) {
+	name = strings.TrimSpace(name)
+	if value, ok := project.Properties.Entries[name]; ok {
+		return value, true
+	}
+
+	var field string
+	switch {
+	case strings.HasPrefix(name, "project."):
+		field = strings.TrimPrefix(name, "project.")
+	case strings.HasPrefix(name, "pom."):
+		field = strings.TrimPrefix(name, "pom.")
+	default:
+		return "", false
+	}
+
+	switch field {
+	case "groupId":
+		if project.GroupID != "" {
+			return project.GroupID, true
+		}
+		return project.Parent.GroupID, project.Parent.GroupID != ""
+	case "artifactId":
+		artifactID := projectArtifactID(project)
+		return artifactID, artifactID != ""
+	case "version":
+		if project.Version != "" {
+			return project.Version, true
+		}
+		return project.Parent.Version, project.Parent.Version != ""
+	case "name":
+		return project.Name, project.Name != ""
+	case "url":
+		return project.URL, project.URL != ""
+	}
+	return "", false
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveProperties(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/placeholders")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "https://projects.example.com/placeholders", resolveProperties(project.URL, project))
+	assert.Equal(t, "${missing}/placeholders", resolveProperties("${missing}/${pom.artifactId}", project))
+	assert.Equal(t, "no placeholder", resolveProperties("no placeholder", project))
+}
+
+func TestPlaceholdersInURLAndSupplier(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/placeholders")
+	assert.NoError(t, err)
+
+	mod := convertProjectLevelPackageToModule(project, Options{})
+	assert.Equal(t, "https://projects.example.com/placeholders", mod.PackageHomePage)
+	assert.Equal(t, "https://downloads.example.com/placeholders/1.2.0", mod.PackageDownloadLocation)
+	assert.Equal(t, "Example Maintainers", mod.Supplier.Name)
+	assert.Equal(t, "dev@projects.example.com", mod.Supplier.Email)
+}
+
+func TestProjectCoordinatesInheritedFromParent(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/inherited")
+	assert.NoError(t, err)
+
+	mod := convertProjectLevelPackageToModule(project, Options{})
+	assert.Equal(t, "inherited-parent", mod.Name)
+	assert.Equal(t, "com.example", mod.Group)
+	assert.Equal(t, "3.2.1", mod.Version)
+	assert.Equal(t, "pkg:maven/com.example/inherited-parent@3.2.1", mod.PackageURL)
+	assert.Equal(t, "https://example.com/inherited-parent", mod.PackageHomePage)
+}
+
+func TestResolvePropertyChains(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/chained")
+	assert.NoError(t, err)
+
+	// three levels: core.version, platform.version, project.version, itself ${revision}
+	assert.Equal(t, "2.1.0", resolveProperty(project, "${core.version}"))
+	assert.Equal(t, "1.7.36", resolveProperty(project, "${slf4j.version}"))
+	assert.Equal(t, "chained-2.1.0", resolveProperty(project, "${project.artifactId}-${platform.version}"))
+	// a cycle is left unresolved
+	assert.Equal(t, "${cycle.version}", resolveProperty(project, "${cycle.version}"))
+
+	root := convertProjectLevelPackageToModule(project, Options{})
+	assert.Equal(t, "chained", root.Name)
+	assert.Equal(t, "2.1.0", root.Version)
+
+	modules := convertDeclaredModules(project, Options{})
+	assert.Equal(t, "2.1.0", findModule(t, modules, "core").Version)
+	assert.Equal(t, "1.7.36", findModule(t, modules, "slf4j-api").Version)
+	assert.Empty(t, findModule(t, modules, "cyclic").Version)
+}
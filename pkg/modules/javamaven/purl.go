@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+const (
+	purlType         = "maven"
+	platformProperty = "nativePlatform"
+)
+
+// osClassifierSystems and osClassifierArchitectures are the `${os.detected.name}` and
+// `${os.detected.arch}` values of os-maven-plugin, whose classifiers read `<name>-<arch>`
+var (
+	osClassifierSystems = map[string]bool{
+		"aix": true, "freebsd": true, "hpux": true, "linux": true, "netbsd": true, "openbsd": true,
+		"os400": true, "osx": true, "qnx": true, "sunos": true, "windows": true, "zos": true,
+	}
+	osClassifierArchitectures = map[string]bool{
+		"aarch_32": true, "aarch_64": true, "arm_32": true, "ia64_32": true, "ia64_64": true,
+		"itanium_32": true, "itanium_64": true, "loongarch_64": true, "mips_32": true, "mips_64": true,
+		"mipsel_32": true, "mipsel_64": true, "ppc_32": true, "ppc_64": true, "ppcle_32": true,
+		"ppcle_64": true, "riscv": true, "riscv64": true, "s390_32": true, "s390_64": true,
+		"sparc_32": true, "sparc_64": true, "x86_32": true, "x86_64": true,
+	}
+)
+
+// platformClassifier returns the platform of an os classified native artifact, e.g. `linux-x86_64`.
+// Classifiers carrying more than the platform, such as `linux-x86_64-fedora`, are recognized by their prefix.
+func platformClassifier(classifier string) (string, bool) {
+	parts := strings.SplitN(strings.ToLower(classifier), "-", 3)
+	if len(parts) < 2 || !osClassifierSystems[parts[0]] || !osClassifierArchitectures[parts[1]] {
+		return "", false
+	}
+	return parts[0] + "-" + parts[1], true
+}
+
+// mavenPurl builds the Maven package urls, the groupId being the namespace
+var mavenPurl = purl.Builder{Type: purlType, Qualifiers: []string{"classifier", "type"}}
+
+// buildPurl returns the package url of an artifact, see https://github.com/package-url/purl-spec.
+// The classifier, carrying the platform of native artifacts, and the type other than jar are qualifiers.
+func buildPurl(file artifact, artifactType string) string {
+	if file.groupID == "" || file.artifactID == "" {
+		return ""
+	}
+
+	qualifiers := map[string]string{"classifier": file.classifier}
+	if artifactType != defaultArtifactType {
+		qualifiers["type"] = artifactType
+	}
+	return mavenPurl.Build(file.groupID, file.artifactID, file.version, qualifiers)
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlatformClassifier(t *testing.T) {
+	for classifier, expected := range map[string]string{
+		"linux-x86_64":        "linux-x86_64",
+		"osx-aarch_64":        "osx-aarch_64",
+		"linux-x86_64-fedora": "linux-x86_64",
+		"windows-x86_32":      "windows-x86_32",
+	} {
+		platform, ok := platformClassifier(classifier)
+		assert.True(t, ok, classifier)
+		assert.Equal(t, expected, platform)
+	}
+
+	for _, classifier := range []string{"", "tests", "sources", "jdk15", "linux"} {
+		_, ok := platformClassifier(classifier)
+		assert.False(t, ok, classifier)
+	}
+}
+
+func TestNativeDependencyPurl(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/native")
+	assert.NoError(t, err)
+
+	modules := convertDeclaredModules(project, Options{})
+	assert.Equal(t, "pkg:maven/com.example/native@1.0.0", modules[0].PackageURL)
+
+	epoll := findModule(t, modules, "netty-transport-native-epoll-linux-x86_64")
+	assert.Equal(t, "pkg:maven/io.netty/netty-transport-native-epoll@4.1.65.Final?classifier=linux-x86_64", epoll.PackageURL)
+	assert.Equal(t, "linux-x86_64", epoll.GetProperty(platformProperty))
+
+	codec := findModule(t, modules, "netty-codec")
+	assert.Equal(t, "pkg:maven/io.netty/netty-codec@4.1.65.Final", codec.PackageURL)
+	assert.Empty(t, codec.GetProperty(platformProperty))
+}
+
+func TestBuildPurlQualifiers(t *testing.T) {
+	file := artifact{groupID: "com.example", artifactID: "core", version: "1.0.0", classifier: "tests", extension: "jar"}
+	assert.Equal(t, "pkg:maven/com.example/core@1.0.0?classifier=tests&type=test-jar", buildPurl(file, testJarType))
+	assert.Equal(t, "", buildPurl(artifact{artifactID: "core"}, ""))
+}
+
+func TestPurlOfPlaceholderGroupID(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/groupplaceholders")
+	assert.NoError(t, err)
+
+	modules := convertDeclaredModules(project, Options{})
+	assert.Equal(t, "pkg:maven/com.example/service@1.0.0", modules[0].PackageURL)
+
+	api := findModule(t, modules, "api")
+	assert.Equal(t, "com.example", api.Group)
+	assert.Equal(t, "pkg:maven/com.example/api@1.0.0", api.PackageURL)
+
+	databind := findModule(t, modules, "jackson-databind")
+	assert.Equal(t, "com.fasterxml.jackson.core", databind.Group)
+	assert.Equal(t, "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.12.3", databind.PackageURL)
+
+	// an unresolved groupId makes no package url
+	mystery := findModule(t, modules, "mystery")
+	assert.Empty(t, mystery.Group)
+	assert.Empty(t, mystery.PackageURL)
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"path/filepath"
+
+	"github.com/vifraa/gopom"
+)
+
+// reactorIndex holds the poms of the modules of a reactor build by their `groupId:artifactId:version`, so that a
+// BOM imported from a sibling module, i.e. a version platform not installed in the local repository yet, is read
+// from its sources as Maven does within the reactor
+type reactorIndex map[string]gopom.Project
+
+// withReactor returns the options resolving the BOMs imported from the modules of the reactor of the project, and
+// the parents the project and its modules inherit from, the project itself and the parents found at their relative
+// path being indexed along with the modules
+func (o Options) withReactor(dir string, project gopom.Project) Options {
+	reactor := reactorIndex{}
+	reactor.addParents(dir, project)
+	reactor.index(project)
+	reactor.add(dir, project, o, map[string]bool{})
+	o.reactor = reactor
+	return o
+}
+
+// index indexes the pom of a project by the artifact it builds
+func (r reactorIndex) index(project gopom.Project) {
+	file := projectArtifact(project, projectVersion(project), Options{})
+	r[file.groupID+":"+file.artifactID+":"+file.version] = project
+}
+
+// addParents indexes the parents of the project found at their relative path, up the chain of parents. The
+// directories being read are tracked so that a parent listed as its own ancestor ends the walk
+func (r reactorIndex) addParents(dir string, project gopom.Project) {
+	reading := map[string]bool{}
+	for {
+		parentDir, parent, ok := readRelativeParent(dir, project)
+		if !ok || reading[parentDir] {
+			return
+		}
+		reading[parentDir] = true
+		r.index(parent)
+		dir, project = parentDir, parent
+	}
+}
+
+// add indexes the modules of the project, recursively for the aggregators among them. The directories being read
+// are tracked so that a module listing one of its parents ends the walk
+func (r reactorIndex) add(dir string, project gopom.Project, o Options, reading map[string]bool) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	if reading[dir] {
+		return
+	}
+	reading[dir] = true
+
+	for _, module := range project.Modules {
+		moduleDir, submodule, err := readModulePom(dir, module, o)
+		if err != nil {
+			continue
+		}
+		r.index(submodule)
+		r.add(moduleDir, submodule, o, reading)
+	}
+}
+
+// lookup returns the pom of the reactor module built as the artifact
+func (r reactorIndex) lookup(file artifact) (gopom.Project, bool) {
+	project, ok := r[file.groupID+":"+file.artifactID+":"+file.version]
+	return project, ok
+}
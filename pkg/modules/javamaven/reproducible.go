@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// outputTimestampProperty pins the timestamp of the archive entries, Maven builds are reproducible when it is set
+const outputTimestampProperty = "project.build.outputTimestamp"
+
+// reproducibleBuildPlugins are the plugins normalizing or verifying the build output, keyed by artifactId
+var reproducibleBuildPlugins = map[string]string{
+	"reproducible-build-maven-plugin": "io.github.zlika",
+	"maven-artifact-plugin":           "org.apache.maven.plugins",
+}
+
+// reproducibilityEnvironment tells whether the project pom file configures a reproducible build, i.e. sets
+// project.build.outputTimestamp, along with the timestamp and the reproducible-build plugins it configures.
+// Nothing is reported when the pom cannot be read
+func reproducibilityEnvironment(pomPath string) []models.Property {
+	project, err := readPomFile(pomPath)
+	if err != nil {
+		return nil
+	}
+
+	timestamp := resolveProperties(strings.TrimSpace(project.Properties.Entries[outputTimestampProperty]), project)
+	properties := []models.Property{{Name: "Reproducible build", Value: strconv.FormatBool(timestamp != "")}}
+	properties = appendAttribute(properties, "Build output timestamp", timestamp)
+
+	plugins := append([]gopom.Plugin{}, project.Build.Plugins...)
+	plugins = append(plugins, project.Build.PluginManagement.Plugins...)
+	seen := map[string]bool{}
+	for _, plugin := range plugins {
+		artifactID := strings.TrimSpace(plugin.ArtifactID)
+		groupID, ok := reproducibleBuildPlugins[artifactID]
+		if !ok || seen[artifactID] {
+			continue
+		}
+		seen[artifactID] = true
+		if declared := strings.TrimSpace(plugin.GroupID); declared != "" {
+			groupID = declared
+		}
+		properties = append(properties, models.Property{Name: "Reproducible build plugin", Value: groupID + ":" + artifactID})
+	}
+	return properties
+}
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	diagnosticMissingMaven     = "missing-mvn"
+	diagnosticUnreadablePom    = "unreadable-pom"
+	diagnosticUnresolvedParent = "unresolved-parent"
+	diagnosticUnresolvedBOM    = "unresolved-bom"
+	defaultParentRelativePath  = "../" + defaultPomFile
+)
+
+// CanResolve checks, before a generation and without running mvn, that the project can be fully resolved: mvn is
+// in PATH, the poms of the project and its modules are readable, their parents and imported BOMs are found in the
+// reactor, at the parent relative path or in the artifact repository, and with an offline mirror the mirror holds
+// the artifacts they declare. Each gap is returned as a diagnostic, the project being resolvable without errors
+func CanResolve(path string, options Options) (bool, []models.Diagnostic) {
+	options.diagnostics = &models.Diagnostics{}
+	if _, err := exec.LookPath("mvn"); err != nil {
+		options.report(models.DiagnosticError, diagnosticMissingMaven, "",
+			"mvn is not in PATH, install Maven or add its bin directory to PATH")
+	}
+
+	path, err := options.projectPath(path)
+	if err != nil {
+		options.report(models.DiagnosticError, diagnosticUnreadablePom, "", err.Error())
+		return false, options.diagnostics.List()
+	}
+	project, err := readProjectPom(path, options)
+	if err != nil {
+		options.report(models.DiagnosticError, diagnosticUnreadablePom, "", err.Error())
+	} else {
+		options = options.withReactor(path, project)
+		checkResolvable(path, project, options, map[string]bool{})
+	}
+
+	diagnostics := options.diagnostics.List()
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == models.DiagnosticError {
+			return false, diagnostics
+		}
+	}
+	return true, diagnostics
+}
+
+// checkResolvable reports the gaps of a project and then of its modules, recursively for the aggregators among
+// them. The directories being checked are tracked so that a module listing one of its parents ends the walk
+func checkResolvable(dir string, project gopom.Project, options Options, checking map[string]bool) {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	if checking[dir] {
+		return
+	}
+	checking[dir] = true
+
+	module := artifactModuleName(projectArtifactID(project), "")
+	checkParent(dir, project, module, options)
+	checkBOMs(project, module, options, map[string]bool{})
+	if options.OfflineMirror != "" {
+		checkMirroredDependencies(project, options)
+	}
+
+	for _, name := range project.Modules {
+		moduleDir, submodule, err := readModulePom(dir, name, options)
+		if err != nil {
+			options.report(models.DiagnosticError, diagnosticUnreadablePom, strings.TrimSpace(name), err.Error())
+			continue
+		}
+		checkResolvable(moduleDir, submodule, options, checking)
+	}
+}
+
+// checkParent reports a parent pom that is neither at its relative path, `../pom.xml` by default, nor a module of
+// the reactor, nor in the artifact repository
+func checkParent(dir string, project gopom.Project, module string, options Options) {
+	file, ok := parentArtifact(project)
+	if !ok {
+		return
+	}
+	if _, _, ok := readRelativeParent(dir, project); ok {
+		return
+	}
+	if _, ok := options.reactor.lookup(file); ok || inArtifactRepository(file, options) {
+		return
+	}
+
+	options.report(models.DiagnosticError, diagnosticUnresolvedParent, module,
+		fmt.Sprintf("parent %s:%s:%s is neither at %s nor in %s, install it or add it to the repository",
+			file.groupID, file.artifactID, file.version, parentRelativePath(dir, project), options.artifactRepository()))
+}
+
+// parentRelativePath returns the pom file at the relative path of the parent of a project, `../pom.xml` by default
+func parentRelativePath(dir string, project gopom.Project) string {
+	relativePath := strings.TrimSpace(project.Parent.RelativePath)
+	if relativePath == "" {
+		relativePath = defaultParentRelativePath
+	}
+	relativePath = filepath.Join(dir, relativePath)
+	if info, err := os.Stat(relativePath); err == nil && info.IsDir() {
+		relativePath = filepath.Join(relativePath, defaultPomFile)
+	}
+	return relativePath
+}
+
+// readRelativeParent reads the parent of a project at its relative path, returning its directory. The pom found
+// there is the parent when it is the artifact the project names as its parent, an unrelated aggregator is not
+func readRelativeParent(dir string, project gopom.Project) (string, gopom.Project, bool) {
+	file, ok := parentArtifact(project)
+	if !ok {
+		return "", gopom.Project{}, false
+	}
+	path := parentRelativePath(dir, project)
+	candidate, err := readPomFile(path)
+	if err != nil {
+		return "", gopom.Project{}, false
+	}
+	found := projectArtifact(candidate, "", Options{})
+	if found.groupID != file.groupID || found.artifactID != file.artifactID {
+		return "", gopom.Project{}, false
+	}
+	return filepath.Dir(path), candidate, true
+}
+
+// readParentPom returns the parent of a project, read from the reactor, where the parents at their relative path are
+// indexed, or else from the artifact repository
+func readParentPom(project gopom.Project, options Options) (gopom.Project, bool) {
+	file, ok := parentArtifact(project)
+	if !ok {
+		return gopom.Project{}, false
+	}
+	if parent, ok := options.reactor.lookup(file); ok {
+		return parent, true
+	}
+	path, err := options.artifactPath(file)
+	if err != nil {
+		return gopom.Project{}, false
+	}
+	parent, err := readPomFile(path)
+	return parent, err == nil
+}
+
+// checkBOMs reports the BOMs imported by the dependencyManagement of a project, and by the BOMs it imports in turn,
+// that are neither modules of the reactor nor in the artifact repository
+func checkBOMs(project gopom.Project, module string, options Options, importing map[string]bool) {
+	for _, managed := range project.DependencyManagement.Dependencies {
+		if !isBOMImport(managed) {
+			continue
+		}
+		bom := newArtifact(managed, resolveProperties(managed.Version, project), options)
+		coordinates := bom.groupID + ":" + bom.artifactID + ":" + bom.version
+		if importing[coordinates] {
+			continue
+		}
+
+		imported, ok := options.reactor.lookup(bom)
+		if !ok {
+			path, err := options.artifactPath(bom)
+			if err == nil {
+				imported, err = readPomFile(path)
+			}
+			if errors.Is(err, errMissingFromMirror) || errors.Is(err, ErrPomNotFound) {
+				options.report(models.DiagnosticError, diagnosticUnresolvedBOM, module,
+					fmt.Sprintf("imported BOM %s is not in %s, install it or add it to the repository", coordinates, options.artifactRepository()))
+				continue
+			}
+			if err != nil {
+				options.report(models.DiagnosticError, diagnosticUnresolvedBOM, module, err.Error())
+				continue
+			}
+		}
+
+		importing[coordinates] = true
+		checkBOMs(imported, module, options, importing)
+		delete(importing, coordinates)
+	}
+}
+
+// checkMirroredDependencies reports the declared dependencies and build plugins the offline mirror does not hold,
+// the ones whose version is unknown until mvn resolves it being skipped
+func checkMirroredDependencies(project gopom.Project, options Options) {
+	deps := append([]gopom.Dependency{}, project.Dependencies...)
+	for _, plugin := range project.Build.Plugins {
+		deps = append(deps, pluginDependency(plugin))
+	}
+	// the BOMs missing from the mirror are reported by checkBOMs already
+	lookup := options
+	lookup.diagnostics = nil
+	for _, dep := range deps {
+		version := resolvePropertyVersion(strings.TrimSpace(dep.Version), project)
+		if version == "" {
+			version = resolveManagedVersion(resolveCoordinate(project, dep.GroupID), strings.TrimSpace(dep.ArtifactID), project, lookup)
+		}
+		if version == "" {
+			continue
+		}
+		file := newArtifact(dep, version, options)
+		if _, err := options.artifactPath(file); err != nil {
+			options.reportMissingFromMirror(file, err)
+		}
+	}
+}
+
+// inArtifactRepository tells whether the artifact file is in the artifact repository
+func inArtifactRepository(file artifact, options Options) bool {
+	path, err := options.artifactPath(file)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestCanResolveReportsOfflineGaps(t *testing.T) {
+	defer stubMaven(t, "exit 0")()
+
+	dir := filepath.Join("testdata", "resolve", "app")
+	resolvable, diagnostics := CanResolve(dir, Options{OfflineMirror: filepath.Join("testdata", "mirror")})
+	assert.False(t, resolvable)
+
+	codes := map[string]models.Diagnostic{}
+	for _, diagnostic := range diagnostics {
+		assert.Equal(t, models.DiagnosticError, diagnostic.Severity)
+		codes[diagnostic.Code+" "+diagnostic.Module] = diagnostic
+	}
+	assert.Len(t, codes, 3)
+	assert.Contains(t, codes[diagnosticUnresolvedParent+" app"].Message, "parent com.example:corporate-parent:3.1.0 is neither at")
+	assert.Contains(t, codes[diagnosticUnresolvedBOM+" app"].Message, "imported BOM com.example:platform-bom:2.0.0 is not in testdata/mirror")
+	assert.Contains(t, codes[diagnosticMissingFromMirror+" junit"].Message, "junit:junit:4.13.2 jar is not at")
+	// slf4j-api is in the mirror
+	assert.NotContains(t, codes, diagnosticMissingFromMirror+" slf4j-api")
+}
+
+func TestCanResolveWithoutMaven(t *testing.T) {
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", t.TempDir())
+	defer os.Setenv("PATH", path)
+
+	resolvable, diagnostics := CanResolve(filepath.Join("testdata", "fakemvn", "app"), Options{})
+	assert.False(t, resolvable)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, diagnosticMissingMaven, diagnostics[0].Code)
+
+	defer stubMaven(t, "exit 0")()
+	resolvable, diagnostics = CanResolve(filepath.Join("testdata", "fakemvn", "app"), Options{})
+	assert.True(t, resolvable)
+	assert.Empty(t, diagnostics)
+}
+
+func TestCanResolveOutsideAllowedRoot(t *testing.T) {
+	defer stubMaven(t, "exit 0")()
+
+	dir := filepath.Join("testdata", "fakemvn", "app")
+	resolvable, diagnostics := CanResolve(dir, Options{AllowedRoot: t.TempDir()})
+	assert.False(t, resolvable)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, diagnosticUnreadablePom, diagnostics[0].Code)
+	assert.Contains(t, diagnostics[0].Message, errPathOutsideRoot.Error())
+
+	// a symlinked project directory is resolved to the project it links
+	link := filepath.Join(t.TempDir(), "app")
+	target, err := filepath.Abs(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Symlink(target, link))
+	resolvable, diagnostics = CanResolve(link, Options{AllowedRoot: "testdata"})
+	assert.True(t, resolvable)
+	assert.Empty(t, diagnostics)
+}
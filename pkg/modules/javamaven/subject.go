@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// Subjects of the document of a pom aggregator, see Options.Subject
+const (
+	SubjectAggregator    = "aggregator"
+	SubjectPrimaryModule = "primary-module"
+)
+
+// aggregatorPackaging is the packaging of the poms building no artifact, e.g. the aggregators of a reactor
+const aggregatorPackaging = "pom"
+
+// selectSubject makes the primary module of an aggregator the root module, the one the document describes, when the
+// options select it as the subject. The aggregator is then one of the components, listed after it
+func selectSubject(modules []models.Module, dir string, project gopom.Project, options Options) []models.Module {
+	if options.Subject != SubjectPrimaryModule || strings.TrimSpace(project.Packaging) != aggregatorPackaging {
+		return modules
+	}
+	moduleDir := strings.TrimSpace(options.PrimaryModule)
+	if moduleDir == "" && len(project.Modules) > 0 {
+		moduleDir = strings.TrimSpace(project.Modules[0])
+	}
+	if moduleDir == "" {
+		return modules
+	}
+	_, primary, err := readModulePom(dir, moduleDir, options)
+	if err != nil {
+		return modules
+	}
+
+	purl := buildPurl(projectArtifact(primary, projectVersion(primary), options), primary.Packaging)
+	root, subject := -1, -1
+	for i := range modules {
+		switch {
+		case modules[i].Root:
+			root = i
+		case modules[i].SourceInfo == projectSourceInfo && modules[i].PackageURL == purl:
+			subject = i
+		}
+	}
+	if root < 0 || subject < 0 {
+		return modules
+	}
+
+	modules[root].Root = false
+	modules[subject].Root = true
+	selected := make([]models.Module, 0, len(modules))
+	selected = append(selected, modules[subject])
+	for i := range modules {
+		if i != subject {
+			selected = append(selected, modules[i])
+		}
+	}
+	return selected
+}
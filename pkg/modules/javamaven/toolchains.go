@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	toolchainsPluginArtifactID = "maven-toolchains-plugin"
+	jdkToolchainType           = "jdk"
+)
+
+// toolchainValues are the free form `<key>value</key>` elements of a toolchain requirement or offer
+type toolchainValues struct {
+	Entries []toolchainValue `xml:",any"`
+}
+
+type toolchainValue struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+func (v toolchainValues) toMap() map[string]string {
+	values := map[string]string{}
+	for _, entry := range v.Entries {
+		values[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+	return values
+}
+
+// toolchainsPom holds the JDK requirement of the maven-toolchains-plugin, whose configuration gopom leaves out
+type toolchainsPom struct {
+	Plugins []struct {
+		ArtifactID string          `xml:"artifactId"`
+		JDK        toolchainValues `xml:"configuration>toolchains>jdk"`
+	} `xml:"build>plugins>plugin"`
+}
+
+// toolchainsFile is the toolchains.xml listing the toolchains available to the build
+type toolchainsFile struct {
+	Toolchains []struct {
+		Type     string          `xml:"type"`
+		Provides toolchainValues `xml:"provides"`
+	} `xml:"toolchain"`
+}
+
+// defaultToolchainsFile returns the user toolchains.xml, next to the default local repository
+func defaultToolchainsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "toolchains.xml")
+}
+
+// readToolchainRequirement returns the JDK the maven-toolchains-plugin of the project pom file requires, if any
+func readToolchainRequirement(pomPath string) map[string]string {
+	data, err := ioutil.ReadFile(pomPath)
+	if err != nil {
+		return nil
+	}
+
+	var pom toolchainsPom
+	if err := unmarshalPom(data, &pom); err != nil {
+		return nil
+	}
+	for _, plugin := range pom.Plugins {
+		if strings.TrimSpace(plugin.ArtifactID) == toolchainsPluginArtifactID {
+			if requirement := plugin.JDK.toMap(); len(requirement) > 0 {
+				return requirement
+			}
+		}
+	}
+	return nil
+}
+
+// resolveToolchain returns what the first JDK toolchain of the toolchains file matching the requirement provides
+func resolveToolchain(file string, requirement map[string]string) (map[string]string, bool) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+
+	var toolchains toolchainsFile
+	if err := xml.Unmarshal(data, &toolchains); err != nil {
+		return nil, false
+	}
+	for _, toolchain := range toolchains.Toolchains {
+		if strings.TrimSpace(toolchain.Type) != jdkToolchainType {
+			continue
+		}
+		provides := toolchain.Provides.toMap()
+		if toolchainMatches(provides, requirement) {
+			return provides, true
+		}
+	}
+	return nil, false
+}
+
+// toolchainMatches tells whether a toolchain provides every requirement. The version may be required as a range,
+// e.g. `[11,17)`, other values must be equal
+func toolchainMatches(provides, requirement map[string]string) bool {
+	for key, required := range requirement {
+		provided, ok := provides[key]
+		if !ok {
+			return false
+		}
+		if key == "version" {
+			if !versionMatches(provided, required) {
+				return false
+			}
+			continue
+		}
+		if provided != required {
+			return false
+		}
+	}
+	return true
+}
+
+// versionMatches checks a version against a Maven version requirement, either a version or a `[low,high)` range
+func versionMatches(version, requirement string) bool {
+	if !strings.ContainsAny(requirement, "[]()") {
+		return compareVersions(version, requirement) == 0
+	}
+	if len(requirement) < 2 {
+		return false
+	}
+
+	bounds := strings.SplitN(requirement[1:len(requirement)-1], ",", 2)
+	low := strings.TrimSpace(bounds[0])
+	high := low
+	if len(bounds) == 2 {
+		high = strings.TrimSpace(bounds[1])
+	}
+	if low != "" {
+		if c := compareVersions(version, low); c < 0 || (c == 0 && requirement[0] == '(') {
+			return false
+		}
+	}
+	if high != "" {
+		if c := compareVersions(version, high); c > 0 || (c == 0 && requirement[len(requirement)-1] == ')') {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares the numeric components of two versions, a missing component counts as zero
+func compareVersions(a, b string) int {
+	split := func(version string) []string {
+		return strings.FieldsFunc(version, func(r rune) bool { return r == '.' || r == '-' || r == '_' })
+	}
+	left, right := split(a), split(b)
+	for i := 0; i < len(left) || i < len(right); i++ {
+		l, r := "0", "0"
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		ln, lerr := strconv.Atoi(l)
+		rn, rerr := strconv.Atoi(r)
+		switch {
+		case lerr == nil && rerr == nil && ln != rn:
+			if ln < rn {
+				return -1
+			}
+			return 1
+		case (lerr != nil || rerr != nil) && l != r:
+			return strings.Compare(l, r)
+		}
+	}
+	return 0
+}
+
+// toolchainEnvironment describes the JDK toolchain the project build is pinned to, if it resolves
+func (o Options) toolchainEnvironment(path string) []models.Property {
+	requirement := readToolchainRequirement(filepath.Join(path, o.pomFile()))
+	if requirement == nil {
+		return nil
+	}
+
+	file := o.ToolchainsFile
+	if file == "" {
+		file = defaultToolchainsFile()
+	}
+	provides, ok := resolveToolchain(file, requirement)
+	if !ok {
+		return nil
+	}
+
+	var properties []models.Property
+	properties = appendAttribute(properties, "Toolchain JDK version", provides["version"])
+	properties = appendAttribute(properties, "Toolchain JDK vendor", provides["vendor"])
+	return properties
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+const (
+	diagnosticUnresolvedVersion = "unresolved-version"
+	originalVersionProperty     = "originalVersion"
+	importScope                 = "import"
+	pomArtifactType             = "pom"
+)
+
+// Policies for the modules whose version is unresolved, see Options.UnresolvedVersions
+const (
+	UnresolvedVersionsOmit        = "omit"
+	UnresolvedVersionsError       = "error"
+	UnresolvedVersionsNoAssertion = "noassertion"
+)
+
+// normalizeVersion canonicalizes a version for package identifiers: a leading `v` in front of a
+// number and SemVer build metadata (`+build`) are dropped. Maven qualifiers such as `-RELEASE`,
+// `.RELEASE`, `-SNAPSHOT` or `.Final` are part of the version and are kept untouched.
+func normalizeVersion(version string) string {
+	normalized := strings.TrimSpace(version)
+	if len(normalized) > 1 && (normalized[0] == 'v' || normalized[0] == 'V') && isDigit(normalized[1]) {
+		normalized = normalized[1:]
+	}
+	if i := strings.Index(normalized, "+"); i > 0 {
+		normalized = normalized[:i]
+	}
+	return normalized
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// resolvePropertyVersion expands the placeholders of a version against the project properties and coordinates.
+// A version left with placeholders that cannot be resolved, e.g. `${property}` or `1.${minor}`, is empty
+func resolvePropertyVersion(version string, project gopom.Project) string {
+	resolved := resolveProperty(project, version)
+	if strings.Contains(resolved, "${") {
+		return ""
+	}
+	return resolved
+}
+
+// resolveManagedVersion looks up the version pinned for the artifact in the dependencyManagement of the project and
+// of the parents it inherits from, then in the BOMs they import, read from the reactor modules or else the local
+// repository or the offline mirror
+func resolveManagedVersion(groupID, artifactID string, project gopom.Project, options Options) string {
+	return lookupManagedVersion(groupID, artifactID, project, options, map[string]bool{})
+}
+
+// lookupManagedVersion looks up a managed version the way Maven builds the effective dependencyManagement: the
+// entries of the project win over the inherited ones, the entries of the nearest parent winning, and both win over
+// the imported ones, as the BOMs are imported once the parents are merged. The BOMs imported first win over the later
+// ones, each BOM being searched along with its parents and the BOMs it imports in turn. The BOMs being searched are
+// tracked so that an import cycle ends the lookup
+func lookupManagedVersion(groupID, artifactID string, project gopom.Project, options Options, importing map[string]bool) string {
+	lineage := projectLineage(project, options)
+	for _, declaring := range lineage {
+		for _, managed := range declaring.DependencyManagement.Dependencies {
+			if isBOMImport(managed) || managed.ArtifactID != artifactID {
+				continue
+			}
+			if groupID != "" && resolveCoordinate(declaring, managed.GroupID) != groupID {
+				continue
+			}
+			// the properties of the project override the inherited ones
+			if version := resolvePropertyVersion(managed.Version, project); version != "" {
+				return version
+			}
+			return resolvePropertyVersion(managed.Version, declaring)
+		}
+	}
+
+	for _, declaring := range lineage {
+		if version := lookupImportedVersion(groupID, artifactID, declaring, options, importing); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// projectLineage returns the project followed by the parents it inherits from, nearest first. The coordinates of
+// the parents are tracked so that a cycle of parents ends the chain
+func projectLineage(project gopom.Project, options Options) []gopom.Project {
+	lineage := []gopom.Project{project}
+	seen := map[string]bool{}
+	for {
+		file, ok := parentArtifact(project)
+		if !ok || seen[file.fileName()] {
+			return lineage
+		}
+		seen[file.fileName()] = true
+		if project, ok = readParentPom(project, options); !ok {
+			return lineage
+		}
+		lineage = append(lineage, project)
+	}
+}
+
+// lookupImportedVersion looks up a managed version in the BOMs imported by the dependencyManagement of a project
+func lookupImportedVersion(groupID, artifactID string, project gopom.Project, options Options, importing map[string]bool) string {
+	for _, managed := range project.DependencyManagement.Dependencies {
+		if !isBOMImport(managed) {
+			continue
+		}
+		bom := newArtifact(managed, resolveProperties(managed.Version, project), options)
+		coordinates := bom.groupID + ":" + bom.artifactID + ":" + bom.version
+		if importing[coordinates] {
+			continue
+		}
+		imported, ok := options.reactor.lookup(bom)
+		if !ok {
+			path, err := options.artifactPath(bom)
+			if err != nil {
+				options.reportMissingFromMirror(bom, err)
+				continue
+			}
+			if imported, err = readPomFile(path); err != nil {
+				continue
+			}
+		}
+
+		importing[coordinates] = true
+		version := lookupManagedVersion(groupID, artifactID, imported, options, importing)
+		delete(importing, coordinates)
+		if version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// isBOMImport reports whether a dependencyManagement entry imports the entries of a BOM
+func isBOMImport(managed gopom.Dependency) bool {
+	return strings.TrimSpace(managed.Scope) == importScope && strings.TrimSpace(managed.Type) == pomArtifactType
+}
+
+// resolveVersionsFromDependencyList fills versions still missing after reading pom.xml
+// from the versions mvn resolved in its dependency list
+func resolveVersionsFromDependencyList(modules []models.Module, dependencyList []string, project gopom.Project, options Options) {
+	for i := range modules {
+		if modules[i].Root || modules[i].Version != "" {
+			continue
+		}
+		for _, item := range dependencyList {
+			dep, ok := parseDependencyListEntry(item)
+			if !ok || dependencyModuleName(dep) != modules[i].Name {
+				continue
+			}
+			modules[i].Version = dep.Version
+			updatePackageDownloadLocation(newArtifact(dep, dep.Version, options), project, &modules[i], project.DistributionManagement)
+			break
+		}
+	}
+}
+
+// handleUnresolvedVersions applies the configured policy to the modules whose version could not be resolved from
+// any source: they are dropped by default, fail the generation or are kept without version
+func handleUnresolvedVersions(modules []models.Module, options Options) ([]models.Module, error) {
+	switch options.UnresolvedVersions {
+	case "", UnresolvedVersionsOmit:
+		return excludeUnresolvedVersions(modules, options), nil
+	case UnresolvedVersionsError:
+		var unresolved []string
+		for _, module := range modules {
+			if !module.Root && module.Version == "" {
+				unresolved = append(unresolved, module.Name)
+			}
+		}
+		if len(unresolved) > 0 {
+			return nil, fmt.Errorf("%w: %s could not be resolved from pom.xml, dependencyManagement or mvn dependency list",
+				errUnresolvedVersion, strings.Join(unresolved, ", "))
+		}
+		return modules, nil
+	case UnresolvedVersionsNoAssertion:
+		for _, module := range modules {
+			if !module.Root && module.Version == "" {
+				options.report(models.DiagnosticWarning, diagnosticUnresolvedVersion, module.Name,
+					fmt.Sprintf("version of %s could not be resolved from pom.xml, dependencyManagement or mvn dependency list, it is emitted without version", module.Name))
+			}
+		}
+		return modules, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedVersionPolicy, options.UnresolvedVersions)
+	}
+}
+
+// excludeUnresolvedVersions drops the modules whose version could not be resolved from any source,
+// reporting each of them as a diagnostic rather than emitting packages with an empty version
+func excludeUnresolvedVersions(modules []models.Module, options Options) []models.Module {
+	filtered := make([]models.Module, 0, len(modules))
+	for _, module := range modules {
+		if module.Root || module.Version != "" {
+			filtered = append(filtered, module)
+			continue
+		}
+
+		options.report(models.DiagnosticWarning, diagnosticUnresolvedVersion, module.Name,
+			fmt.Sprintf("version of %s could not be resolved from pom.xml, dependencyManagement or mvn dependency list", module.Name))
+		for i := range modules {
+			delete(modules[i].Modules, dependencyKey(module))
+		}
+	}
+	return filtered
+}
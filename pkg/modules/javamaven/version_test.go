@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package javamaven
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vifraa/gopom"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestResolveVersionlessDependencies(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/managed")
+	assert.NoError(t, err)
+
+	options := Options{diagnostics: &models.Diagnostics{}}
+	modules := convertDeclaredModules(project, options)
+	dependencyList := []string{"   commons-io:commons-io:jar:2.8.0:compile"}
+	resolveVersionsFromDependencyList(modules, dependencyList, project, options)
+	modules = excludeUnresolvedVersions(modules, options)
+
+	for _, mod := range modules {
+		if mod.Name == "slf4j-api" {
+			assert.Equal(t, "1.7.30", mod.Version)
+		}
+		assert.NotEqual(t, "unknown", mod.Name)
+	}
+	assert.Equal(t, "2.8.0", findModule(t, modules, "commons-io").Version)
+	assert.Equal(t, MavenCentralUrl+"commons-io/commons-io/2.8.0/commons-io-2.8.0.jar", findModule(t, modules, "commons-io").PackageDownloadLocation)
+	assert.NotContains(t, modules[0].Modules, "com.example:unknown")
+
+	diagnostics := options.diagnostics.List()
+	assert.Equal(t, 1, len(diagnostics))
+	assert.Equal(t, diagnosticUnresolvedVersion, diagnostics[0].Code)
+	assert.Equal(t, "unknown", diagnostics[0].Module)
+}
+
+func TestUnresolvedVersionPolicies(t *testing.T) {
+	defer stubMaven(t, "true")()
+	dir := filepath.Join("testdata", "unresolvedversion")
+
+	for _, policy := range []string{"", UnresolvedVersionsOmit} {
+		options := Options{UnresolvedVersions: policy, diagnostics: &models.Diagnostics{}}
+		modules, err := convertPOMReaderToModules(dir, true, options)
+		assert.NoError(t, err)
+		assert.Len(t, modules, 2, policy)
+		assert.Equal(t, "1.7.30", findModule(t, modules, "slf4j-api").Version)
+		assert.ElementsMatch(t, []string{"slf4j-api"}, moduleNames(modules[0].Modules))
+		assert.Len(t, options.diagnostics.List(), 2)
+	}
+
+	_, err := convertPOMReaderToModules(dir, true, Options{UnresolvedVersions: UnresolvedVersionsError})
+	assert.True(t, errors.Is(err, errUnresolvedVersion), "%v", err)
+	assert.Contains(t, err.Error(), "guava, commons-io could not be resolved")
+
+	options := Options{UnresolvedVersions: UnresolvedVersionsNoAssertion, diagnostics: &models.Diagnostics{}}
+	modules, err := convertPOMReaderToModules(dir, true, options)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 4)
+	assert.Equal(t, "1.7.30", findModule(t, modules, "slf4j-api").Version)
+	assert.Empty(t, findModule(t, modules, "guava").Version)
+	assert.Empty(t, findModule(t, modules, "commons-io").Version, "an embedded placeholder is not left in the version")
+	assert.Contains(t, modules[0].Modules, "com.google.guava:guava")
+	diagnostics := options.diagnostics.List()
+	assert.Len(t, diagnostics, 2)
+	assert.Equal(t, diagnosticUnresolvedVersion, diagnostics[0].Code)
+
+	_, err = convertPOMReaderToModules(dir, true, Options{UnresolvedVersions: "literal"})
+	assert.True(t, errors.Is(err, errUnsupportedVersionPolicy))
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3":               "1.2.3",
+		"v1.2.3":              "1.2.3",
+		"V2.0":                "2.0",
+		"1.0.0+20210101":      "1.0.0",
+		"v1.0.0-rc.1+build.5": "1.0.0-rc.1",
+		"5.3.8.RELEASE":       "5.3.8.RELEASE",
+		"2.3.1-RELEASE":       "2.3.1-RELEASE",
+		"1.0-SNAPSHOT":        "1.0-SNAPSHOT",
+		"5.4.32.Final":        "5.4.32.Final",
+		"vertx-4.1":           "vertx-4.1",
+		" 30.1-jre ":          "30.1-jre",
+		"":                    "",
+	}
+	for version, want := range tests {
+		assert.Equal(t, want, normalizeVersion(version), version)
+	}
+}
+
+func TestCreateModuleKeepsOriginalVersion(t *testing.T) {
+	project, err := readAndLoadPomFile("testdata/provenance")
+	assert.NoError(t, err)
+
+	mod := createModule(gopom.Dependency{GroupID: "io.example", ArtifactID: "client", Version: "v1.4.0+git.abc"}, project, provenanceDependencies, Options{})
+	assert.Equal(t, "1.4.0", mod.Version)
+	assert.Equal(t, "v1.4.0+git.abc", mod.GetProperty(originalVersionProperty))
+
+	mod = createModule(gopom.Dependency{GroupID: "org.springframework", ArtifactID: "spring-core", Version: "5.3.8.RELEASE"}, project, provenanceDependencies, Options{})
+	assert.Equal(t, "5.3.8.RELEASE", mod.Version)
+	assert.Equal(t, "", mod.GetProperty(originalVersionProperty))
+
+	// the artifact is looked up at its declared version, the package being identified by the normalized one
+	repository := t.TempDir()
+	dir := filepath.Join(repository, "io", "example", "lib", "v1.2.0")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	jar := filepath.Join(dir, "lib-v1.2.0.jar")
+	assert.NoError(t, ioutil.WriteFile(jar, []byte("lib"), 0644))
+	checksum, err := readFileCheckSum(jar)
+	assert.NoError(t, err)
+
+	mod = createModule(gopom.Dependency{GroupID: "io.example", ArtifactID: "lib", Version: "v1.2.0"}, project, provenanceDependencies, Options{LocalRepository: repository})
+	assert.Equal(t, "1.2.0", mod.Version)
+	assert.Equal(t, checksum, mod.CheckSum.Value)
+	assert.Equal(t, "pkg:maven/io.example/lib@1.2.0", mod.PackageURL)
+	assert.Equal(t, MavenCentralUrl+"io/example/lib/v1.2.0/lib-v1.2.0.jar", mod.PackageDownloadLocation)
+}
+
+func TestResolveVersionsFromNestedBOMImports(t *testing.T) {
+	project, err := readAndLoadPomFile(filepath.Join("testdata", "bom"))
+	assert.NoError(t, err)
+
+	options := Options{LocalRepository: filepath.Join("testdata", "bom", "repository")}
+	// pinned by the BOM imported by the imported BOM
+	assert.Equal(t, "1.7.36", resolveManagedVersion("org.slf4j", "slf4j-api", project, options))
+	// the project entries win over the imported ones
+	assert.Equal(t, "30.1-jre", resolveManagedVersion("com.google.guava", "guava", project, options))
+	// the entries of a BOM win over the ones it imports
+	assert.Equal(t, "4.13.2", resolveManagedVersion("junit", "junit", project, options))
+	// the import cycle between the BOMs ends the lookup
+	assert.Empty(t, resolveManagedVersion("org.example", "unmanaged", project, options))
+
+	mod := createModule(project.Dependencies[0], project, provenanceDependencies, options)
+	assert.Equal(t, "1.7.36", mod.Version)
+}
+
+func TestResolveVersionsFromReactorPlatform(t *testing.T) {
+	defer stubMaven(t, "true")()
+
+	// the platform module is not installed in the repository, its BOM is read from the reactor
+	options := Options{LocalRepository: t.TempDir()}
+	modules, err := convertPOMReaderToModules(filepath.Join("testdata", "platform"), true, options)
+	assert.NoError(t, err)
+
+	guava := findModule(t, modules, "guava")
+	assert.Equal(t, "31.1-jre", guava.Version)
+	assert.Equal(t, "1.7.36", findModule(t, modules, "slf4j-api").Version)
+	assert.Contains(t, findModule(t, modules, "app").Modules, "com.google.guava:guava")
+
+	app, err := readAndLoadPomFile(filepath.Join("testdata", "platform", "app"))
+	assert.NoError(t, err)
+	assert.Empty(t, resolveManagedVersion("com.google.guava", "guava", app, options), "outside of the reactor")
+}
+
+func TestResolveVersionsFromParentPoms(t *testing.T) {
+	// the parent is read at its relative path, its own parent from the repository
+	options := Options{LocalRepository: filepath.Join("testdata", "managedparent", "repository")}
+	modules, err := NewWithOptions(options).ListDeclaredModules(filepath.Join("testdata", "managedparent", "service"))
+	assert.NoError(t, err)
+
+	// managed by the parent through one of its properties, the nearest parent winning
+	assert.Equal(t, "31.1-jre", findModule(t, modules, "guava").Version)
+	// managed by the parent of the parent
+	assert.Equal(t, "1.7.36", findModule(t, modules, "slf4j-api").Version)
+	// the entries of the project win over the inherited ones
+	assert.Equal(t, "4.13.2", findModule(t, modules, "junit").Version)
+
+	project, err := readAndLoadPomFile(filepath.Join("testdata", "managedparent", "service"))
+	assert.NoError(t, err)
+	assert.Equal(t, "31.1-jre", resolveManagedVersion("com.google.guava", "guava", project, options.withReactor(filepath.Join("testdata", "managedparent", "service"), project)))
+	assert.Empty(t, resolveManagedVersion("org.slf4j", "slf4j-api", project, Options{LocalRepository: t.TempDir()}), "without the parents")
+}
+
+func TestResolveVersionsFromOfflineMirror(t *testing.T) {
+	project, err := readAndLoadPomFile(filepath.Join("testdata", "bom"))
+	assert.NoError(t, err)
+
+	// the BOMs are read from the mirror, not the local repository
+	options := Options{OfflineMirror: filepath.Join("testdata", "bom", "repository"), LocalRepository: t.TempDir(), diagnostics: &models.Diagnostics{}}
+	assert.Equal(t, "1.7.36", resolveManagedVersion("org.slf4j", "slf4j-api", project, options))
+	assert.Empty(t, options.diagnostics.List())
+
+	options = Options{OfflineMirror: t.TempDir(), LocalRepository: filepath.Join("testdata", "bom", "repository"), diagnostics: &models.Diagnostics{}}
+	assert.Empty(t, resolveManagedVersion("org.slf4j", "slf4j-api", project, options))
+	assert.Empty(t, resolveManagedVersion("junit", "junit", project, options))
+	diagnostics := options.diagnostics.List()
+	assert.Len(t, diagnostics, 1, "the missing BOM is reported once")
+	assert.Equal(t, models.DiagnosticError, diagnostics[0].Severity)
+	assert.Equal(t, diagnosticMissingFromMirror, diagnostics[0].Code)
+	assert.Equal(t, "platform-bom", diagnostics[0].Module)
+	assert.Contains(t, diagnostics[0].Message, "com.example:platform-bom:1.0.0 pom is not at")
+}
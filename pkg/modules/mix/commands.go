@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mix
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+type command string
+
+var (
+	VersionCmd      command = "mix --version"
+	ProjectFileName string  = "mix.exs"
+	LockFileName    string  = "mix.lock"
+	HexRepository   string  = "hexpm"
+)
+
+// Parse ...
+func (c command) Parse() []string {
+	cmd := strings.TrimSpace(string(c))
+	return strings.Fields(cmd)
+}
+
+func (m *mix) buildCmd(cmd command, path string) error {
+	cmdArgs := cmd.Parse()
+	if cmdArgs[0] != "mix" {
+		return errNoMixCommand
+	}
+
+	command := helper.NewCmd(helper.CmdOptions{
+		Name:      cmdArgs[0],
+		Args:      cmdArgs[1:],
+		Directory: path,
+	})
+
+	m.command = command
+
+	return command.Build()
+}
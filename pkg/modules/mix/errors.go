@@ -0,0 +1,15 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mix
+
+import (
+	"errors"
+)
+
+type errType error
+
+var errDependenciesNotFound = errors.New("no mix.lock found. Please fetch the dependencies before running spdx-sbom-generator, e.g.: `mix deps.get`")
+var errNoMixCommand = errors.New("no Mix command")
+var errFailedToReadProject errType = errors.New("failed to read mix.exs")
+var errFailedToReadLockFile errType = errors.New("failed to read mix.lock")
+var errInvalidTerm errType = errors.New("invalid Elixir term")
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mix
+
+import (
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type mix struct {
+	metadata models.PluginMetadata
+	command  *helper.Cmd
+}
+
+// New ...
+func New() *mix {
+	return &mix{
+		metadata: models.PluginMetadata{
+			Name:       "Mix Package Manager",
+			Slug:       "Mix",
+			Manifest:   []string{ProjectFileName},
+			ModulePath: []string{"deps"},
+		},
+	}
+}
+
+// GetMetadata ...
+func (m *mix) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// IsValid ...
+func (m *mix) IsValid(path string) bool {
+	for i := range m.metadata.Manifest {
+		if helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModulesInstalled checks the dependencies were resolved, mix.lock pinning them
+func (m *mix) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFileName)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// GetVersion ...
+func (m *mix) GetVersion() (string, error) {
+	if err := m.buildCmd(VersionCmd, "."); err != nil {
+		return "", err
+	}
+
+	return m.command.Output()
+}
+
+// SetRootModule ...
+func (m *mix) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule ...
+func (m *mix) GetRootModule(path string) (*models.Module, error) {
+	project, err := readProject(path)
+	if err != nil {
+		return nil, err
+	}
+	root := project.module()
+	return &root, nil
+}
+
+// ListUsedModules ...
+func (m *mix) ListUsedModules(path string) ([]models.Module, error) {
+	return m.ListModulesWithDeps(path)
+}
+
+// ListModulesWithDeps reads the application and its dependencies from mix.exs, and the pinned packages from mix.lock
+func (m *mix) ListModulesWithDeps(path string) ([]models.Module, error) {
+	project, err := readProject(path)
+	if err != nil {
+		return nil, err
+	}
+	return readLockFile(path, project)
+}
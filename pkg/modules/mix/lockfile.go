@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mix
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// Sources of the locked dependencies
+const (
+	sourceHex  = "hex"
+	sourceGit  = "git"
+	sourcePath = "path"
+)
+
+const (
+	sourceProperty = "source"
+	onlyProperty   = "only"
+	// hexTarballs is the location of the package tarballs of the public Hex repository
+	hexTarballs = "https://repo.hex.pm/tarballs"
+)
+
+// lockedModule is a module of mix.lock along with the names of the dependencies it requires
+type lockedModule struct {
+	module   models.Module
+	requires []string
+}
+
+// readLockFile reads the packages pinned in mix.lock and the path dependencies of the project, in name order after
+// the root module. The hex packages are linked to the dependencies they require, the root module to the
+// dependencies declared in mix.exs
+func readLockFile(path string, p project) ([]models.Module, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, LockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	term, err := parseTerm(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	entries, ok := term.(mapTerm)
+	if !ok {
+		return nil, fmt.Errorf("%w: not a map", errFailedToReadLockFile)
+	}
+
+	locked := map[string]lockedModule{}
+	for _, entry := range entries {
+		name, ok := termString(entry.key)
+		if !ok {
+			continue
+		}
+		if module, ok := convertLockEntry(path, name, entry.value); ok {
+			locked[name] = module
+		}
+	}
+	for _, dep := range p.deps {
+		if _, ok := locked[dep.name]; !ok && dep.path != "" {
+			locked[dep.name] = lockedModule{module: convertPathDependency(path, dep)}
+		}
+	}
+
+	names := make([]string, 0, len(locked))
+	for name := range locked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	modules := make([]models.Module, 0, len(names)+1)
+	modules = append(modules, p.module())
+	index := map[string]int{}
+	for _, name := range names {
+		index[name] = len(modules)
+		modules = append(modules, locked[name].module)
+	}
+
+	for _, name := range names {
+		for _, dep := range locked[name].requires {
+			if j, ok := index[dep]; ok {
+				modules[index[name]].Modules[dep] = &modules[j]
+			}
+		}
+	}
+	for _, dep := range p.deps {
+		if j, ok := index[dep.name]; ok {
+			if dep.only != "" {
+				modules[j].SetProperty(onlyProperty, dep.only)
+			}
+			modules[0].Modules[dep.name] = &modules[j]
+		}
+	}
+	return modules, nil
+}
+
+// convertLockEntry builds the module of a mix.lock entry, either
+// `{:hex, :package, version, inner_checksum, build_tools, deps, repository, outer_checksum}` or
+// `{:git, url, revision, options}`
+func convertLockEntry(path, name string, value interface{}) (lockedModule, bool) {
+	entry, ok := value.(tuple)
+	if !ok || len(entry) < 3 {
+		return lockedModule{}, false
+	}
+	source, _ := entry[0].(atom)
+
+	switch string(source) {
+	case sourceHex:
+		return convertHexEntry(name, entry), true
+	case sourceGit:
+		return convertGitEntry(path, name, entry), true
+	}
+	return lockedModule{}, false
+}
+
+func convertHexEntry(name string, entry tuple) lockedModule {
+	pkg, _ := entry[1].(atom)
+	version, _ := termString(entry[2])
+	repository := HexRepository
+	if len(entry) > 6 {
+		if repo, ok := termString(entry[6]); ok {
+			repository = repo
+		}
+	}
+
+	// organization repositories, e.g. `hexpm:acme`, are the namespace of their packages
+	namespace := ""
+	if organization := strings.TrimPrefix(repository, HexRepository+":"); organization != repository {
+		namespace = organization
+	}
+	mod := models.Module{
+		Name:       name,
+		Version:    version,
+		PackageURL: buildPurl(namespace, string(pkg), version),
+		Supplier:   models.SupplierContact{Name: string(pkg)},
+		Modules:    map[string]*models.Module{},
+	}
+	mod.SetProperty(sourceProperty, sourceHex)
+	if repository == HexRepository {
+		mod.PackageDownloadLocation = fmt.Sprintf("%s/%s-%s.tar", hexTarballs, pkg, version)
+		mod.PackageHomePage = "https://hex.pm/packages/" + string(pkg)
+	}
+
+	// the outer checksum is the SHA-256 of the tarball, the older lockfiles only record the inner one of its contents
+	checksum := ""
+	if len(entry) > 7 {
+		checksum, _ = termString(entry[7])
+	}
+	if checksum == "" && len(entry) > 3 {
+		checksum, _ = termString(entry[3])
+	}
+	if checksum != "" {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: checksum}
+	}
+
+	var requires []string
+	if len(entry) > 5 {
+		deps, _ := entry[5].(list)
+		for _, dep := range deps {
+			if dep, ok := dep.(tuple); ok && len(dep) > 0 {
+				if depName, ok := dep[0].(atom); ok {
+					requires = append(requires, string(depName))
+				}
+			}
+		}
+	}
+	return lockedModule{module: mod, requires: requires}
+}
+
+// convertGitEntry builds the module of a git dependency, versioned by its fetched mix.exs, else by its tag or the
+// locked revision
+func convertGitEntry(path, name string, entry tuple) lockedModule {
+	repository, _ := termString(entry[1])
+	revision, _ := termString(entry[2])
+	mod := models.Module{
+		Name:                    name,
+		Version:                 revision,
+		Supplier:                models.SupplierContact{Name: name},
+		PackageDownloadLocation: "git+" + repository + "@" + revision,
+		Modules:                 map[string]*models.Module{},
+	}
+	mod.SetProperty(sourceProperty, sourceGit)
+	if len(entry) > 3 {
+		if tag, ok := keyword(entry[3], "tag"); ok {
+			mod.Version = tag
+		}
+	}
+	if fetched, err := readProject(filepath.Join(path, "deps", name)); err == nil && fetched.version != "" {
+		mod.Version = fetched.version
+	}
+	return lockedModule{module: mod}
+}
+
+// convertPathDependency builds the module of a path dependency, versioned by its mix.exs
+func convertPathDependency(path string, dep projectDep) models.Module {
+	mod := models.Module{
+		Name:      dep.name,
+		Supplier:  models.SupplierContact{Name: dep.name},
+		LocalPath: filepath.Join(path, dep.path),
+		Modules:   map[string]*models.Module{},
+	}
+	mod.SetProperty(sourceProperty, sourcePath)
+	if local, err := readProject(mod.LocalPath); err == nil {
+		mod.Version = local.version
+		mod.PackageURL = buildPurl("", local.app, local.version)
+	}
+	return mod
+}
+
+// hexPurl builds the Hex package urls, the organization being the namespace
+var hexPurl = purl.Builder{Type: "hex", Lowercase: true}
+
+// buildPurl returns the `pkg:hex/[<organization>/]<name>@<version>` package url
+func buildPurl(namespace, name, version string) string {
+	return hexPurl.Build(namespace, name, version, nil)
+}
+
+// termString returns the value of a string or atom term
+func termString(term interface{}) (string, bool) {
+	switch value := term.(type) {
+	case string:
+		return value, true
+	case atom:
+		return string(value), true
+	}
+	return "", false
+}
+
+// keyword returns the string value of a key of a keyword list
+func keyword(term interface{}, key string) (string, bool) {
+	entries, ok := term.(list)
+	if !ok {
+		return "", false
+	}
+	for _, entry := range entries {
+		if pair, ok := entry.(tuple); ok && len(pair) == 2 && pair[0] == atom(key) {
+			return termString(pair[1])
+		}
+	}
+	return "", false
+}
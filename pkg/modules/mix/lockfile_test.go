@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mix
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func requiredNames(module models.Module) []string {
+	names := make([]string, 0, len(module.Modules))
+	for name := range module.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestListModulesWithDeps(t *testing.T) {
+	path := filepath.Join("testdata", "app")
+	modules, err := New().ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 12)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "shop", root.Name)
+	assert.Equal(t, "0.3.1", root.Version)
+	assert.Equal(t, "pkg:hex/shop@0.3.1", root.PackageURL)
+	assert.Equal(t, []string{"credo", "jason", "local_utils", "money", "plug_cowboy"}, requiredNames(root))
+
+	byName := map[string]models.Module{}
+	for _, module := range modules[1:] {
+		byName[module.Name] = module
+	}
+
+	cowboy := byName["cowboy"]
+	assert.Equal(t, "2.9.0", cowboy.Version)
+	assert.Equal(t, "pkg:hex/cowboy@2.9.0", cowboy.PackageURL)
+	assert.Equal(t, "https://repo.hex.pm/tarballs/cowboy-2.9.0.tar", cowboy.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "2c729f934b4e1aa149aff882f57c6372c15399a20d54f65c8d67bef583021bde"}, cowboy.CheckSum)
+	assert.Equal(t, []string{"cowlib", "ranch"}, requiredNames(cowboy))
+	assert.Equal(t, []string{"cowboy"}, requiredNames(byName["plug_cowboy"]))
+	assert.Empty(t, requiredNames(byName["jason"]), "the optional decimal is not locked")
+
+	assert.Equal(t, "a6d6bd0a6d0c8f21a8e5ec0c2e2a9d5d7f2b9e6e5d4c3b2a1f0e9d8c7b6a5f4e3", byName["legacy"].CheckSum.Value, "the inner checksum of the older entries")
+	assert.Equal(t, "pkg:hex/acme/private_lib@0.9.0", byName["private_lib"].PackageURL)
+	assert.Empty(t, byName["private_lib"].PackageDownloadLocation)
+	assert.Equal(t, "dev, test", byName["credo"].GetProperty(onlyProperty))
+
+	money := byName["money"]
+	assert.Equal(t, sourceGit, money.GetProperty(sourceProperty))
+	assert.Equal(t, "v1.4.0", money.Version)
+	assert.Equal(t, "git+https://github.com/example/money.git@3a8e2b1c4d5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b", money.PackageDownloadLocation)
+	assert.Nil(t, money.CheckSum)
+
+	localUtils := byName["local_utils"]
+	assert.Equal(t, sourcePath, localUtils.GetProperty(sourceProperty))
+	assert.Equal(t, "0.1.0", localUtils.Version)
+	assert.Equal(t, filepath.Join("testdata", "local_utils"), localUtils.LocalPath)
+	assert.Equal(t, "pkg:hex/local_utils@0.1.0", localUtils.PackageURL)
+}
+
+func TestParseTerm(t *testing.T) {
+	term, err := parseTerm(`%{"a" => {:hex, :"quoted atom", [only: [:dev]], true}, b: 1.5}`)
+	assert.NoError(t, err)
+	assert.Equal(t, mapTerm{
+		{key: "a", value: tuple{atom("hex"), atom("quoted atom"), list{tuple{atom("only"), list{atom("dev")}}}, "true"}},
+		{key: atom("b"), value: "1.5"},
+	}, term)
+
+	_, err = parseTerm(`%{"a": {:hex, "1.0"}`)
+	assert.True(t, errors.Is(err, errInvalidTerm))
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mix
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+var (
+	appPattern     = regexp.MustCompile(`\bapp:\s*:([A-Za-z_][A-Za-z0-9_]*)`)
+	versionPattern = regexp.MustCompile(`\bversion:\s*(?:"([^"]*)"|@([A-Za-z_][A-Za-z0-9_]*))`)
+	depsPattern    = regexp.MustCompile(`(?s)\bdefp?\s+deps\b.*?\bdo\b(.*?)\n\s*end\b`)
+	depPattern     = regexp.MustCompile(`\{\s*:([A-Za-z_][A-Za-z0-9_]*)\s*,([^{}]*)\}`)
+	pathPattern    = regexp.MustCompile(`\bpath:\s*"([^"]*)"`)
+	onlyPattern    = regexp.MustCompile(`\bonly:\s*(\[[^\]]*\]|:[A-Za-z_]+)`)
+	commentPattern = regexp.MustCompile(`#[^\n]*`)
+)
+
+// project is the application described by mix.exs, read statically without evaluating it
+type project struct {
+	path    string
+	app     string
+	version string
+	deps    []projectDep
+}
+
+// projectDep is a dependency declared in the deps function of mix.exs
+type projectDep struct {
+	name string
+	// path is the directory of a path dependency, relative to the project
+	path string
+	// only lists the environments the dependency is restricted to, e.g. `dev, test`
+	only string
+}
+
+// readProject reads the application name, its version and its dependencies from mix.exs. The version may be given
+// by a module attribute, e.g. `version: @version`
+func readProject(path string) (project, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, ProjectFileName))
+	if err != nil {
+		return project{}, fmt.Errorf("%w: %v", errFailedToReadProject, err)
+	}
+	source := commentPattern.ReplaceAllString(string(data), "")
+
+	p := project{path: path}
+	if match := appPattern.FindStringSubmatch(source); match != nil {
+		p.app = match[1]
+	}
+	if match := versionPattern.FindStringSubmatch(source); match != nil {
+		p.version = match[1]
+		if match[2] != "" {
+			attribute := regexp.MustCompile(`@` + match[2] + `\s+"([^"]*)"`)
+			if value := attribute.FindStringSubmatch(source); value != nil {
+				p.version = value[1]
+			}
+		}
+	}
+	if p.app == "" {
+		return project{}, fmt.Errorf("%w: no app in %s", errFailedToReadProject, ProjectFileName)
+	}
+
+	if deps := depsPattern.FindStringSubmatch(source); deps != nil {
+		for _, match := range depPattern.FindAllStringSubmatch(deps[1], -1) {
+			dep := projectDep{name: match[1]}
+			if path := pathPattern.FindStringSubmatch(match[2]); path != nil {
+				dep.path = path[1]
+			}
+			if only := onlyPattern.FindStringSubmatch(match[2]); only != nil {
+				dep.only = strings.NewReplacer("[", "", "]", "", ":", "").Replace(only[1])
+			}
+			p.deps = append(p.deps, dep)
+		}
+	}
+	return p, nil
+}
+
+// module is the root module of the application
+func (p project) module() models.Module {
+	root := models.Module{
+		Name:       p.app,
+		Version:    p.version,
+		Root:       true,
+		PackageURL: buildPurl("", p.app, p.version),
+		Supplier:   models.SupplierContact{Name: p.app},
+		LocalPath:  p.path,
+		Modules:    map[string]*models.Module{},
+	}
+	if licensePkg, err := helper.GetLicenses(p.path); err == nil {
+		root.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
+		root.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
+		root.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
+		root.CommentsLicense = licensePkg.Comments
+	}
+	return root
+}
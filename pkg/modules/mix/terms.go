@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package mix
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// The Elixir terms of mix.lock: strings, atoms, tuples, lists and maps. Keyword list entries, e.g. `hex: :cowlib`,
+// are the tuples of their atom key and value, and other literals such as numbers are kept as their source text
+type (
+	atom    string
+	tuple   []interface{}
+	list    []interface{}
+	mapTerm []mapEntry
+)
+
+type mapEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// termParser is a recursive descent parser of the Elixir literals found in mix.lock
+type termParser struct {
+	input []rune
+	pos   int
+	// isKeyword tells whether the last element parsed by keywordOrTerm was a keyword entry
+	isKeyword bool
+}
+
+// parseTerm parses a single Elixir literal, followed by nothing but spaces
+func parseTerm(input string) (interface{}, error) {
+	p := &termParser{input: []rune(input)}
+	term, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q after the term", p.input[p.pos])
+	}
+	return term, nil
+}
+
+func (p *termParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w at offset %d: %s", errInvalidTerm, p.pos, fmt.Sprintf(format, args...))
+}
+
+// skipSpaces skips white spaces and `#` comments
+func (p *termParser) skipSpaces() {
+	for p.pos < len(p.input) {
+		switch r := p.input[p.pos]; {
+		case unicode.IsSpace(r):
+			p.pos++
+		case r == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *termParser) peek() rune {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *termParser) term() (interface{}, error) {
+	switch r := p.peek(); {
+	case r == 0:
+		return nil, p.errorf("unexpected end of input")
+	case r == '"':
+		return p.str()
+	case r == ':':
+		p.pos++
+		if p.pos < len(p.input) && p.input[p.pos] == '"' {
+			name, err := p.str()
+			return atom(name), err
+		}
+		return atom(p.word()), nil
+	case r == '{':
+		p.pos++
+		elements, err := p.elements('}')
+		return tuple(elements), err
+	case r == '[':
+		p.pos++
+		elements, err := p.elements(']')
+		return list(elements), err
+	case r == '%':
+		p.pos++
+		if p.peek() != '{' {
+			return nil, p.errorf("expected { after %%")
+		}
+		p.pos++
+		return p.mapEntries()
+	default:
+		word := p.word()
+		if word == "" {
+			return nil, p.errorf("unexpected %q", r)
+		}
+		return word, nil
+	}
+}
+
+// elements parses the comma separated terms up to the closing rune, the keyword entries included
+func (p *termParser) elements(closing rune) ([]interface{}, error) {
+	var elements []interface{}
+	for {
+		if p.peek() == closing {
+			p.pos++
+			return elements, nil
+		}
+		element, err := p.keywordOrTerm()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+		if err := p.separator(closing); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// mapEntries parses the `key => value` or `key: value` entries of a map up to its closing brace
+func (p *termParser) mapEntries() (mapTerm, error) {
+	var entries mapTerm
+	for {
+		if p.peek() == '}' {
+			p.pos++
+			return entries, nil
+		}
+		element, err := p.keywordOrTerm()
+		if err != nil {
+			return nil, err
+		}
+		if keyword, ok := element.(tuple); ok && p.isKeyword {
+			entries = append(entries, mapEntry{key: keyword[0], value: keyword[1]})
+		} else {
+			if p.peek() != '=' || p.pos+1 >= len(p.input) || p.input[p.pos+1] != '>' {
+				return nil, p.errorf("expected => in map")
+			}
+			p.pos += 2
+			value, err := p.term()
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, mapEntry{key: element, value: value})
+		}
+		if err := p.separator('}'); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes the comma between elements, or checks the closing rune follows
+func (p *termParser) separator(closing rune) error {
+	switch p.peek() {
+	case ',':
+		p.pos++
+		return nil
+	case closing:
+		return nil
+	default:
+		return p.errorf("expected , or %q", closing)
+	}
+}
+
+// keywordOrTerm parses a `key: value` or `"key": value` keyword entry as an {atom, value} tuple, or else a term.
+// isKeyword tells which one was parsed
+func (p *termParser) keywordOrTerm() (interface{}, error) {
+	p.isKeyword = false
+	start := p.pos
+	var key string
+	switch r := p.peek(); {
+	case r == '"':
+		quoted, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		key = quoted
+	case r != ':':
+		key = p.word()
+	}
+	if key == "" || p.pos >= len(p.input) || p.input[p.pos] != ':' {
+		p.pos = start
+		return p.term()
+	}
+
+	p.pos++
+	value, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	p.isKeyword = true
+	return tuple{atom(key), value}, nil
+}
+
+// word reads an identifier or the text of a number or other bare literal
+func (p *termParser) word() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.?!@-+", r)) {
+			break
+		}
+		p.pos++
+	}
+	return string(p.input[start:p.pos])
+}
+
+// str reads a double quoted string, decoding the common escapes
+func (p *termParser) str() (string, error) {
+	p.pos++ // opening quote
+	var value strings.Builder
+	for p.pos < len(p.input) {
+		r := p.input[p.pos]
+		p.pos++
+		switch r {
+		case '"':
+			return value.String(), nil
+		case '\\':
+			if p.pos >= len(p.input) {
+				return "", p.errorf("unterminated escape")
+			}
+			escaped := p.input[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				value.WriteRune('\n')
+			case 't':
+				value.WriteRune('\t')
+			default:
+				value.WriteRune(escaped)
+			}
+		default:
+			value.WriteRune(r)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
@@ -0,0 +1,31 @@
+defmodule Shop.MixProject do
+  use Mix.Project
+
+  @version "0.3.1"
+
+  def project do
+    [
+      app: :shop,
+      version: @version,
+      elixir: "~> 1.12",
+      start_permanent: Mix.env() == :prod,
+      deps: deps()
+    ]
+  end
+
+  def application do
+    [extra_applications: [:logger]]
+  end
+
+  # Run "mix help deps" to learn about dependencies.
+  defp deps do
+    [
+      {:plug_cowboy, "~> 2.5"},
+      {:jason, "~> 1.2"},
+      {:money, git: "https://github.com/example/money.git", tag: "v1.4.0"},
+      {:local_utils, path: "../local_utils"},
+      # {:commented, "~> 1.0"},
+      {:credo, "~> 1.6", only: [:dev, :test], runtime: false}
+    ]
+  end
+end
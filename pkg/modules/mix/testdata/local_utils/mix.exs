@@ -0,0 +1,7 @@
+defmodule LocalUtils.MixProject do
+  use Mix.Project
+
+  def project do
+    [app: :local_utils, version: "0.1.0", deps: []]
+  end
+end
@@ -10,14 +10,19 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/cabal"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/cargo"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/composer"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/conan"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/gem"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/gomod"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/javamaven"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/mix"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/npm"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/nuget"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/pip"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pnpm"
+	"github.com/spdx/spdx-sbom-generator/pkg/modules/pub"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/swift"
 	"github.com/spdx/spdx-sbom-generator/pkg/modules/yarn"
 )
@@ -26,22 +31,31 @@ var (
 	errNoPluginAvailable   = errors.New("no plugin system available for current path")
 	errNoModulesInstalled  = errors.New("there are no components in the BOM. The project may not contain dependencies, please install modules")
 	errFailedToReadModules = errors.New("failed to read modules")
+	errNoDeclaredView      = errors.New("plugin does not support listing declared modules")
+	errNoBuildEnvironment  = errors.New("plugin does not support describing the build environment")
+	errNoInventory         = errors.New("plugin does not support listing the module inventory")
+	errNoPreview           = errors.New("plugin does not support previewing its SBOM")
 )
 
 var registeredPlugins []models.IPlugin
 
 func init() {
 	registeredPlugins = append(registeredPlugins,
+		cabal.New(),
 		cargo.New(),
 		composer.New(),
+		conan.New(),
 		gomod.New(),
 		gem.New(),
 		npm.New(),
+		pnpm.New(),
 		javagradle.New(),
 		javamaven.New(),
+		mix.New(),
 		nuget.New(),
 		yarn.New(),
 		pip.New(),
+		pub.New(),
 		swift.New(),
 	)
 }
@@ -56,6 +70,8 @@ type Manager struct {
 // Config ...
 type Config struct {
 	Path string
+	// DryRun does not set the root module of the plugins, which may run the package manager
+	DryRun bool
 }
 
 // New ...
@@ -64,8 +80,10 @@ func New(cfg Config) ([]*Manager, error) {
 	var managerSlice []*Manager
 	for _, plugin := range registeredPlugins {
 		if plugin.IsValid(cfg.Path) {
-			if err := plugin.SetRootModule(cfg.Path); err != nil {
-				return nil, err
+			if !cfg.DryRun {
+				if err := plugin.SetRootModule(cfg.Path); err != nil {
+					return nil, err
+				}
 			}
 
 			usePlugin = plugin
@@ -111,3 +129,48 @@ func (m *Manager) Run() error {
 func (m *Manager) GetSource() []models.Module {
 	return m.modules
 }
+
+// GetDiagnostics returns the diagnostics reported by the plugin while reading its modules
+func (m *Manager) GetDiagnostics() []models.Diagnostic {
+	if plugin, ok := m.Plugin.(models.IDiagnosticsPlugin); ok {
+		return plugin.GetDiagnostics()
+	}
+	return nil
+}
+
+// GetDeclaredSource returns the modules as declared in the project manifest, for plugins supporting it
+func (m *Manager) GetDeclaredSource() ([]models.Module, error) {
+	plugin, ok := m.Plugin.(models.IDeclaredModulesPlugin)
+	if !ok {
+		return nil, errNoDeclaredView
+	}
+	return plugin.ListDeclaredModules(m.Config.Path)
+}
+
+// GetBuildEnvironment returns the build environment of the project, for plugins supporting it
+func (m *Manager) GetBuildEnvironment() ([]models.Property, error) {
+	plugin, ok := m.Plugin.(models.IBuildEnvironmentPlugin)
+	if !ok {
+		return nil, errNoBuildEnvironment
+	}
+	return plugin.GetBuildEnvironment(m.Config.Path)
+}
+
+// GetInventory returns the flat list of resolved modules, without their dependency graph, for plugins supporting it
+func (m *Manager) GetInventory() ([]models.Module, error) {
+	plugin, ok := m.Plugin.(models.IInventoryPlugin)
+	if !ok {
+		return nil, errNoInventory
+	}
+	return plugin.ListInventory(m.Config.Path)
+}
+
+// GetPreview returns the SBOM the plugin expects to generate, without running the package manager, for plugins
+// supporting it
+func (m *Manager) GetPreview() (models.Preview, error) {
+	plugin, ok := m.Plugin.(models.IPreviewPlugin)
+	if !ok {
+		return models.Preview{}, errNoPreview
+	}
+	return plugin.Preview(m.Config.Path)
+}
@@ -10,5 +10,7 @@ type errType error
 
 var (
 	errDependenciesNotFound errType = errors.New("unable to generate SPDX file, no modules founded. Please install them before running spdx-sbom-generator, e.g.: `npm install`")
-	errNoNpmCommand errType = errors.New("no npm command")
+	errNoNpmCommand         errType = errors.New("no npm command")
+	errFailedToReadLockFile errType = errors.New("failed to read the npm lockfile")
+	errUnsupportedLockFile  errType = errors.New("unsupported npm lockfile, the packages of lockfileVersion 2 or later are required")
 )
@@ -17,6 +17,7 @@ import (
 
 type npm struct {
 	metadata models.PluginMetadata
+	options  Options
 }
 
 var (
@@ -28,6 +29,11 @@ var (
 
 // New creates a new npm manager instance
 func New() *npm {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions creates a new npm manager instance tuned by options
+func NewWithOptions(options Options) *npm {
 	return &npm{
 		metadata: models.PluginMetadata{
 			Name:       "Node Package Manager",
@@ -35,6 +41,7 @@ func New() *npm {
 			Manifest:   []string{"package.json", lockFile},
 			ModulePath: []string{"node_modules"},
 		},
+		options: options,
 	}
 }
 
@@ -54,8 +61,12 @@ func (m *npm) IsValid(path string) bool {
 	return true
 }
 
-// HasModulesInstalled checks if modules of manifest file already installed
+// HasModulesInstalled checks if modules of manifest file already installed, which a lockfile of version 2 or later
+// does not require
 func (m *npm) HasModulesInstalled(path string) error {
+	if version, err := readLockFileVersion(filepath.Join(path, lockFile)); err == nil && version >= packagesLockfileVersion {
+		return nil
+	}
 	for _, p := range m.metadata.ModulePath {
 		if !helper.Exists(filepath.Join(path, p)) {
 			return errDependenciesNotFound
@@ -164,13 +175,22 @@ func (m *npm) ListUsedModules(path string) ([]models.Module, error) {
 	return modules, nil
 }
 
-// ListModulesWithDeps return all info of installed modules
+// ListModulesWithDeps return all info of installed modules. The lockfiles of version 2 or later are read on their
+// own, the packages they list need not be installed
 func (m *npm) ListModulesWithDeps(path string) ([]models.Module, error) {
 	pk := lockFile
 	if helper.Exists(filepath.Join(path, shrink)) {
 		pk = shrink
 	}
 
+	if version, err := readLockFileVersion(filepath.Join(path, pk)); err == nil && version >= packagesLockfileVersion {
+		root, err := m.GetRootModule(path)
+		if err != nil {
+			return nil, err
+		}
+		return readLockFile(filepath.Join(path, pk), *root, m.options)
+	}
+
 	r := reader.New(filepath.Join(path, pk))
 	pkResults, err := r.ReadJson()
 	if err != nil {
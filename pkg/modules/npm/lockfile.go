@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/licenses"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+const (
+	// packagesLockfileVersion is the first lockfile version listing the installed packages by their path
+	packagesLockfileVersion = 2
+	nodeModules             = "node_modules/"
+
+	devScope         = "dev"
+	optionalScope    = "optional"
+	devOptionalScope = "devOptional"
+)
+
+// LockFile is package-lock.json or npm-shrinkwrap.json. From version 2 on, packages lists the installed packages
+// keyed by their path, `""` being the root project, e.g. `node_modules/@scope/name` or
+// `node_modules/a/node_modules/b` for a nested one
+type LockFile struct {
+	Name            string                 `json:"name"`
+	Version         string                 `json:"version"`
+	LockfileVersion int                    `json:"lockfileVersion"`
+	Packages        map[string]LockPackage `json:"packages"`
+}
+
+// LockPackage is an installed package, or a link to a workspace package when Link is set
+type LockPackage struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Resolved             string            `json:"resolved"`
+	Integrity            string            `json:"integrity"`
+	License              json.RawMessage   `json:"license"`
+	Link                 bool              `json:"link"`
+	Dev                  bool              `json:"dev"`
+	Optional             bool              `json:"optional"`
+	DevOptional          bool              `json:"devOptional"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+}
+
+// readLockFileVersion returns the lockfileVersion of a lockfile
+func readLockFileVersion(file string) (int, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+	var lock LockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return 0, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	return lock.LockfileVersion, nil
+}
+
+// readLockFile reads the packages of a version 2 or 3 lockfile, after the root module in path order. An installed
+// package is listed once, whatever the number of paths it is installed at. Each package is linked to the
+// dependencies it requires, resolved as Node does from its own node_modules up to the root one. The dev and optional
+// packages have the corresponding scope and are omitted as the options tell
+func readLockFile(file string, root models.Module, options Options) ([]models.Module, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	var lock LockFile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	if lock.LockfileVersion < packagesLockfileVersion || len(lock.Packages) == 0 {
+		return nil, fmt.Errorf("%w: lockfileVersion %d", errUnsupportedLockFile, lock.LockfileVersion)
+	}
+
+	paths := make([]string, 0, len(lock.Packages))
+	for path, pkg := range lock.Packages {
+		if path != "" && !pkg.Link && !options.excludes(pkg) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	if root.PackageURL != "" {
+		root.PackageHomePage = root.PackageURL
+	}
+	root.Root = true
+	root.PackageURL = purl.BuildNPM(root.Name, root.Version)
+	modules := []models.Module{root}
+	index := map[string]int{"": 0}
+	installed := map[string]int{}
+	for _, path := range paths {
+		pkg := lock.Packages[path]
+		mod := convertLockPackage(filepath.Dir(file), path, pkg)
+		key := mod.Name + "@" + mod.Version
+		if i, ok := installed[key]; ok {
+			index[path] = i
+			continue
+		}
+		installed[key] = len(modules)
+		index[path] = len(modules)
+		modules = append(modules, mod)
+	}
+	// the links of the workspace packages resolve to the path of their target
+	for path, pkg := range lock.Packages {
+		if i, ok := index[pkg.Resolved]; ok && pkg.Link {
+			index[path] = i
+		}
+	}
+
+	for path, i := range index {
+		pkg := lock.Packages[path]
+		requires := []map[string]string{pkg.Dependencies, pkg.OptionalDependencies, pkg.PeerDependencies}
+		if path == "" || !strings.Contains(path, nodeModules) {
+			requires = append(requires, pkg.DevDependencies)
+		}
+		for _, deps := range requires {
+			for name := range deps {
+				if j, ok := resolveDependency(index, path, name); ok && j != i {
+					modules[i].Modules[name] = &modules[j]
+				}
+			}
+		}
+	}
+	return modules, nil
+}
+
+// resolveDependency returns the index of the package a dependency resolves to from the package at path, looking
+// into the node_modules of the package and then of its ancestors
+func resolveDependency(index map[string]int, path, name string) (int, bool) {
+	dir := path
+	for {
+		candidate := nodeModules + name
+		if dir != "" {
+			candidate = dir + "/" + candidate
+		}
+		if i, ok := index[candidate]; ok {
+			return i, true
+		}
+		if dir == "" {
+			return 0, false
+		}
+		// the top level and workspace packages resolve their dependencies from the root node_modules
+		if i := strings.LastIndex(dir, "/"+nodeModules); i >= 0 {
+			dir = dir[:i]
+		} else {
+			dir = ""
+		}
+	}
+}
+
+// convertLockPackage builds the module of an installed package, named after the last node_modules of its path
+// unless it is installed under an alias
+func convertLockPackage(dir, path string, pkg LockPackage) models.Module {
+	name := pkg.Name
+	if name == "" {
+		name = path
+		if i := strings.LastIndex(path, nodeModules); i >= 0 {
+			name = path[i+len(nodeModules):]
+		}
+	}
+	mod := models.Module{
+		Name:       name,
+		Version:    pkg.Version,
+		PackageURL: purl.BuildNPM(name, pkg.Version),
+		Supplier:   models.SupplierContact{Name: name},
+		Modules:    map[string]*models.Module{},
+	}
+	if strings.Contains(pkg.Resolved, "://") {
+		mod.PackageDownloadLocation = pkg.Resolved
+	}
+	if !strings.Contains(path, nodeModules) {
+		mod.LocalPath = filepath.Join(dir, filepath.FromSlash(path))
+	}
+	if checksum := helper.ParseIntegrity(pkg.Integrity); checksum != nil {
+		mod.CheckSum = checksum
+	}
+
+	switch {
+	case pkg.Dev:
+		mod.Scope = devScope
+	case pkg.Optional:
+		mod.Scope = optionalScope
+	case pkg.DevOptional:
+		mod.Scope = devOptionalScope
+	}
+
+	// the license is an SPDX identifier or expression, or a license name
+	var license string
+	if json.Unmarshal(pkg.License, &license) == nil {
+		if id, ok := licenses.Normalize(license); ok {
+			mod.LicenseDeclared = id
+		} else if _, err := licenses.ParseExpression(license); err == nil {
+			mod.LicenseDeclared = license
+		}
+	}
+	return mod
+}
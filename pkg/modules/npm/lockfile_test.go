@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package npm
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func findLockedModule(t *testing.T, modules []models.Module, name, version string) models.Module {
+	for _, mod := range modules {
+		if mod.Name == name && mod.Version == version {
+			return mod
+		}
+	}
+	t.Fatalf("module %s@%s not found", name, version)
+	return models.Module{}
+}
+
+func requiredNames(mod models.Module) []string {
+	names := make([]string, 0, len(mod.Modules))
+	for name, dep := range mod.Modules {
+		names = append(names, name+"@"+dep.Version)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestReadLockFileV2(t *testing.T) {
+	path := filepath.Join("test", "lockfile-v2")
+	assert.NoError(t, New().HasModulesInstalled(path))
+	modules, err := New().ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 9)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "lock-demo", root.Name)
+	assert.Equal(t, "pkg:npm/lock-demo@1.2.0", root.PackageURL)
+	assert.Equal(t, []string{"@babel/runtime@7.17.2", "express@4.17.3", "fsevents@2.3.2", "jest-util@27.5.1"}, requiredNames(root))
+
+	babel := findLockedModule(t, modules, "@babel/runtime", "7.17.2")
+	assert.Equal(t, "pkg:npm/%40babel/runtime@7.17.2", babel.PackageURL)
+	assert.Equal(t, "https://registry.npmjs.org/@babel/runtime/-/runtime-7.17.2.tgz", babel.PackageDownloadLocation)
+	sum := sha512.Sum512([]byte("babel-runtime"))
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: hex.EncodeToString(sum[:])}, babel.CheckSum)
+	assert.Equal(t, "MIT", babel.LicenseDeclared)
+	assert.Empty(t, babel.Scope)
+
+	// the strongest hash of the integrity is recorded
+	express := findLockedModule(t, modules, "express", "4.17.3")
+	assert.Equal(t, models.HashAlgoSHA512, express.CheckSum.Algorithm)
+	assert.Equal(t, []string{"debug@2.6.9", "ms@2.0.0"}, requiredNames(express))
+
+	// nested packages shadow the hoisted ones
+	jest := findLockedModule(t, modules, "jest-util", "27.5.1")
+	assert.Equal(t, devScope, jest.Scope)
+	assert.Equal(t, []string{"ms@2.1.3"}, requiredNames(jest))
+	assert.Equal(t, optionalScope, findLockedModule(t, modules, "fsevents", "2.3.2").Scope)
+}
+
+func TestReadLockFileV3(t *testing.T) {
+	path := filepath.Join("test", "lockfile-v3")
+	modules, err := New().ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 10)
+	assert.Contains(t, requiredNames(modules[0]), "@demo/shared@0.1.0")
+
+	// the workspace packages are linked from the root node_modules
+	shared := findLockedModule(t, modules, "@demo/shared", "0.1.0")
+	assert.Equal(t, "pkg:npm/%40demo/shared@0.1.0", shared.PackageURL)
+	assert.Equal(t, filepath.Join(path, "packages", "shared"), shared.LocalPath)
+	assert.Equal(t, "(MIT OR Apache-2.0)", shared.LicenseDeclared)
+	assert.Nil(t, shared.CheckSum)
+	assert.Equal(t, []string{"debug@2.6.9"}, requiredNames(shared))
+
+	// the dev and optional packages may be omitted
+	modules, err = NewWithOptions(Options{ExcludeDev: true, ExcludeOptional: true}).ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 7)
+	assert.Equal(t, []string{"@babel/runtime@7.17.2", "@demo/shared@0.1.0", "express@4.17.3"}, requiredNames(modules[0]))
+	for _, mod := range modules {
+		assert.Empty(t, mod.Scope, mod.Name)
+	}
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package npm
+
+// Options tunes how the npm plugin turns a project into modules
+type Options struct {
+	// ExcludeDev omits the packages only installed as dev dependencies, flagged `dev` in package-lock.json
+	ExcludeDev bool
+
+	// ExcludeOptional omits the packages only installed as optional dependencies, flagged `optional` in
+	// package-lock.json. The packages flagged `devOptional` are omitted when both kinds are excluded
+	ExcludeOptional bool
+}
+
+// excludes tells whether a locked package is omitted by the options
+func (o Options) excludes(pkg LockPackage) bool {
+	return (o.ExcludeDev && pkg.Dev) ||
+		(o.ExcludeOptional && pkg.Optional) ||
+		(o.ExcludeDev && o.ExcludeOptional && pkg.DevOptional)
+}
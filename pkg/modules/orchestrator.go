@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package modules
+
+import (
+	"path/filepath"
+	"sync"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// RunAll runs the managers concurrently, for polyglot projects where several package managers are detected, at
+// most concurrency of them at a time, all of them at once when it is not positive. The errors of the managers that
+// failed are returned keyed by plugin slug, the others having read their modules
+func RunAll(managers []*Manager, concurrency int) map[string]error {
+	if concurrency <= 0 || concurrency > len(managers) {
+		concurrency = len(managers)
+	}
+
+	errs := map[string]error{}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for _, manager := range managers {
+		wg.Add(1)
+		go func(manager *Manager) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			if err := manager.Run(); err != nil {
+				mu.Lock()
+				errs[manager.Plugin.GetMetadata().Slug] = err
+				mu.Unlock()
+			}
+		}(manager)
+	}
+	wg.Wait()
+	return errs
+}
+
+// MergeSources returns the modules of the managers as a single project, rooted at a module named after the project
+// directory. The root module of each manager is a dependency of it instead of a root, keyed by the plugin slug so
+// that ecosystems naming their root alike are both kept
+func MergeSources(path string, managers []*Manager) []models.Module {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	root := models.Module{
+		Name:      filepath.Base(path),
+		LocalPath: path,
+		Root:      true,
+		Modules:   map[string]*models.Module{},
+	}
+
+	modules := []models.Module{root}
+	for _, manager := range managers {
+		slug := manager.Plugin.GetMetadata().Slug
+		for _, mod := range manager.GetSource() {
+			if mod.Root {
+				mod.Root = false
+				dependency := mod
+				root.Modules[slug+":"+mod.Name] = &dependency
+			}
+			modules = append(modules, mod)
+		}
+	}
+	return modules
+}
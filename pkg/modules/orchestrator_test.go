@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package modules
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+// fakePlugin lists canned modules, tracking how many plugins list their modules at the same time
+type fakePlugin struct {
+	models.IPlugin
+	slug    string
+	modules []models.Module
+	err     error
+	running *concurrency
+}
+
+type concurrency struct {
+	mu      sync.Mutex
+	current int
+	max     int
+}
+
+func (c *concurrency) enter() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current++
+	if c.current > c.max {
+		c.max = c.current
+	}
+}
+
+func (c *concurrency) leave() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current--
+}
+
+func (f fakePlugin) GetMetadata() models.PluginMetadata {
+	return models.PluginMetadata{Slug: f.slug}
+}
+
+func (f fakePlugin) GetVersion() (string, error) {
+	return "1.0", nil
+}
+
+func (f fakePlugin) HasModulesInstalled(path string) error {
+	return nil
+}
+
+func (f fakePlugin) ListModulesWithDeps(path string) ([]models.Module, error) {
+	f.running.enter()
+	defer f.running.leave()
+	time.Sleep(50 * time.Millisecond)
+	return f.modules, f.err
+}
+
+func project(root string, dependencies ...string) []models.Module {
+	modules := []models.Module{{Name: root, Version: "1.0.0", Root: true, Modules: map[string]*models.Module{}}}
+	for _, name := range dependencies {
+		dependency := models.Module{Name: name, Version: "2.0.0", Modules: map[string]*models.Module{}}
+		modules[0].Modules[name] = &dependency
+		modules = append(modules, dependency)
+	}
+	return modules
+}
+
+func TestRunAllMergesEcosystems(t *testing.T) {
+	path := filepath.Join("testdata", "polyglot")
+	detected, err := New(Config{Path: path, DryRun: true})
+	assert.NoError(t, err)
+	var slugs []string
+	for _, manager := range detected {
+		slugs = append(slugs, manager.Plugin.GetMetadata().Slug)
+	}
+	sort.Strings(slugs)
+	assert.Equal(t, []string{"Java-Maven", "go-mod"}, slugs)
+
+	running := &concurrency{}
+	sources := map[string][]models.Module{
+		"Java-Maven": project("service", "guava", "slf4j-api"),
+		"go-mod":     project("service", "github.com/spf13/cobra"),
+	}
+	var managers []*Manager
+	for _, manager := range detected {
+		slug := manager.Plugin.GetMetadata().Slug
+		managers = append(managers, &Manager{Config: manager.Config, Plugin: fakePlugin{slug: slug, modules: sources[slug], running: running}})
+	}
+	managers = append(managers, &Manager{Config: Config{Path: path}, Plugin: fakePlugin{slug: "npm", err: errors.New("no lockfile"), running: running}})
+
+	errs := RunAll(managers, 2)
+	assert.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs["npm"], errFailedToReadModules), errs["npm"])
+	assert.Equal(t, 2, running.max, "the managers run concurrently within the bound")
+
+	merged := MergeSources(path, managers[:2])
+	assert.Len(t, merged, 6)
+	root := merged[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "polyglot", root.Name)
+	var dependencies []string
+	for key, dependency := range root.Modules {
+		assert.False(t, dependency.Root)
+		dependencies = append(dependencies, key)
+	}
+	sort.Strings(dependencies)
+	assert.Equal(t, []string{"Java-Maven:service", "go-mod:service"}, dependencies, "both ecosystems are covered")
+	var names []string
+	for _, mod := range merged[1:] {
+		assert.False(t, mod.Root)
+		names = append(names, mod.Name)
+	}
+	assert.ElementsMatch(t, []string{"service", "guava", "slf4j-api", "service", "github.com/spf13/cobra"}, names)
+
+	running = &concurrency{}
+	for i := range managers {
+		plugin := managers[i].Plugin.(fakePlugin)
+		plugin.running = running
+		managers[i].Plugin = plugin
+	}
+	RunAll(managers, 1)
+	assert.Equal(t, 1, running.max)
+}
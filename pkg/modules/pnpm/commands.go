@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+type command string
+
+var (
+	VersionCmd       command = "pnpm --version"
+	ManifestFileName string  = "package.json"
+	LockFileName     string  = "pnpm-lock.yaml"
+	NpmRegistry      string  = "https://registry.npmjs.org"
+)
+
+// Parse ...
+func (c command) Parse() []string {
+	cmd := strings.TrimSpace(string(c))
+	return strings.Fields(cmd)
+}
+
+func (m *pnpm) buildCmd(cmd command, path string) error {
+	cmdArgs := cmd.Parse()
+	if cmdArgs[0] != "pnpm" {
+		return errNoPnpmCommand
+	}
+
+	command := helper.NewCmd(helper.CmdOptions{
+		Name:      cmdArgs[0],
+		Args:      cmdArgs[1:],
+		Directory: path,
+	})
+
+	m.command = command
+
+	return command.Build()
+}
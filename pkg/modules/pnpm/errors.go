@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"errors"
+)
+
+type errType error
+
+var errDependenciesNotFound = errors.New("no pnpm-lock.yaml found. Please install the dependencies before running spdx-sbom-generator, e.g.: `pnpm install`")
+var errNoPnpmCommand = errors.New("no pnpm command")
+var errFailedToReadManifest errType = errors.New("failed to read package.json")
+var errFailedToReadLockFile errType = errors.New("failed to read pnpm-lock.yaml")
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type pnpm struct {
+	metadata models.PluginMetadata
+	command  *helper.Cmd
+}
+
+// New ...
+func New() *pnpm {
+	return &pnpm{
+		metadata: models.PluginMetadata{
+			Name:       "Performant Node Package Manager",
+			Slug:       "pnpm",
+			Manifest:   []string{ManifestFileName, LockFileName},
+			ModulePath: []string{"node_modules"},
+		},
+	}
+}
+
+// GetMetadata ...
+func (m *pnpm) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// IsValid checks both package.json and pnpm-lock.yaml exist, package.json alone being the manifest of the npm
+// and yarn projects too
+func (m *pnpm) IsValid(path string) bool {
+	for i := range m.metadata.Manifest {
+		if !helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasModulesInstalled checks the dependencies were resolved, pnpm-lock.yaml pinning them
+func (m *pnpm) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFileName)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// GetVersion ...
+func (m *pnpm) GetVersion() (string, error) {
+	if err := m.buildCmd(VersionCmd, "."); err != nil {
+		return "", err
+	}
+
+	return m.command.Output()
+}
+
+// SetRootModule ...
+func (m *pnpm) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule ...
+func (m *pnpm) GetRootModule(path string) (*models.Module, error) {
+	root, err := readRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// ListUsedModules ...
+func (m *pnpm) ListUsedModules(path string) ([]models.Module, error) {
+	return m.ListModulesWithDeps(path)
+}
+
+// ListModulesWithDeps reads the root module from package.json and the locked packages and workspaces from
+// pnpm-lock.yaml
+func (m *pnpm) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := readRootModule(path)
+	if err != nil {
+		return nil, err
+	}
+	return readLockFile(path, root)
+}
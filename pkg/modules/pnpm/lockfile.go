@@ -0,0 +1,354 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+const (
+	rootImporter  = "."
+	linkPrefix    = "link:"
+	devScope      = "dev"
+	optionalScope = "optional"
+)
+
+// The schemas of the package keys, told by the major lockfile version
+const (
+	// schemaV5 keys the packages as `/<name>/<version>[_<peers>]`
+	schemaV5 = 5
+	// schemaV6 keys the packages as `/<name>@<version>[(<peer>)...]`
+	schemaV6 = 6
+	// schemaV9 keys the snapshots as `<name>@<version>[(<peer>)...]` and the packages without their peers
+	schemaV9 = 9
+)
+
+// readRootModule reads the root module from package.json, its license from the license files of the project
+func readRootModule(path string) (models.Module, error) {
+	manifest, err := readManifest(path)
+	if err != nil {
+		return models.Module{}, err
+	}
+	root := manifestModule(path, manifest)
+	root.Root = true
+	if licensePkg, err := helper.GetLicenses(path); err == nil {
+		root.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
+		root.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
+		root.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
+		root.CommentsLicense = licensePkg.Comments
+	}
+	return root, nil
+}
+
+func readManifest(path string) (Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, ManifestFileName))
+	if err != nil {
+		return Manifest{}, fmt.Errorf("%w: %v", errFailedToReadManifest, err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("%w: %v", errFailedToReadManifest, err)
+	}
+	return manifest, nil
+}
+
+// manifestModule is the module of the project or of a workspace package, named after its directory when
+// package.json does not name it
+func manifestModule(path string, manifest Manifest) models.Module {
+	name := manifest.Name
+	if name == "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			name = filepath.Base(abs)
+		}
+	}
+	return models.Module{
+		Name:            name,
+		Version:         manifest.Version,
+		PackageURL:      purl.BuildNPM(name, manifest.Version),
+		PackageHomePage: manifest.Homepage,
+		Supplier:        models.SupplierContact{Name: name},
+		LocalPath:       path,
+		Modules:         map[string]*models.Module{},
+	}
+}
+
+// lockfileSchema returns the schema of the package keys of a lockfile version, e.g. `5.4` or `'9.0'`
+func lockfileSchema(version string) int {
+	major, err := strconv.Atoi(strings.SplitN(strings.TrimSpace(version), ".", 2)[0])
+	switch {
+	case err != nil || major <= schemaV5:
+		return schemaV5
+	case major < schemaV9:
+		return schemaV6
+	}
+	return schemaV9
+}
+
+// readLockFile reads the workspace packages and the locked packages of pnpm-lock.yaml, after the root module,
+// respectively in path and in key order. A package is listed once, whatever the versions of the peers it is locked
+// with. The importers are linked to their dependencies, the locked packages to their dependencies, optional
+// dependencies and peers
+func readLockFile(dir string, root models.Module) ([]models.Module, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, LockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	var lock LockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	schema := lockfileSchema(lock.LockfileVersion)
+
+	importers := lock.Importers
+	if len(importers) == 0 {
+		importers = map[string]Importer{rootImporter: lock.Importer}
+	}
+	snapshots := lock.Snapshots
+	if schema < schemaV9 {
+		snapshots = map[string]Snapshot{}
+		for key, pkg := range lock.Packages {
+			snapshots[key] = Snapshot{Dependencies: pkg.Dependencies, OptionalDependencies: pkg.OptionalDependencies}
+		}
+	}
+
+	modules := []models.Module{root}
+	index := map[string]int{importerKey(rootImporter): 0}
+	importerPaths := make([]string, 0, len(importers))
+	for importer := range importers {
+		if importer != rootImporter {
+			importerPaths = append(importerPaths, importer)
+		}
+	}
+	sort.Strings(importerPaths)
+	for _, importer := range importerPaths {
+		importerDir := filepath.Join(dir, filepath.FromSlash(importer))
+		manifest, _ := readManifest(importerDir)
+		index[importerKey(importer)] = len(modules)
+		modules = append(modules, manifestModule(importerDir, manifest))
+	}
+
+	keys := make([]string, 0, len(snapshots))
+	for key := range snapshots {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	locked := map[string]int{}
+	for _, key := range keys {
+		name, version := parsePackageKey(key, schema)
+		if name == "" {
+			continue
+		}
+		pkg := lock.Packages[packageKey(key, schema)]
+		mod := convertLockPackage(dir, name, version, pkg)
+		id := mod.Name + "@" + mod.Version
+		if i, ok := locked[id]; ok {
+			index[key] = i
+			continue
+		}
+		locked[id] = len(modules)
+		index[key] = len(modules)
+		modules = append(modules, mod)
+	}
+
+	resolve := func(name, ref, from string) (int, bool) {
+		if strings.HasPrefix(ref, linkPrefix) {
+			i, ok := index[importerKey(path.Clean(path.Join(from, strings.TrimPrefix(ref, linkPrefix))))]
+			return i, ok
+		}
+		key := dependencyKey(name, ref, schema)
+		if i, ok := index[key]; ok {
+			return i, true
+		}
+		// the peers of a package may be locked under another key
+		depName, version := parsePackageKey(key, schema)
+		i, ok := locked[depName+"@"+version]
+		return i, ok
+	}
+	link := func(i int, from string, dependencies ...map[string]string) {
+		for _, deps := range dependencies {
+			for name, ref := range deps {
+				if j, ok := resolve(name, ref, from); ok && j != i {
+					modules[i].Modules[name] = &modules[j]
+				}
+			}
+		}
+	}
+
+	for importer, deps := range importers {
+		link(index[importerKey(importer)], importer, deps.Dependencies, deps.DevDependencies, deps.OptionalDependencies)
+	}
+	for _, key := range keys {
+		i, ok := index[key]
+		if !ok {
+			continue
+		}
+		snapshot := snapshots[key]
+		link(i, rootImporter, snapshot.Dependencies, snapshot.OptionalDependencies)
+
+		// the peers not listed as dependencies are resolved by the versions the package is locked with
+		peers := map[string]string{}
+		resolvedPeers := parsePeers(key, schema)
+		for name := range lock.Packages[packageKey(key, schema)].PeerDependencies {
+			if _, listed := snapshot.Dependencies[name]; !listed && resolvedPeers[name] != "" {
+				peers[name] = resolvedPeers[name]
+			}
+		}
+		link(i, rootImporter, peers)
+	}
+	return modules, nil
+}
+
+func importerKey(importer string) string {
+	return "importer:" + importer
+}
+
+// packageKey returns the key of the package metadata of a snapshot, the one of the version 9 packages omitting
+// the peers
+func packageKey(key string, schema int) string {
+	if schema >= schemaV9 {
+		return strings.SplitN(key, "(", 2)[0]
+	}
+	return key
+}
+
+// dependencyKey returns the key of the package a dependency reference resolves to. The reference is the version
+// of the package, or the key of another package for an aliased dependency
+func dependencyKey(name, ref string, schema int) string {
+	switch schema {
+	case schemaV5:
+		if strings.HasPrefix(ref, "/") {
+			return ref
+		}
+		return "/" + name + "/" + ref
+	case schemaV6:
+		if strings.HasPrefix(ref, "/") {
+			return ref
+		}
+		return "/" + name + "@" + ref
+	}
+	if base := strings.SplitN(ref, "(", 2)[0]; strings.Contains(strings.TrimPrefix(base, "@"), "@") {
+		return ref
+	}
+	return name + "@" + ref
+}
+
+// parsePackageKey returns the name and the version of the package of a key, without its peers
+func parsePackageKey(key string, schema int) (string, string) {
+	key = strings.TrimPrefix(key, "/")
+	if schema == schemaV5 {
+		segments := strings.Split(key, "/")
+		n := 1
+		if strings.HasPrefix(key, "@") {
+			n = 2
+		}
+		if len(segments) != n+1 {
+			return "", ""
+		}
+		return strings.Join(segments[:n], "/"), strings.SplitN(segments[n], "_", 2)[0]
+	}
+	base := strings.SplitN(key, "(", 2)[0]
+	i := strings.LastIndex(base, "@")
+	if i <= 0 {
+		return "", ""
+	}
+	return base[:i], base[i+1:]
+}
+
+// parsePeers returns the versions of the peers a package key is locked with, e.g. `react@17.0.2` in
+// `/react-dom/17.0.2_react@17.0.2` or `react-dom@17.0.2(react@17.0.2)`. The scoped peers of the version 5 keys
+// are written `@scope+name`
+func parsePeers(key string, schema int) map[string]string {
+	peers := map[string]string{}
+	add := func(peer string) {
+		base := strings.SplitN(peer, "(", 2)[0]
+		if i := strings.LastIndex(base, "@"); i > 0 {
+			peers[base[:i]] = peer[i+1:]
+		}
+	}
+
+	if schema == schemaV5 {
+		parts := strings.SplitN(strings.TrimPrefix(key, "/"), "_", 2)
+		if len(parts) < 2 {
+			return peers
+		}
+		tokens := strings.Split(parts[1], "+")
+		for i := 0; i < len(tokens); i++ {
+			if strings.HasPrefix(tokens[i], "@") && !strings.Contains(tokens[i][1:], "@") && i+1 < len(tokens) {
+				tokens[i+1] = tokens[i] + "/" + tokens[i+1]
+				continue
+			}
+			add(tokens[i])
+		}
+		return peers
+	}
+
+	depth, start := 0, -1
+	for i, r := range key {
+		switch r {
+		case '(':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && start >= 0 {
+				add(key[start:i])
+			}
+		}
+	}
+	return peers
+}
+
+// convertLockPackage builds the module of a locked package, located by its resolution. The registry packages are
+// checksummed by their integrity
+func convertLockPackage(dir, name, version string, pkg LockPackage) models.Module {
+	if pkg.Name != "" {
+		name = pkg.Name
+	}
+	if pkg.Version != "" {
+		version = pkg.Version
+	}
+	mod := models.Module{
+		Name:       name,
+		Version:    version,
+		PackageURL: purl.BuildNPM(name, version),
+		Supplier:   models.SupplierContact{Name: name},
+		Modules:    map[string]*models.Module{},
+	}
+	switch {
+	case pkg.Dev:
+		mod.Scope = devScope
+	case pkg.Optional:
+		mod.Scope = optionalScope
+	}
+
+	resolution := pkg.Resolution
+	switch {
+	case resolution.Directory != "":
+		mod.LocalPath = filepath.Join(dir, filepath.FromSlash(resolution.Directory))
+	case resolution.Repo != "":
+		mod.PackageDownloadLocation = "git+" + resolution.Repo + "@" + resolution.Commit
+	case resolution.Tarball != "":
+		mod.PackageDownloadLocation = resolution.Tarball
+	default:
+		mod.PackageDownloadLocation = fmt.Sprintf("%s/%s/-/%s-%s.tgz", NpmRegistry, name, path.Base(name), version)
+	}
+	if checksum := helper.ParseIntegrity(resolution.Integrity); checksum != nil {
+		mod.CheckSum = checksum
+	}
+	return mod
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func findModule(t *testing.T, modules []models.Module, name string) models.Module {
+	for _, mod := range modules {
+		if mod.Name == name {
+			return mod
+		}
+	}
+	t.Fatalf("module %s not found", name)
+	return models.Module{}
+}
+
+func requiredNames(mod models.Module) []string {
+	names := make([]string, 0, len(mod.Modules))
+	for name, dep := range mod.Modules {
+		names = append(names, name+"@"+dep.Version)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func integrityChecksum(content string) *models.CheckSum {
+	sum := sha512.Sum512([]byte(content))
+	return &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: hex.EncodeToString(sum[:])}
+}
+
+func TestReadLockFileV5(t *testing.T) {
+	path := filepath.Join("testdata", "v5")
+	plugin := New()
+	assert.True(t, plugin.IsValid(path))
+	assert.NoError(t, plugin.HasModulesInstalled(path))
+
+	modules, err := plugin.ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 8)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "pkg:npm/pnpm-demo@1.0.0", root.PackageURL)
+	assert.Equal(t, []string{"@babel/runtime@7.17.2", "debug@4.3.3", "react-dom@17.0.2", "string-width-cjs@4.2.3"}, requiredNames(root))
+
+	babel := findModule(t, modules, "@babel/runtime")
+	assert.Equal(t, "pkg:npm/%40babel/runtime@7.17.2", babel.PackageURL)
+	assert.Equal(t, "https://registry.npmjs.org/@babel/runtime/-/runtime-7.17.2.tgz", babel.PackageDownloadLocation)
+	assert.Equal(t, integrityChecksum("babel"), babel.CheckSum)
+	assert.Equal(t, []string{"regenerator-runtime@0.13.9"}, requiredNames(babel))
+
+	reactDOM := findModule(t, modules, "react-dom")
+	assert.Equal(t, "17.0.2", reactDOM.Version)
+	assert.Equal(t, []string{"react@17.0.2"}, requiredNames(reactDOM))
+
+	debug := findModule(t, modules, "debug")
+	assert.Equal(t, devScope, debug.Scope)
+	assert.Equal(t, []string{"ms@2.1.2"}, requiredNames(debug))
+	assert.Equal(t, "pkg:npm/string-width@4.2.3", findModule(t, modules, "string-width").PackageURL)
+}
+
+func TestReadLockFileV6(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "v6"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 6)
+	assert.Equal(t, []string{"@demo/ui@0.3.0", "lodash@4.17.21"}, requiredNames(modules[0]))
+
+	// the workspace packages are the importers
+	ui := modules[1]
+	assert.Equal(t, "@demo/ui", ui.Name)
+	assert.Equal(t, filepath.Join("testdata", "v6", "packages", "ui"), ui.LocalPath)
+	assert.Equal(t, []string{"fsevents@2.3.2", "react-dom@17.0.2"}, requiredNames(ui))
+
+	assert.Equal(t, optionalScope, findModule(t, modules, "fsevents").Scope)
+	reactDOM := findModule(t, modules, "react-dom")
+	assert.Equal(t, integrityChecksum("react-dom"), reactDOM.CheckSum)
+	assert.Equal(t, []string{"react@17.0.2"}, requiredNames(reactDOM))
+}
+
+func TestReadLockFileV9(t *testing.T) {
+	modules, err := New().ListModulesWithDeps(filepath.Join("testdata", "v9"))
+	assert.NoError(t, err)
+	assert.Len(t, modules, 6)
+	assert.Equal(t, []string{"@demo/ui@0.4.0", "string-width-cjs@4.2.3"}, requiredNames(modules[0]))
+
+	ui := findModule(t, modules, "@demo/ui")
+	assert.Equal(t, []string{"left-pad@1.3.0", "react-dom@17.0.2", "react@17.0.2"}, requiredNames(ui))
+
+	// the metadata of the snapshots are the ones of their package
+	reactDOM := findModule(t, modules, "react-dom")
+	assert.Equal(t, integrityChecksum("react-dom"), reactDOM.CheckSum)
+	// the peers are resolved by the versions the snapshot is locked with
+	assert.Equal(t, []string{"react@17.0.2"}, requiredNames(reactDOM))
+
+	leftPad := findModule(t, modules, "left-pad")
+	assert.Equal(t, "1.3.0", leftPad.Version)
+	assert.Equal(t, "https://example.com/left-pad-1.3.0.tgz", leftPad.PackageDownloadLocation)
+	assert.Nil(t, leftPad.CheckSum)
+}
+
+func TestParsePackageKeys(t *testing.T) {
+	for _, tc := range []struct {
+		key     string
+		schema  int
+		name    string
+		version string
+		peers   map[string]string
+	}{
+		{"/react-dom/17.0.2_react@17.0.2", schemaV5, "react-dom", "17.0.2", map[string]string{"react": "17.0.2"}},
+		{"/@testing-library/react/12.1.2_@types+react@17.0.39+react@17.0.2", schemaV5, "@testing-library/react", "12.1.2",
+			map[string]string{"@types/react": "17.0.39", "react": "17.0.2"}},
+		{"/@babel/runtime@7.17.2", schemaV6, "@babel/runtime", "7.17.2", map[string]string{}},
+		{"use-sync@1.2.0(react-dom@17.0.2(react@17.0.2))(react@17.0.2)", schemaV9, "use-sync", "1.2.0",
+			map[string]string{"react-dom": "17.0.2(react@17.0.2)", "react": "17.0.2"}},
+	} {
+		name, version := parsePackageKey(tc.key, tc.schema)
+		assert.Equal(t, tc.name, name, tc.key)
+		assert.Equal(t, tc.version, version, tc.key)
+		assert.Equal(t, tc.peers, parsePeers(tc.key, tc.schema), tc.key)
+	}
+	assert.Equal(t, schemaV5, lockfileSchema("5.4"))
+	assert.Equal(t, schemaV6, lockfileSchema("6.0"))
+	assert.Equal(t, schemaV9, lockfileSchema("9.0"))
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pnpm
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest is the package.json of the project or of a workspace package
+type Manifest struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Homepage string `json:"homepage"`
+}
+
+// LockFile is pnpm-lock.yaml. The lockfiles of a single project before version 9 list its dependencies at the top
+// level, the others list them by importer, i.e. by the path of the workspace package. Since version 9 the
+// resolved dependencies of the packages are listed by snapshots, packages only holding their metadata
+type LockFile struct {
+	LockfileVersion string `yaml:"lockfileVersion"`
+	Importer        `yaml:",inline"`
+	Importers       map[string]Importer    `yaml:"importers"`
+	Packages        map[string]LockPackage `yaml:"packages"`
+	Snapshots       map[string]Snapshot    `yaml:"snapshots"`
+}
+
+// Importer lists the dependencies of a workspace package, by their name
+type Importer struct {
+	Dependencies         DependencyRefs `yaml:"dependencies"`
+	DevDependencies      DependencyRefs `yaml:"devDependencies"`
+	OptionalDependencies DependencyRefs `yaml:"optionalDependencies"`
+}
+
+// LockPackage is a locked package, keyed by its path, e.g. `/react-dom/17.0.2_react@17.0.2` before version 6,
+// `/react-dom@17.0.2(react@17.0.2)` in version 6 and `react-dom@17.0.2` since version 9
+type LockPackage struct {
+	Resolution           Resolution        `yaml:"resolution"`
+	Name                 string            `yaml:"name"`
+	Version              string            `yaml:"version"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+	PeerDependencies     map[string]string `yaml:"peerDependencies"`
+	Dev                  bool              `yaml:"dev"`
+	Optional             bool              `yaml:"optional"`
+}
+
+// Resolution tells where a package comes from: the registry, identified by its integrity, a tarball, a git
+// repository or a local directory
+type Resolution struct {
+	Integrity string `yaml:"integrity"`
+	Tarball   string `yaml:"tarball"`
+	Repo      string `yaml:"repo"`
+	Commit    string `yaml:"commit"`
+	Directory string `yaml:"directory"`
+}
+
+// Snapshot is a package of a version 9 lockfile along with its resolved dependencies, keyed by the package and the
+// versions of its peers, e.g. `react-dom@17.0.2(react@17.0.2)`
+type Snapshot struct {
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+}
+
+// DependencyRefs maps the dependencies of an importer to their reference, i.e. the version of the locked package,
+// possibly suffixed by the versions of its peers, or a `link:` to a workspace package. The references are scalars
+// before version 6 and `{specifier, version}` mappings since
+type DependencyRefs map[string]string
+
+// UnmarshalYAML ...
+func (d *DependencyRefs) UnmarshalYAML(node *yaml.Node) error {
+	var refs map[string]yaml.Node
+	if err := node.Decode(&refs); err != nil {
+		return err
+	}
+	*d = DependencyRefs{}
+	for name, ref := range refs {
+		if ref.Kind == yaml.ScalarNode {
+			(*d)[name] = ref.Value
+			continue
+		}
+		var versioned struct {
+			Version string `yaml:"version"`
+		}
+		if err := ref.Decode(&versioned); err != nil {
+			return err
+		}
+		(*d)[name] = versioned.Version
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"strings"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+)
+
+type command string
+
+var (
+	VersionCmd       command = "dart --version"
+	PubspecFileName  string  = "pubspec.yaml"
+	LockFileName     string  = "pubspec.lock"
+	DefaultHostedURL string  = "https://pub.dev"
+)
+
+// Parse ...
+func (c command) Parse() []string {
+	cmd := strings.TrimSpace(string(c))
+	return strings.Fields(cmd)
+}
+
+func (m *pub) buildCmd(cmd command, path string) error {
+	cmdArgs := cmd.Parse()
+	if cmdArgs[0] != "dart" {
+		return errNoDartCommand
+	}
+
+	command := helper.NewCmd(helper.CmdOptions{
+		Name:      cmdArgs[0],
+		Args:      cmdArgs[1:],
+		Directory: path,
+	})
+
+	m.command = command
+
+	return command.Build()
+}
@@ -0,0 +1,14 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"errors"
+)
+
+type errType error
+
+var errDependenciesNotFound = errors.New("no pubspec.lock found. Please get the dependencies before running spdx-sbom-generator, e.g.: `dart pub get`")
+var errNoDartCommand = errors.New("no Dart command")
+var errFailedToReadPubspec errType = errors.New("failed to read pubspec.yaml")
+var errFailedToReadLockFile errType = errors.New("failed to read pubspec.lock")
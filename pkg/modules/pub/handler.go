@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"path/filepath"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+type pub struct {
+	metadata models.PluginMetadata
+	command  *helper.Cmd
+}
+
+// New ...
+func New() *pub {
+	return &pub{
+		metadata: models.PluginMetadata{
+			Name:       "Pub Package Manager",
+			Slug:       "Pub",
+			Manifest:   []string{PubspecFileName},
+			ModulePath: []string{".dart_tool"},
+		},
+	}
+}
+
+// GetMetadata ...
+func (m *pub) GetMetadata() models.PluginMetadata {
+	return m.metadata
+}
+
+// IsValid ...
+func (m *pub) IsValid(path string) bool {
+	for i := range m.metadata.Manifest {
+		if helper.Exists(filepath.Join(path, m.metadata.Manifest[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModulesInstalled checks the dependencies were resolved, pubspec.lock pinning them
+func (m *pub) HasModulesInstalled(path string) error {
+	if helper.Exists(filepath.Join(path, LockFileName)) {
+		return nil
+	}
+	return errDependenciesNotFound
+}
+
+// GetVersion ...
+func (m *pub) GetVersion() (string, error) {
+	if err := m.buildCmd(VersionCmd, "."); err != nil {
+		return "", err
+	}
+
+	return m.command.Output()
+}
+
+// SetRootModule ...
+func (m *pub) SetRootModule(path string) error {
+	return nil
+}
+
+// GetRootModule ...
+func (m *pub) GetRootModule(path string) (*models.Module, error) {
+	root, err := readPubspec(path)
+	if err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// ListUsedModules ...
+func (m *pub) ListUsedModules(path string) ([]models.Module, error) {
+	return m.ListModulesWithDeps(path)
+}
+
+// ListModulesWithDeps reads the root module from pubspec.yaml and the pinned dependencies from pubspec.lock
+func (m *pub) ListModulesWithDeps(path string) ([]models.Module, error) {
+	root, err := readPubspec(path)
+	if err != nil {
+		return nil, err
+	}
+	return readLockFile(path, root)
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+// Kinds of dependency recorded in pubspec.lock
+const (
+	dependencyDirectMain       = "direct main"
+	dependencyDirectDev        = "direct dev"
+	dependencyDirectOverridden = "direct overridden"
+	dependencyTransitive       = "transitive"
+)
+
+// Sources of the pinned packages
+const (
+	sourceHosted = "hosted"
+	sourceGit    = "git"
+	sourcePath   = "path"
+	sourceSDK    = "sdk"
+)
+
+const (
+	dependencyProperty = "dependency"
+	sourceProperty     = "source"
+	// devScope is the scope of the dev dependencies
+	devScope = "dev"
+)
+
+// readPubspec reads the root module from pubspec.yaml
+func readPubspec(path string) (models.Module, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, PubspecFileName))
+	if err != nil {
+		return models.Module{}, fmt.Errorf("%w: %v", errFailedToReadPubspec, err)
+	}
+	var pubspec Pubspec
+	if err := yaml.Unmarshal(data, &pubspec); err != nil {
+		return models.Module{}, fmt.Errorf("%w: %v", errFailedToReadPubspec, err)
+	}
+
+	root := models.Module{
+		Name:                    pubspec.Name,
+		Version:                 pubspec.Version,
+		Root:                    true,
+		PackageURL:              buildPurl(pubspec.Name, pubspec.Version),
+		PackageHomePage:         pubspec.Homepage,
+		PackageDownloadLocation: pubspec.Repository,
+		PackageComment:          strings.TrimSpace(pubspec.Description),
+		Supplier:                models.SupplierContact{Name: pubspec.Name},
+		LocalPath:               path,
+		Modules:                 map[string]*models.Module{},
+	}
+	if root.PackageHomePage == "" {
+		root.PackageHomePage = pubspec.Repository
+	}
+	if licensePkg, err := helper.GetLicenses(path); err == nil {
+		root.LicenseDeclared = helper.BuildLicenseDeclared(licensePkg.ID)
+		root.LicenseConcluded = helper.BuildLicenseConcluded(licensePkg.ID)
+		root.Copyright = helper.GetCopyright(licensePkg.ExtractedText)
+		root.CommentsLicense = licensePkg.Comments
+	}
+	return root, nil
+}
+
+// readLockFile reads the pinned packages of pubspec.lock, in name order after the root module. The lockfile does
+// not record the dependencies of each package, so only the direct ones are linked to the root module
+func readLockFile(path string, root models.Module) ([]models.Module, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, LockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	var lock LockFile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+
+	names := make([]string, 0, len(lock.Packages))
+	for name := range lock.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	modules := make([]models.Module, 0, len(names)+1)
+	modules = append(modules, root)
+	for _, name := range names {
+		modules = append(modules, convertLockPackage(path, name, lock.Packages[name]))
+	}
+	for i := 1; i < len(modules); i++ {
+		if modules[i].GetProperty(dependencyProperty) != dependencyTransitive {
+			modules[0].Modules[modules[i].Name] = &modules[i]
+		}
+	}
+	return modules, nil
+}
+
+// convertLockPackage builds the module of a pinned package, located according to its source
+func convertLockPackage(path, name string, pkg LockPackage) models.Module {
+	mod := models.Module{
+		Name:       name,
+		Version:    pkg.Version,
+		PackageURL: buildPurl(name, pkg.Version),
+		Supplier:   models.SupplierContact{Name: name},
+		Modules:    map[string]*models.Module{},
+	}
+	mod.SetProperty(dependencyProperty, pkg.Dependency)
+	mod.SetProperty(sourceProperty, pkg.Source)
+	if pkg.Dependency == dependencyDirectDev {
+		mod.Scope = devScope
+	}
+
+	description := pkg.Description
+	switch pkg.Source {
+	case sourceHosted:
+		hostedURL := strings.TrimSuffix(description.URL, "/")
+		if hostedURL == "" {
+			hostedURL = DefaultHostedURL
+		}
+		mod.PackageDownloadLocation = fmt.Sprintf("%s/packages/%s/versions/%s", hostedURL, url.PathEscape(name), url.PathEscape(pkg.Version))
+		mod.PackageHomePage = fmt.Sprintf("%s/packages/%s", hostedURL, url.PathEscape(name))
+		if description.Sha256 != "" {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: description.Sha256}
+		}
+	case sourceGit:
+		mod.PackageDownloadLocation = "git+" + description.URL
+		if description.ResolvedRef != "" {
+			mod.PackageDownloadLocation += "@" + description.ResolvedRef
+		}
+		if description.Path != "" && description.Path != "." {
+			mod.PackageDownloadLocation += "#" + description.Path
+		}
+	case sourcePath:
+		mod.LocalPath = description.Path
+		if description.Relative {
+			mod.LocalPath = filepath.Join(path, description.Path)
+		}
+	case sourceSDK:
+		mod.PackageComment = fmt.Sprintf("provided by the %s SDK", description.SDK)
+	}
+	return mod
+}
+
+// pubPurl builds the pub package urls, whose names are lowercase
+var pubPurl = purl.Builder{Type: "pub", Lowercase: true}
+
+// buildPurl returns the `pkg:pub/<name>@<version>` package url
+func buildPurl(name, version string) string {
+	return pubPurl.Build("", name, version, nil)
+}
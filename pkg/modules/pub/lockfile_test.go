@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"errors"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func TestListModulesWithDeps(t *testing.T) {
+	modules, err := New().ListModulesWithDeps("testdata")
+	assert.NoError(t, err)
+	assert.Len(t, modules, 7)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "weather_app", root.Name)
+	assert.Equal(t, "1.2.0+3", root.Version)
+	assert.Equal(t, "pkg:pub/weather_app@1.2.0+3", root.PackageURL)
+	assert.Equal(t, "https://example.com/weather_app", root.PackageHomePage)
+	assert.Equal(t, "https://github.com/example/weather_app", root.PackageDownloadLocation)
+
+	var direct []string
+	for name := range root.Modules {
+		direct = append(direct, name)
+	}
+	sort.Strings(direct)
+	assert.Equal(t, []string{"charts", "flutter", "http", "lints", "shared_models"}, direct, "the transitive async is not linked")
+
+	byName := map[string]models.Module{}
+	for _, module := range modules[1:] {
+		byName[module.Name] = module
+	}
+
+	async := byName["async"]
+	assert.Equal(t, dependencyTransitive, async.GetProperty(dependencyProperty))
+	assert.Equal(t, "pkg:pub/async@2.11.0", async.PackageURL)
+	assert.Equal(t, "https://pub.dev/packages/async/versions/2.11.0", async.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA256, Value: "947bfcf187f74dbc5e146c9eb9c0f10c9f8b30743e341481c1e2ed3ecc18c20c"}, async.CheckSum)
+
+	http := byName["http"]
+	assert.Equal(t, dependencyDirectMain, http.GetProperty(dependencyProperty))
+	assert.Equal(t, "https://pub.dartlang.org/packages/http/versions/0.13.5", http.PackageDownloadLocation)
+	assert.Nil(t, http.CheckSum, "older lockfiles record no sha256")
+
+	lints := byName["lints"]
+	assert.Equal(t, dependencyDirectDev, lints.GetProperty(dependencyProperty))
+	assert.Equal(t, devScope, lints.Scope)
+	assert.Equal(t, devScope, root.Modules["lints"].Scope)
+	assert.Empty(t, http.Scope)
+
+	charts := byName["charts"]
+	assert.Equal(t, sourceGit, charts.GetProperty(sourceProperty))
+	assert.Equal(t, "git+https://github.com/example/charts.git@5b4a7c9b0e1f2d3c4b5a69788796a5b4c3d2e1f0", charts.PackageDownloadLocation)
+	assert.Equal(t, "pkg:pub/charts@0.4.1", charts.PackageURL)
+
+	sharedModels := byName["shared_models"]
+	assert.Equal(t, sourcePath, sharedModels.GetProperty(sourceProperty))
+	assert.Equal(t, filepath.Join("testdata", "..", "shared_models"), sharedModels.LocalPath)
+	assert.Empty(t, sharedModels.PackageDownloadLocation)
+
+	flutter := byName["flutter"]
+	assert.Equal(t, sourceSDK, flutter.GetProperty(sourceProperty))
+	assert.Equal(t, "provided by the flutter SDK", flutter.PackageComment)
+}
+
+func TestReadMissingLockFile(t *testing.T) {
+	_, err := readLockFile(t.TempDir(), models.Module{})
+	assert.True(t, errors.Is(err, errFailedToReadLockFile))
+	assert.True(t, errors.Is(New().HasModulesInstalled(t.TempDir()), errDependenciesNotFound))
+}
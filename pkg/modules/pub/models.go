@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package pub
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// Pubspec is the root package metadata of pubspec.yaml
+type Pubspec struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	Homepage    string `yaml:"homepage"`
+	Repository  string `yaml:"repository"`
+}
+
+// LockFile is pubspec.lock, its packages keyed by name
+type LockFile struct {
+	Packages map[string]LockPackage `yaml:"packages"`
+}
+
+// LockPackage is a pinned package, `dependency` being `direct main`, `direct dev`, `direct overridden` or
+// `transitive`, and `source` being `hosted`, `git`, `path` or `sdk`
+type LockPackage struct {
+	Dependency  string      `yaml:"dependency"`
+	Description Description `yaml:"description"`
+	Source      string      `yaml:"source"`
+	Version     string      `yaml:"version"`
+}
+
+// Description locates a pinned package according to its source. It is a mapping for the hosted, git and path
+// sources, and the SDK name, e.g. `flutter`, for the sdk ones
+type Description struct {
+	Name        string `yaml:"name"`
+	URL         string `yaml:"url"`
+	Sha256      string `yaml:"sha256"`
+	Path        string `yaml:"path"`
+	Relative    bool   `yaml:"relative"`
+	Ref         string `yaml:"ref"`
+	ResolvedRef string `yaml:"resolved-ref"`
+	SDK         string `yaml:"-"`
+}
+
+// UnmarshalYAML decodes the mapping descriptions, and the scalar ones as the SDK name
+func (d *Description) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		d.SDK = value.Value
+		return nil
+	}
+	type description Description
+	return value.Decode((*description)(d))
+}
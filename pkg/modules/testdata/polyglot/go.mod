@@ -0,0 +1,3 @@
+module example.com/polyglot/tools
+
+go 1.15
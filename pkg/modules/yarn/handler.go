@@ -3,11 +3,8 @@
 package yarn
 
 import (
-	"bufio"
-	"crypto/sha256"
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -24,6 +21,7 @@ type yarn struct {
 
 var (
 	errDependenciesNotFound = errors.New("unable to generate SPDX file, no modules founded. Please install them before running spdx-sbom-generator, e.g.: `yarn install`")
+	errFailedToReadLockFile = errors.New("failed to read yarn.lock")
 	yarnRegistry            = "https://registry.yarnpkg.com"
 	lockFile                = "yarn.lock"
 	rg = regexp.MustCompile(`^(((git|hg|svn|bzr)\+)?(http:\/\/www\.|https:\/\/www\.|http:\/\/|https:\/\/|ssh:\/\/|git:\/\/|svn:\/\/|sftp:\/\/|ftp:\/\/)?[a-z0-9]+([\-\.]{1}[a-z0-9]+){0,100}\.[a-z]{2,5}(:[0-9]{1,5})?(\/.*))|(git\+git@[a-zA-Z0-9\.]+:[a-zA-Z0-9/\\.@]+)|(bzr\+lp:[a-zA-Z0-9\.]+)$`)
@@ -57,14 +55,9 @@ func (m *yarn) IsValid(path string) bool {
 	return true
 }
 
-// HasModulesInstalled checks if modules of manifest file already installed
+// HasModulesInstalled checks the manifest files exist, the modules are read from yarn.lock and need not be
+// installed
 func (m *yarn) HasModulesInstalled(path string) error {
-	for _, p := range m.metadata.ModulePath {
-		if !helper.Exists(filepath.Join(path, p)) {
-			return errDependenciesNotFound
-		}
-	}
-
 	for _, p := range m.metadata.Manifest {
 		if !helper.Exists(filepath.Join(path, p)) {
 			return errDependenciesNotFound
@@ -163,177 +156,62 @@ func (m *yarn) ListUsedModules(path string) ([]models.Module, error) {
 	return modules, nil
 }
 
-// ListModulesWithDeps return all info of installed modules
+// ListModulesWithDeps return all info of the modules resolved by yarn.lock, classic or Berry. The licenses,
+// copyrights and homepages are read from node_modules when the modules are installed
 func (m *yarn) ListModulesWithDeps(path string) ([]models.Module, error) {
-	deps, err := readLockFile(filepath.Join(path, lockFile))
-	allDeps := appendNestedDependencies(deps)
+	entries, berry, err := readLockFile(filepath.Join(path, lockFile))
 	if err != nil {
 		return nil, err
 	}
-
-	return m.buildDependencies(path, allDeps)
-}
-
-func (m *yarn) buildDependencies(path string, deps []dependency) ([]models.Module, error) {
-	modules := make([]models.Module, 0)
-	de, err := m.GetRootModule(path)
+	root, err := m.GetRootModule(path)
 	if err != nil {
-		return modules, err
-	}
-	h := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s-%s", de.Name, de.Version))))
-	de.CheckSum = &models.CheckSum{
-		Algorithm: "SHA256",
-		Value:     h,
-	}
-	de.Supplier.Name = de.Name
-	if de.PackageDownloadLocation == "" {
-		de.PackageDownloadLocation = de.Name
+		return nil, err
 	}
-	modules = append(modules, *de)
-	for _, d := range deps {
-		var mod models.Module
-		mod.Name = d.Name
-		mod.Version = extractVersion(d.Version)
-		modules[0].Modules[d.Name] = &models.Module{
-			Name:     d.Name,
-			Version:  mod.Version,
-			CheckSum: &models.CheckSum{Content: []byte(fmt.Sprintf("%s-%s", d.Name, mod.Version))},
-		}
-		if len(d.Dependencies) != 0 {
-			mod.Modules = map[string]*models.Module{}
-			for _, depD := range d.Dependencies {
-				ar := strings.Split(strings.TrimSpace(depD), " ")
-				name := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(ar[0], "\""), "\""), "@")
-				if name == "optionalDependencies:" {
-					continue
-				}
-
-				version := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(ar[1]), "\""), "\"")
-				if extractVersion(version) == "*" {
-					continue
-				}
-				mod.Modules[name] = &models.Module{
-					Name:     name,
-					Version:  extractVersion(version),
-					CheckSum: &models.CheckSum{Content: []byte(fmt.Sprintf("%s-%s", name, version))},
-				}
-			}
-		}
-		r := strings.TrimSuffix(strings.TrimPrefix(d.Resolved, "\""), "\"")
-		if strings.Index(r, "#") > 0 {
-			r = r[:strings.Index(r, "#")]
-			mod.PackageDownloadLocation = r
-		}
-		if mod.PackageDownloadLocation == "" {
-			r := "https://www.yarnpkg.com/package/%s"
-			mod.PackageDownloadLocation = fmt.Sprintf(r, mod.Name)
-		}
-		mod.Supplier.Name = mod.Name
+	root.Supplier.Name = root.Name
 
-		mod.PackageURL = getPackageHomepage(filepath.Join(path, m.metadata.ModulePath[0], d.PkPath, m.metadata.Manifest[0]))
-		h := fmt.Sprintf("%x", sha256.Sum256([]byte(mod.Name)))
-		mod.CheckSum = &models.CheckSum{
-			Algorithm: "SHA256",
-			Value:     h,
+	modules := buildLockModules(path, *root, readManifestDependencies(filepath.Join(path, m.metadata.Manifest[0])), entries, berry)
+	for i := 1; i < len(modules); i++ {
+		name := modules[i].Name
+		if alias := modules[i].GetProperty(aliasProperty); alias != "" {
+			name = alias
 		}
-		licensePath := filepath.Join(path, m.metadata.ModulePath[0], d.PkPath, "LICENSE")
-		if helper.Exists(licensePath) {
-			r := reader.New(licensePath)
-			s := r.StringFromFile()
-			mod.Copyright = helper.GetCopyright(s)
+		installed := filepath.Join(path, m.metadata.ModulePath[0], name)
+		if !helper.Exists(installed) {
+			continue
 		}
-
-		modLic, err := helper.GetLicenses(filepath.Join(path, m.metadata.ModulePath[0], d.PkPath))
+		modules[i].PackageHomePage = getPackageHomepage(filepath.Join(installed, m.metadata.Manifest[0]))
+		modules[i].Copyright = getCopyright(installed)
+		modLic, err := helper.GetLicenses(installed)
 		if err != nil {
-			modules = append(modules, mod)
 			continue
 		}
-		mod.LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
-		mod.LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
-		mod.CommentsLicense = modLic.Comments
+		modules[i].LicenseDeclared = helper.BuildLicenseDeclared(modLic.ID)
+		modules[i].LicenseConcluded = helper.BuildLicenseConcluded(modLic.ID)
+		modules[i].CommentsLicense = modLic.Comments
 		if !helper.LicenseSPDXExists(modLic.ID) {
-			mod.OtherLicense = append(mod.OtherLicense, modLic)
+			modules[i].OtherLicense = append(modules[i].OtherLicense, modLic)
 		}
-		modules = append(modules, mod)
 	}
 	return modules, nil
 }
 
-func readLockFile(path string) ([]dependency, error) {
-	file, err := os.Open(path)
+// readManifestDependencies returns the dependencies, dev and optional dependencies of package.json
+func readManifestDependencies(path string) map[string]string {
+	r := reader.New(path)
+	pkResult, err := r.ReadJson()
 	if err != nil {
-		return []dependency{}, err
+		return nil
 	}
-	defer file.Close()
-	p := make([]dependency, 0)
-	i := -1
-	scanner := bufio.NewScanner(file)
-
-	isPk := false
-	isDep := false
-	for scanner.Scan() {
-		text := scanner.Text()
-		if strings.HasPrefix(scanner.Text(), "#") {
-			continue
-		}
-		if strings.TrimSpace(text) == "" {
-			isPk = false
-			isDep = false
-			continue
-		}
-		if isDep {
-			p[i].Dependencies = append(p[i].Dependencies, text)
-			continue
-		}
-		if isPk {
-			if strings.HasPrefix(text, "  version ") {
-				p[i].Version = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(text, "  version "), "\""), "\"")
-				n := p[i].Name[:strings.Index(p[i].Name, "@")]
-				p[i].Name = n
-				p[i].PkPath = p[i].PkPath[:strings.LastIndex(p[i].PkPath, "@")]
-				continue
-			}
-			if strings.HasPrefix(text, "  resolved ") {
-				p[i].Resolved = strings.TrimPrefix(text, "  resolved ")
-				continue
-			}
-			if strings.HasPrefix(text, "  integrity ") {
-				p[i].Integrity = strings.TrimPrefix(text, "  integrity ")
-				continue
-			}
-			if strings.HasPrefix(text, "  dependencies:") {
-				isDep = true
-				continue
+	dependencies := map[string]string{}
+	for _, key := range []string{"dependencies", "devDependencies", "optionalDependencies"} {
+		deps, _ := pkResult[key].(map[string]interface{})
+		for name, descriptor := range deps {
+			if descriptor, ok := descriptor.(string); ok {
+				dependencies[name] = descriptor
 			}
 		}
-
-		if !strings.HasPrefix(scanner.Text(), "  ") {
-			isPk = true
-			i++
-			var dep dependency
-			name := text
-			name = strings.TrimSpace(name)
-			if strings.Contains(name, ",") {
-				s := strings.Split(name, ",")
-				name = s[0]
-			}
-			name = strings.TrimPrefix(name, "\"")
-			name = strings.TrimSuffix(name, ":")
-
-			dep.PkPath = strings.TrimSuffix(name, "\"")
-			name = strings.TrimPrefix(name, "@")
-
-			dep.Name = name
-			p = append(p, dep)
-			continue
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return []dependency{}, err
 	}
-
-	return p, nil
+	return dependencies
 }
 
 func getCopyright(path string) string {
@@ -357,6 +235,16 @@ func getCopyright(path string) string {
 	return ""
 }
 
+func getPackageVersion(path string) string {
+	r := reader.New(path)
+	pkResult, err := r.ReadJson()
+	if err != nil {
+		return ""
+	}
+	version, _ := pkResult["version"].(string)
+	return version
+}
+
 func getPackageHomepage(path string) string {
 	r := reader.New(path)
 	pkResult, err := r.ReadJson()
@@ -368,36 +256,3 @@ func getPackageHomepage(path string) string {
 	}
 	return ""
 }
-
-func extractVersion(s string) string {
-	t := strings.TrimPrefix(s, "^")
-	t = strings.TrimPrefix(t, "~")
-	t = strings.TrimPrefix(t, ">")
-	t = strings.TrimPrefix(t, "=")
-
-	t = strings.Split(t, " ")[0]
-	return t
-}
-
-func appendNestedDependencies(deps []dependency) []dependency {
-	allDeps := make([]dependency, 0)
-	for _, d := range deps {
-		allDeps = append(allDeps, d)
-		if len(d.Dependencies) > 0 {
-			for _, depD := range d.Dependencies {
-				ar := strings.Split(strings.TrimSpace(depD), " ")
-				name := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(ar[0], "\""), "\""), "@")
-				if name == "optionalDependencies:" {
-					continue
-				}
-
-				version := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(ar[1]), "\""), "\"")
-				if extractVersion(version) == "*" {
-					continue
-				}
-				allDeps = append(allDeps, dependency{Name: name, Version: extractVersion(version)})
-			}
-		}
-	}
-	return allDeps
-}
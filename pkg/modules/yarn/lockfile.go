@@ -0,0 +1,328 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/helper"
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+	"github.com/spdx/spdx-sbom-generator/pkg/purl"
+)
+
+const (
+	berryMetadataKey  = "__metadata"
+	npmProtocol       = "npm:"
+	workspaceProtocol = "workspace:"
+	aliasProperty     = "alias"
+	rootWorkspace     = "."
+)
+
+// localProtocols are the protocols of the packages read from the project rather than downloaded
+var localProtocols = []string{workspaceProtocol, "file:", "link:", "portal:"}
+
+// sha1Fragment matches the SHA-1 of the tarball ending the resolved url of a classic lockfile
+var sha1Fragment = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// readLockFile reads the entries of a classic (v1) or Berry (v2 and later) yarn.lock, the latter being told by its
+// __metadata entry
+func readLockFile(file string) ([]lockEntry, bool, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	if bytes.Contains(data, []byte("\n"+berryMetadataKey+":")) || bytes.HasPrefix(data, []byte(berryMetadataKey+":")) {
+		entries, err := readBerryLockFile(data)
+		return entries, true, err
+	}
+	entries, err := readClassicLockFile(data)
+	return entries, false, err
+}
+
+// readBerryLockFile reads the entries of a Berry lockfile, in key order
+func readBerryLockFile(data []byte) ([]lockEntry, error) {
+	var document map[string]berryEntry
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	keys := make([]string, 0, len(document))
+	for key := range document {
+		if key != berryMetadataKey {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	entries := make([]lockEntry, 0, len(keys))
+	for _, key := range keys {
+		entry := document[key]
+		dependencies := map[string]string{}
+		for _, deps := range []map[string]string{entry.Dependencies, entry.OptionalDependencies} {
+			for name, descriptor := range deps {
+				dependencies[name] = descriptor
+			}
+		}
+		entries = append(entries, lockEntry{
+			Descriptors:  splitDescriptors(key),
+			Version:      entry.Version,
+			Resolution:   entry.Resolution,
+			Checksum:     entry.Checksum,
+			Dependencies: dependencies,
+		})
+	}
+	return entries, nil
+}
+
+// readClassicLockFile reads the entries of a classic lockfile, in file order. Each entry starts with its unindented
+// descriptors, followed by its indented `field value` lines and `dependencies:` and `optionalDependencies:` sections
+func readClassicLockFile(data []byte) ([]lockEntry, error) {
+	var entries []lockEntry
+	var section string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case indent == 0:
+			entries = append(entries, lockEntry{
+				Descriptors:  splitDescriptors(strings.TrimSuffix(trimmed, ":")),
+				Dependencies: map[string]string{},
+			})
+			section = ""
+		case len(entries) == 0:
+			return nil, fmt.Errorf("%w: unexpected indented line %q", errFailedToReadLockFile, trimmed)
+		case indent == 2:
+			section = ""
+			if strings.HasSuffix(trimmed, ":") {
+				section = strings.TrimSuffix(trimmed, ":")
+				continue
+			}
+			key, value := splitClassicField(trimmed)
+			entry := &entries[len(entries)-1]
+			switch key {
+			case "version":
+				entry.Version = value
+			case "resolved":
+				entry.Resolved = value
+			case "integrity":
+				entry.Integrity = value
+			}
+		case section == "dependencies" || section == "optionalDependencies":
+			name, descriptor := splitClassicField(trimmed)
+			entries[len(entries)-1].Dependencies[name] = descriptor
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", errFailedToReadLockFile, err)
+	}
+	return entries, nil
+}
+
+// splitClassicField splits a `key value` line of a classic lockfile, either of them being possibly quoted
+func splitClassicField(line string) (string, string) {
+	var key string
+	if strings.HasPrefix(line, "\"") {
+		if end := strings.Index(line[1:], "\""); end >= 0 {
+			key, line = line[1:end+1], line[end+2:]
+		}
+	} else {
+		parts := strings.SplitN(line, " ", 2)
+		key, line = parts[0], ""
+		if len(parts) == 2 {
+			line = parts[1]
+		}
+	}
+	return key, strings.Trim(strings.TrimSpace(line), "\"")
+}
+
+// splitDescriptors splits the comma separated, possibly quoted descriptors of an entry key
+func splitDescriptors(key string) []string {
+	var descriptors []string
+	for _, descriptor := range strings.Split(key, ",") {
+		if descriptor = strings.Trim(strings.TrimSpace(descriptor), "\""); descriptor != "" {
+			descriptors = append(descriptors, descriptor)
+		}
+	}
+	return descriptors
+}
+
+// splitDescriptor splits a `<name>@<range>` descriptor or `<name>@<reference>` locator, the name being possibly
+// scoped
+func splitDescriptor(descriptor string) (string, string) {
+	i := strings.Index(strings.TrimPrefix(descriptor, "@"), "@")
+	if i < 0 {
+		return descriptor, ""
+	}
+	if strings.HasPrefix(descriptor, "@") {
+		i++
+	}
+	return descriptor[:i], descriptor[i+1:]
+}
+
+// packageName returns the name of the package an entry resolves to, i.e. the target of an aliased descriptor such as
+// `string-width-cjs@npm:string-width@^4.2.0`
+func packageName(entry lockEntry) string {
+	if entry.Resolution != "" {
+		name, _ := splitDescriptor(entry.Resolution)
+		return name
+	}
+	name, reference := splitDescriptor(entry.Descriptors[0])
+	if target := strings.TrimPrefix(reference, npmProtocol); target != reference && strings.Contains(strings.TrimPrefix(target, "@"), "@") {
+		name, _ = splitDescriptor(target)
+	}
+	return name
+}
+
+// normalizeDescriptor returns the descriptor of a dependency as written in the entry keys, the Berry lockfiles
+// prefixing the npm ranges with their protocol
+func normalizeDescriptor(name, descriptor string, berry bool) string {
+	if berry && !strings.Contains(descriptor, ":") {
+		descriptor = npmProtocol + descriptor
+	}
+	return name + "@" + descriptor
+}
+
+// buildLockModules builds the modules of the lockfile entries, after the root module in name and version order. A
+// package is listed once, whatever the number of entries resolving to it, e.g. once patched. The modules are linked
+// to the dependencies of their entry, and the root module to the dependencies of the root workspace of a Berry
+// lockfile or else of package.json
+func buildLockModules(dir string, root models.Module, rootDependencies map[string]string, entries []lockEntry, berry bool) []models.Module {
+	if root.PackageURL != "" {
+		root.PackageHomePage = root.PackageURL
+	}
+	root.Root = true
+	root.PackageURL = purl.BuildNPM(root.Name, root.Version)
+
+	rootEntry := -1
+	keys := make([]string, 0, len(entries))
+	converted := map[string]models.Module{}
+	entryKeys := make([]string, len(entries))
+	for i, entry := range entries {
+		if len(entry.Descriptors) == 0 {
+			continue
+		}
+		_, reference := splitDescriptor(entry.Resolution)
+		if reference == workspaceProtocol+rootWorkspace {
+			rootEntry = i
+			continue
+		}
+		mod := convertLockEntry(dir, entry, berry)
+		key := mod.Name + "@" + mod.Version
+		if _, ok := converted[key]; !ok {
+			keys = append(keys, key)
+			converted[key] = mod
+		}
+		entryKeys[i] = key
+	}
+	sort.Strings(keys)
+
+	modules := []models.Module{root}
+	index := map[string]int{}
+	for _, key := range keys {
+		index[key] = len(modules)
+		modules = append(modules, converted[key])
+	}
+	descriptors := map[string]int{}
+	for i, entry := range entries {
+		for _, descriptor := range entry.Descriptors {
+			if i == rootEntry {
+				descriptors[descriptor] = 0
+			} else if j, ok := index[entryKeys[i]]; ok {
+				descriptors[descriptor] = j
+			}
+		}
+	}
+
+	link := func(i int, dependencies map[string]string) {
+		for name, descriptor := range dependencies {
+			if j, ok := descriptors[normalizeDescriptor(name, descriptor, berry)]; ok && j != i {
+				modules[i].Modules[name] = &modules[j]
+			}
+		}
+	}
+	if rootEntry >= 0 {
+		link(0, entries[rootEntry].Dependencies)
+	} else {
+		link(0, rootDependencies)
+	}
+	for i, entry := range entries {
+		if j, ok := index[entryKeys[i]]; ok && i != rootEntry {
+			link(j, entry.Dependencies)
+		}
+	}
+	return modules
+}
+
+// convertLockEntry builds the module of a lockfile entry. The classic entries are checksummed by their integrity,
+// or else the SHA-1 fragment of their tarball url, and the Berry ones by the SHA-512 of their archive
+func convertLockEntry(dir string, entry lockEntry, berry bool) models.Module {
+	name := packageName(entry)
+	mod := models.Module{
+		Name:       name,
+		Version:    entry.Version,
+		PackageURL: purl.BuildNPM(name, entry.Version),
+		Supplier:   models.SupplierContact{Name: name},
+		Modules:    map[string]*models.Module{},
+	}
+	if alias, _ := splitDescriptor(entry.Descriptors[0]); alias != name {
+		mod.SetProperty(aliasProperty, alias)
+	}
+
+	_, reference := splitDescriptor(entry.Resolution)
+	if reference == "" {
+		_, reference = splitDescriptor(entry.Descriptors[0])
+	}
+	for _, protocol := range localProtocols {
+		if strings.HasPrefix(reference, protocol) {
+			local := strings.TrimPrefix(reference, protocol)
+			mod.LocalPath = filepath.Join(dir, filepath.FromSlash(strings.SplitN(local, "#", 2)[0]))
+			// the Berry lockfiles do not version the workspaces
+			if version := getPackageVersion(filepath.Join(mod.LocalPath, "package.json")); protocol == workspaceProtocol && version != "" {
+				mod.Version = version
+				mod.PackageURL = purl.BuildNPM(name, version)
+			}
+			return mod
+		}
+	}
+
+	if berry {
+		if strings.HasPrefix(reference, npmProtocol) {
+			mod.PackageDownloadLocation = fmt.Sprintf("%s/%s/-/%s-%s.tgz", yarnRegistry, name, path.Base(name), entry.Version)
+		}
+		// since cache key 8 the checksums are prefixed, e.g. `10c0/<sha512>`
+		checksum := entry.Checksum[strings.LastIndex(entry.Checksum, "/")+1:]
+		if _, err := hex.DecodeString(checksum); err == nil && len(checksum) == 128 {
+			mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: checksum}
+		}
+		return mod
+	}
+
+	// the fragment of a tarball url is the SHA-1 of the tarball, the one of a git url its commit
+	mod.PackageDownloadLocation = entry.Resolved
+	var fragment string
+	if i := strings.LastIndex(entry.Resolved, "#"); i >= 0 && !strings.HasPrefix(entry.Resolved, "git") {
+		mod.PackageDownloadLocation, fragment = entry.Resolved[:i], entry.Resolved[i+1:]
+	}
+	if checksum := helper.ParseIntegrity(entry.Integrity); checksum != nil {
+		mod.CheckSum = checksum
+	} else if sha1Fragment.MatchString(fragment) {
+		mod.CheckSum = &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: fragment}
+	}
+	return mod
+}
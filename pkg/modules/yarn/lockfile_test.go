@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package yarn
+
+import (
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spdx/spdx-sbom-generator/pkg/models"
+)
+
+func findLockedModule(t *testing.T, modules []models.Module, name string) models.Module {
+	for _, mod := range modules {
+		if mod.Name == name {
+			return mod
+		}
+	}
+	t.Fatalf("module %s not found", name)
+	return models.Module{}
+}
+
+func requiredNames(mod models.Module) []string {
+	names := make([]string, 0, len(mod.Modules))
+	for name, dep := range mod.Modules {
+		names = append(names, name+"@"+dep.Version)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sha512Hex(content string) string {
+	sum := sha512.Sum512([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestReadClassicLockFile(t *testing.T) {
+	path := filepath.Join("test", "classic")
+	assert.NoError(t, New().HasModulesInstalled(path))
+	modules, err := New().ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 9)
+
+	root := modules[0]
+	assert.True(t, root.Root)
+	assert.Equal(t, "pkg:npm/yarn-demo@2.0.0", root.PackageURL)
+	assert.Equal(t, []string{"@babel/runtime@7.17.2", "debug@4.3.3", "left-pad@1.3.0", "local-utils@0.0.1", "string-width-cjs@4.2.3"}, requiredNames(root))
+
+	babel := findLockedModule(t, modules, "@babel/runtime")
+	assert.Equal(t, "pkg:npm/%40babel/runtime@7.17.2", babel.PackageURL)
+	assert.Equal(t, "https://registry.yarnpkg.com/@babel/runtime/-/runtime-7.17.2.tgz", babel.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: sha512Hex("babel")}, babel.CheckSum)
+	assert.Equal(t, []string{"regenerator-runtime@0.13.9"}, requiredNames(babel))
+
+	// without integrity the SHA-1 of the tarball url is recorded
+	sum := sha1.Sum([]byte("left-pad"))
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA1, Value: hex.EncodeToString(sum[:])}, findLockedModule(t, modules, "left-pad").CheckSum)
+
+	// an aliased package is named after its target
+	width := findLockedModule(t, modules, "string-width")
+	assert.Equal(t, "pkg:npm/string-width@4.2.3", width.PackageURL)
+	assert.Equal(t, "string-width-cjs", width.GetProperty(aliasProperty))
+	assert.Equal(t, []string{"emoji-regex@8.0.0"}, requiredNames(width))
+	assert.Same(t, root.Modules["string-width-cjs"], &modules[8])
+
+	// the commit of a git dependency is kept
+	emoji := findLockedModule(t, modules, "emoji-regex")
+	assert.Contains(t, emoji.PackageDownloadLocation, "git+https://github.com/mathiasbynens/emoji-regex.git#")
+	assert.Nil(t, emoji.CheckSum)
+
+	local := findLockedModule(t, modules, "local-utils")
+	assert.Equal(t, filepath.Join(path, "local-utils"), local.LocalPath)
+	assert.Empty(t, local.PackageDownloadLocation)
+}
+
+func TestReadBerryLockFile(t *testing.T) {
+	path := filepath.Join("test", "berry")
+	modules, err := New().ListModulesWithDeps(path)
+	assert.NoError(t, err)
+	assert.Len(t, modules, 6)
+
+	root := modules[0]
+	assert.Equal(t, "berry-demo", root.Name)
+	assert.Equal(t, []string{"@babel/runtime@7.17.2", "@demo/shared@0.4.0", "resolve@1.22.0"}, requiredNames(root))
+
+	babel := findLockedModule(t, modules, "@babel/runtime")
+	assert.Equal(t, "https://registry.yarnpkg.com/@babel/runtime/-/runtime-7.17.2.tgz", babel.PackageDownloadLocation)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: sha512Hex("babel")}, babel.CheckSum)
+	assert.Equal(t, []string{"regenerator-runtime@0.13.9"}, requiredNames(babel))
+
+	// the workspaces are versioned by their package.json
+	shared := findLockedModule(t, modules, "@demo/shared")
+	assert.Equal(t, "pkg:npm/%40demo/shared@0.4.0", shared.PackageURL)
+	assert.Equal(t, filepath.Join(path, "packages", "shared"), shared.LocalPath)
+	assert.Nil(t, shared.CheckSum)
+	assert.Equal(t, []string{"string-width-cjs@4.2.3"}, requiredNames(shared))
+
+	width := findLockedModule(t, modules, "string-width")
+	assert.Equal(t, "string-width-cjs", width.GetProperty(aliasProperty))
+
+	// the patched package is listed once
+	resolve := findLockedModule(t, modules, "resolve")
+	assert.Equal(t, sha512Hex("resolve"), resolve.CheckSum.Value)
+}
+
+func TestIntegrityOptions(t *testing.T) {
+	sum := sha512.Sum512([]byte("debug"))
+	entry := lockEntry{
+		Descriptors: []string{"debug@^4.3.1"},
+		Version:     "4.3.3",
+		Resolved:    "https://registry.yarnpkg.com/debug/-/debug-4.3.3.tgz#32faaecac742100f7753f0c1d0aa0add01b4046b",
+		Integrity:   "sha512-" + base64.StdEncoding.EncodeToString(sum[:]) + "?foo",
+	}
+
+	// the options following the digest are left out, the integrity winning over the SHA-1 of the tarball url
+	mod := convertLockEntry("", entry, false)
+	assert.Equal(t, &models.CheckSum{Algorithm: models.HashAlgoSHA512, Value: sha512Hex("debug")}, mod.CheckSum)
+}
@@ -2,11 +2,29 @@
 
 package yarn
 
-type dependency struct {
-	Name         string
-	PkPath       string
-	Version      string
-	Resolved     string
-	Integrity    string
-	Dependencies []string
+// lockEntry is an entry of yarn.lock: the package resolved for the descriptors, `<name>@<range>`, it is the
+// resolution of
+type lockEntry struct {
+	Descriptors []string
+	Version     string
+	// Resolved is the tarball url of a classic lockfile, fragmented by the SHA-1 of the tarball
+	Resolved string
+	// Integrity is the subresource integrity string of a classic lockfile, e.g. `sha512-<base64>`
+	Integrity string
+	// Resolution is the locator of a Berry lockfile, e.g. `react@npm:16.14.0` or `app@workspace:packages/app`
+	Resolution string
+	// Checksum is the SHA-512 of the package archive of a Berry lockfile, prefixed by the cache key since version 8
+	Checksum     string
+	Dependencies map[string]string
+}
+
+// berryEntry is an entry of a Berry lockfile, a YAML document
+type berryEntry struct {
+	Version              string            `yaml:"version"`
+	Resolution           string            `yaml:"resolution"`
+	Checksum             string            `yaml:"checksum"`
+	Dependencies         map[string]string `yaml:"dependencies"`
+	OptionalDependencies map[string]string `yaml:"optionalDependencies"`
+	PeerDependencies     map[string]string `yaml:"peerDependencies"`
+	LinkType             string            `yaml:"linkType"`
 }
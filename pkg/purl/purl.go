@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package purl
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Builder builds the package urls of an ecosystem, see https://github.com/package-url/purl-spec. Each decoder
+// configures one with its purl type and the qualifiers it records, so the package urls of every ecosystem are
+// escaped and ordered alike
+type Builder struct {
+	// Type is the purl type, e.g. `maven` or `npm`
+	Type string
+	// Qualifiers are the qualifier keys of the ecosystem, e.g. `classifier` and `type` for Maven. Others are left out
+	Qualifiers []string
+	// Lowercase lowercases the namespace and name, for the ecosystems whose names are case insensitive
+	Lowercase bool
+}
+
+// Build returns the `pkg:<type>/[<namespace>/]<name>[@<version>][?<qualifiers>]` package url, nothing without a
+// name. The namespace segments, name and version are percent-encoded, `@` included. The qualifiers without a value
+// are left out and the others sorted by key, their values percent-encoded
+func (b Builder) Build(namespace, name, version string, qualifiers map[string]string) string {
+	if name == "" {
+		return ""
+	}
+	if b.Lowercase {
+		namespace, name = strings.ToLower(namespace), strings.ToLower(name)
+	}
+
+	purl := "pkg:" + b.Type + "/"
+	for _, segment := range strings.Split(namespace, "/") {
+		if segment != "" {
+			purl += escape(segment) + "/"
+		}
+	}
+	purl += escape(name)
+	if version != "" {
+		purl += "@" + escape(version)
+	}
+
+	var keys []string
+	for _, key := range b.Qualifiers {
+		if qualifiers[key] != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return purl
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		keys[i] = strings.ToLower(key) + "=" + escapeQualifier(qualifiers[key])
+	}
+	return purl + "?" + strings.Join(keys, "&")
+}
+
+// NPM builds the npm package urls, the scope of scoped packages being the namespace
+var NPM = Builder{Type: "npm"}
+
+// BuildNPM returns the `pkg:npm/[%40<scope>/]<name>@<version>` package url of an npm package, its scope, e.g. `@babel`
+// in `@babel/core`, split off the name
+func BuildNPM(name, version string) string {
+	namespace := ""
+	if parts := strings.SplitN(name, "/", 2); len(parts) == 2 && strings.HasPrefix(name, "@") {
+		namespace, name = parts[0], parts[1]
+	}
+	return NPM.Build(namespace, name, version, nil)
+}
+
+// escape percent-encodes a path segment of a package url, `@` separating the version
+func escape(segment string) string {
+	return strings.ReplaceAll(url.PathEscape(segment), "@", "%40")
+}
+
+// escapeQualifier percent-encodes a qualifier value, spaces as %20
+func escapeQualifier(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package purl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuild(t *testing.T) {
+	maven := Builder{Type: "maven", Qualifiers: []string{"type", "classifier", "repository_url"}}
+	npm := Builder{Type: "npm"}
+	hex := Builder{Type: "hex", Lowercase: true}
+
+	for expected, purl := range map[string]string{
+		"pkg:maven/junit/junit@4.13.2":                                                        maven.Build("junit", "junit", "4.13.2", nil),
+		"pkg:maven/io.netty/netty-transport@4.1.65.Final?classifier=linux-x86_64":             maven.Build("io.netty", "netty-transport", "4.1.65.Final", map[string]string{"classifier": "linux-x86_64"}),
+		"pkg:maven/com.example/app@1.0.0?classifier=tests&type=test-jar":                      maven.Build("com.example", "app", "1.0.0", map[string]string{"type": "test-jar", "classifier": "tests"}),
+		"pkg:maven/com.example/app@1.0.0":                                                     maven.Build("com.example", "app", "1.0.0", map[string]string{"classifier": "", "unknown": "value"}),
+		"pkg:maven/com.example/app?repository_url=https%3A%2F%2Frepo.example.com%2Fmaven%202": maven.Build("com.example", "app", "", map[string]string{"repository_url": "https://repo.example.com/maven 2"}),
+		"pkg:npm/%40babel/core@7.14.0":                                                        npm.Build("@babel", "core", "7.14.0", nil),
+		"pkg:npm/left-pad@1.3.0":                                                              npm.Build("", "left-pad", "1.3.0", nil),
+		"pkg:npm/name%20with%20space@1.0.0+build%2F1":                                         npm.Build("", "name with space", "1.0.0+build/1", nil),
+		"pkg:hex/acme/plug@1.11.1":                                                            hex.Build("Acme", "Plug", "1.11.1", nil),
+	} {
+		assert.Equal(t, expected, purl)
+	}
+	assert.Empty(t, npm.Build("@babel", "", "7.14.0", nil))
+}
+
+func TestBuildNPM(t *testing.T) {
+	assert.Equal(t, "pkg:npm/%40babel/core@7.14.0", BuildNPM("@babel/core", "7.14.0"))
+	assert.Equal(t, "pkg:npm/left-pad@1.3.0", BuildNPM("left-pad", "1.3.0"))
+	// only a leading `@` tells a scope
+	assert.Equal(t, "pkg:npm/a%2Fb@1.0.0", BuildNPM("a/b", "1.0.0"))
+	assert.Equal(t, "pkg:npm/%40babel", BuildNPM("@babel", ""))
+}
+
+func TestBuildOrdersQualifiersDeterministically(t *testing.T) {
+	qualifiers := map[string]string{"type": "zip", "classifier": "dist", "repository_url": "repo.example.com"}
+	maven := Builder{Type: "maven", Qualifiers: []string{"type", "repository_url", "classifier"}}
+	expected := "pkg:maven/com.example/app@1.0.0?classifier=dist&repository_url=repo.example.com&type=zip"
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, expected, maven.Build("com.example", "app", "1.0.0", qualifiers))
+	}
+
+	conan := Builder{Type: "conan", Qualifiers: []string{"user", "channel"}}
+	assert.Equal(t, "pkg:conan/zlib@1.2.11?channel=stable&user=acme", conan.Build("", "zlib", "1.2.11", map[string]string{"user": "acme", "channel": "stable"}))
+}